@@ -0,0 +1,794 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/application"
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/evaluation"
+	"product-catalog-sorting/internal/infrastructure/experiment"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// commandNames lists the CLI subcommands; any other arguments run the demonstration
+var commandNames = map[string]bool{
+	"extsort":    true,
+	"sort":       true,
+	"serve":      true,
+	"explain":    true,
+	"compare":    true,
+	"evaluate":   true,
+	"simulate":   true,
+	"experiment": true,
+	"bandit":     true,
+}
+
+// isCommand reports whether the arguments name a CLI subcommand
+func isCommand(args []string) bool {
+	return len(args) > 0 && commandNames[args[0]]
+}
+
+// runCommand dispatches a CLI subcommand
+func runCommand(ctx context.Context, app *application.Application, logger *zap.Logger, name string, args []string) error {
+	switch name {
+	case "extsort":
+		return runExternalSort(ctx, app, logger, args)
+	case "sort":
+		return runStreamSort(ctx, app, logger, args)
+	case "serve":
+		return runServer(ctx, app, logger, args)
+	case "explain":
+		return runExplain(ctx, app, args)
+	case "compare":
+		return runCompare(ctx, app, args)
+	case "evaluate":
+		return runEvaluate(ctx, app, args)
+	case "simulate":
+		return runSimulate(ctx, app, args)
+	case "experiment":
+		return runExperiment(ctx, logger, args)
+	case "bandit":
+		return runBandit(ctx, logger, args)
+	default:
+		return fmt.Errorf("unknown command: %s", name)
+	}
+}
+
+// runExternalSort sorts a JSONL catalog dump that may not fit in memory
+func runExternalSort(ctx context.Context, app *application.Application, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("extsort", flag.ContinueOnError)
+	input := flags.String("in", "-", "input JSONL file (- for stdin)")
+	output := flags.String("out", "-", "output JSONL file (- for stdout)")
+	strategy := flags.String("strategy", string(catalog.SortBySalesConversionRatio), "sort strategy")
+	memoryMB := flags.Int64("memory-mb", sorting.DefaultExternalSortMemoryBudget>>20, "memory budget in megabytes")
+	tempDir := flags.String("tmp", "", "directory for spilled runs (defaults to the OS temp dir)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *memoryMB <= 0 {
+		return fmt.Errorf("memory budget must be positive")
+	}
+
+	reader, err := openInput(*input)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	logger.Info("Starting external sort",
+		zap.String("strategy", *strategy),
+		zap.String("input", *input),
+		zap.Int64("memory_mb", *memoryMB),
+	)
+
+	_, err = app.ExternalSort(ctx, reader, writer, catalog.SortStrategy(*strategy), sorting.ExternalSortConfig{
+		MemoryBudget: *memoryMB << 20,
+		TempDir:      *tempDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// runStreamSort sorts a JSONL product stream, optionally keeping only the top K
+func runStreamSort(ctx context.Context, app *application.Application, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("sort", flag.ContinueOnError)
+	input := flags.String("in", "-", "input JSONL file (- for stdin)")
+	output := flags.String("out", "-", "output JSONL file (- for stdout)")
+	strategy := flags.String("strategy", string(catalog.SortBySalesConversionRatio), "sort strategy")
+	topK := flags.Int("top", 0, "emit only the first K products (0 for all)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	reader, err := openInput(*input)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	buffered := bufio.NewWriter(writer)
+	result, err := app.StreamSort(ctx, catalog.NewJSONProductIterator(reader), catalog.SortStrategy(*strategy),
+		catalog.StreamOptions{TopK: *topK}, catalog.NewNDJSONEmitter(buffered))
+	if err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	logger.Info("Stream sort completed",
+		zap.String("strategy", *strategy),
+		zap.Int("product_count", result.ProductCount),
+		zap.Int("emitted_count", result.EmittedCount),
+		zap.Duration("duration", result.ExecutionTime),
+	)
+
+	return writer.Close()
+}
+
+// runExplain sorts a JSONL catalog and prints why each product holds its position
+func runExplain(ctx context.Context, app *application.Application, args []string) error {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	input := flags.String("in", "-", "input JSONL file (- for stdin)")
+	strategy := flags.String("strategy", string(catalog.SortBySalesConversionRatio), "sort strategy")
+	topK := flags.Int("top", 10, "explain only the first K products (0 for all)")
+	productID := flags.Int64("product", 0, "explain only this product ID")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	products, err := readAllProducts(*input)
+	if err != nil {
+		return err
+	}
+
+	result, err := app.SortProductsWithOptions(ctx, products, catalog.SortStrategy(*strategy),
+		catalog.SortOptions{Explain: true})
+	if err != nil {
+		return err
+	}
+
+	names := make(map[catalog.ProductID]string, len(result.Products))
+	for _, product := range result.Products {
+		names[product.ID] = product.Name
+	}
+
+	found := false
+	for i, explanation := range result.Explanations {
+		if *productID != 0 && explanation.ProductID != catalog.ProductID(*productID) {
+			continue
+		}
+		if *productID == 0 && *topK > 0 && i >= *topK {
+			break
+		}
+		found = true
+		printExplanation(os.Stdout, explanation, names)
+	}
+
+	if *productID != 0 && !found {
+		return fmt.Errorf("product %d is not in the ranking", *productID)
+	}
+	return nil
+}
+
+// printExplanation renders one ranking explanation as indented text
+func printExplanation(w io.Writer, explanation catalog.RankExplanation, names map[catalog.ProductID]string) {
+	fmt.Fprintf(w, "#%d %s (id %d), sorter position #%d\n",
+		explanation.Position, names[explanation.ProductID], explanation.ProductID, explanation.SorterPosition)
+
+	if len(explanation.Criteria) > 0 {
+		values := make([]string, len(explanation.Criteria))
+		for i, criterion := range explanation.Criteria {
+			direction := "asc"
+			if criterion.Descending {
+				direction = "desc"
+			}
+			values[i] = fmt.Sprintf("%s=%s (%s)", criterion.Name, criterion.Value, direction)
+		}
+		fmt.Fprintf(w, "    keys:  %s\n", strings.Join(values, ", "))
+	}
+
+	for _, neighbour := range []struct {
+		label      string
+		comparison *catalog.NeighbourComparison
+	}{{"above", explanation.Previous}, {"below", explanation.Next}} {
+		if neighbour.comparison == nil {
+			continue
+		}
+		fmt.Fprintf(w, "    %s: %s (id %d) by %s", neighbour.label, names[neighbour.comparison.ProductID],
+			neighbour.comparison.ProductID, neighbour.comparison.DecidedBy)
+		if neighbour.comparison.Overridden {
+			fmt.Fprint(w, ", overridden after sorting")
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, adjustment := range explanation.Adjustments {
+		fmt.Fprintf(w, "    %s: #%d -> #%d", adjustment.Stage, adjustment.From, adjustment.To)
+		if len(adjustment.RuleIDs) > 0 {
+			fmt.Fprintf(w, " (rules: %s)", strings.Join(adjustment.RuleIDs, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// runCompare compares two strategies on one catalog, or one strategy across
+// two catalog snapshots, and prints the ranking diff
+func runCompare(ctx context.Context, app *application.Application, args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	input := flags.String("in", "-", "input JSONL file (- for stdin)")
+	against := flags.String("against", "", "second JSONL snapshot ranked with the candidate strategy")
+	base := flags.String("base", string(catalog.SortByPopularity), "base sort strategy")
+	candidate := flags.String("candidate", "", "candidate sort strategy (defaults to the base strategy)")
+	topK := flags.Int("k", catalog.DefaultDiffTopK, "top-K cut-off")
+	movers := flags.Int("movers", catalog.DefaultDiffMovers, "number of biggest movers to show")
+	asJSON := flags.Bool("json", false, "print the diff as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *candidate == "" {
+		*candidate = *base
+	}
+	if *against == "" && *candidate == *base {
+		return fmt.Errorf("compare needs a different candidate strategy or an -against snapshot")
+	}
+
+	products, err := readAllProducts(*input)
+	if err != nil {
+		return err
+	}
+	options := catalog.RankingDiffOptions{TopK: *topK, Movers: *movers}
+
+	var diff *catalog.RankingDiff
+	if *against == "" {
+		diff, err = app.CompareStrategies(ctx, products, catalog.SortStrategy(*base), catalog.SortStrategy(*candidate), options)
+	} else {
+		diff, err = compareSnapshots(ctx, app, products, *against, catalog.SortStrategy(*base), catalog.SortStrategy(*candidate), options)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+	printRankingDiff(os.Stdout, diff)
+	return nil
+}
+
+// compareSnapshots ranks two catalog snapshots and compares the rankings
+func compareSnapshots(ctx context.Context, app *application.Application, products []catalog.Product, againstPath string, base, candidate catalog.SortStrategy, options catalog.RankingDiffOptions) (*catalog.RankingDiff, error) {
+	against, err := readAllProducts(againstPath)
+	if err != nil {
+		return nil, err
+	}
+
+	baseResult, err := app.SortProducts(ctx, products, base)
+	if err != nil {
+		return nil, err
+	}
+	candidateResult, err := app.SortProducts(ctx, against, candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.CompareRankings(baseResult, candidateResult, options)
+}
+
+// printRankingDiff renders a ranking diff as text
+func printRankingDiff(w io.Writer, diff *catalog.RankingDiff) {
+	fmt.Fprintf(w, "%s -> %s\n", diff.BaseStrategy, diff.CandidateStrategy)
+	fmt.Fprintf(w, "  products in both: %d\n", diff.CommonProducts)
+	fmt.Fprintf(w, "  kendall tau:      %.4f\n", diff.KendallTau)
+	fmt.Fprintf(w, "  spearman rho:     %.4f\n", diff.SpearmanRho)
+	fmt.Fprintf(w, "  overlap@%d:       %.2f\n", diff.TopK, diff.OverlapAtK)
+	fmt.Fprintf(w, "  entered top %d:   %v\n", diff.TopK, diff.EnteredTopK)
+	fmt.Fprintf(w, "  exited top %d:    %v\n", diff.TopK, diff.ExitedTopK)
+
+	if len(diff.BiggestMovers) > 0 {
+		fmt.Fprintln(w, "  biggest movers:")
+		for _, mover := range diff.BiggestMovers {
+			fmt.Fprintf(w, "    id %d: #%d -> #%d (%+d)\n", mover.ProductID, mover.Before, mover.After, mover.Delta)
+		}
+	}
+}
+
+// runEvaluate scores strategies against relevance judgments and prints a leaderboard
+func runEvaluate(ctx context.Context, app *application.Application, args []string) error {
+	flags := flag.NewFlagSet("evaluate", flag.ContinueOnError)
+	input := flags.String("in", "-", "input JSONL file (- for stdin)")
+	judgmentsPath := flags.String("judgments", "", "relevance judgments JSON file")
+	strategyList := flags.String("strategies", "", "comma-separated strategies (defaults to all supported)")
+	k := flags.Int("k", catalog.DefaultEvaluationK, "metric cut-off")
+	reportPath := flags.String("report", "", "write the report as JSON to this file")
+	baselinePath := flags.String("baseline", "", "earlier report to compare the leaderboard with")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *judgmentsPath == "" {
+		return fmt.Errorf("evaluate needs a -judgments file")
+	}
+
+	judgments, err := evaluation.LoadJudgments(*judgmentsPath)
+	if err != nil {
+		return err
+	}
+	products, err := readAllProducts(*input)
+	if err != nil {
+		return err
+	}
+
+	strategies := app.GetSupportedStrategies()
+	if *strategyList != "" {
+		strategies = nil
+		for _, name := range strings.Split(*strategyList, ",") {
+			strategies = append(strategies, catalog.SortStrategy(strings.TrimSpace(name)))
+		}
+	}
+
+	report, err := app.EvaluateStrategies(ctx, products, strategies, judgments, *k)
+	if err != nil {
+		return err
+	}
+
+	var baseline *catalog.EvaluationReport
+	if *baselinePath != "" {
+		if baseline, err = evaluation.LoadReport(*baselinePath); err != nil {
+			return err
+		}
+		if !report.Comparable(baseline) {
+			return fmt.Errorf("baseline report was evaluated against a different catalog, judgments or cut-off")
+		}
+	}
+	if *reportPath != "" {
+		if err := evaluation.SaveReport(*reportPath, report); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printLeaderboard(os.Stdout, report, baseline)
+	return nil
+}
+
+// printLeaderboard renders an evaluation report as text, with the NDCG change
+// against a baseline report when one is given
+func printLeaderboard(w io.Writer, report, baseline *catalog.EvaluationReport) {
+	fmt.Fprintf(w, "%d queries, k=%d\n", report.Queries, report.K)
+	fmt.Fprintf(w, "%4s  %-32s %8s %8s %8s %8s\n", "rank", "strategy", "ndcg", "map", "mrr", "p@k")
+	for _, entry := range report.Leaderboard {
+		fmt.Fprintf(w, "%4d  %-32s %8.4f %8.4f %8.4f %8.4f", entry.Rank, entry.Strategy,
+			entry.NDCG, entry.MAP, entry.MRR, entry.Precision)
+		if baseline != nil {
+			if previous, ok := baseline.Entry(entry.Strategy); ok {
+				fmt.Fprintf(w, "  (ndcg %+.4f, was #%d)", entry.NDCG-previous.NDCG, previous.Rank)
+			} else {
+				fmt.Fprint(w, "  (new)")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// runSimulate estimates the clicks, sales and revenue of strategies under a
+// position-based click model and prints them best first
+func runSimulate(ctx context.Context, app *application.Application, args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	input := flags.String("in", "-", "input JSONL file (- for stdin)")
+	strategyList := flags.String("strategies", "", "comma-separated strategies, baseline first (defaults to all supported)")
+	k := flags.Int("k", catalog.DefaultSimulationK, "number of slots shoppers examine")
+	runs := flags.Int("runs", catalog.DefaultSimulationRuns, "Monte Carlo runs")
+	sessions := flags.Int("sessions", catalog.DefaultSimulationSessions, "shopping sessions per run")
+	seed := flags.Int64("seed", 1, "random seed")
+	decay := flags.Float64("decay", catalog.DefaultExaminationDecay, "examination decay: slot i is examined with probability 1/i^decay")
+	examination := flags.String("examination", "", "comma-separated examination probabilities per slot (overrides -decay)")
+	click := flags.Float64("click", catalog.DefaultClickProbability, "probability an examined product is clicked")
+	prior := flags.Float64("prior", catalog.DefaultConversionPriorViews, "views of catalog-average conversion added to each product (0 uses raw ratios)")
+	confidence := flags.Float64("confidence", catalog.DefaultSimulationConfidence, "confidence level of the intervals")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options := catalog.SimulationOptions{
+		K:                    *k,
+		Runs:                 *runs,
+		Sessions:             *sessions,
+		Seed:                 *seed,
+		Examination:          catalog.ExaminationModel{Decay: decay, ClickProbability: click},
+		ConversionPriorViews: prior,
+		ConfidenceLevel:      *confidence,
+	}
+	if *examination != "" {
+		for _, value := range strings.Split(*examination, ",") {
+			probability, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return fmt.Errorf("invalid examination probability %q: %w", value, err)
+			}
+			options.Examination.Probabilities = append(options.Examination.Probabilities, probability)
+		}
+	}
+
+	products, err := readAllProducts(*input)
+	if err != nil {
+		return err
+	}
+
+	strategies := app.GetSupportedStrategies()
+	if *strategyList != "" {
+		strategies = nil
+		for _, name := range strings.Split(*strategyList, ",") {
+			strategies = append(strategies, catalog.SortStrategy(strings.TrimSpace(name)))
+		}
+	}
+
+	report, err := app.SimulateStrategies(ctx, products, strategies, options)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printSimulation(os.Stdout, report)
+	return nil
+}
+
+// printSimulation renders a simulation report as text
+func printSimulation(w io.Writer, report *catalog.SimulationReport) {
+	fmt.Fprintf(w, "k=%d, %d runs of %d sessions, %.0f%% intervals, baseline %s\n",
+		report.K, report.Runs, report.Sessions, report.ConfidenceLevel*100, report.Baseline)
+	fmt.Fprintf(w, "%4s  %-32s %10s %10s %24s %24s\n", "rank", "strategy", "clicks", "sales", "revenue", "vs baseline")
+	for _, entry := range report.Strategies {
+		revenue := fmt.Sprintf("%.2f [%.2f, %.2f]", entry.Revenue.Mean,
+			entry.Revenue.Interval.Lower, entry.Revenue.Interval.Upper)
+		difference := fmt.Sprintf("%+.2f [%+.2f, %+.2f]", entry.RevenueDifference.Mean,
+			entry.RevenueDifference.Interval.Lower, entry.RevenueDifference.Interval.Upper)
+		fmt.Fprintf(w, "%4d  %-32s %10.1f %10.1f %24s %24s %s\n", entry.Rank, entry.Strategy,
+			entry.Clicks.Mean, entry.Sales.Mean, revenue, difference, entry.Currency)
+	}
+}
+
+// runExperiment manages the experiments file: list (default), define, delete,
+// assign and report. It goes through the application like runBandit does.
+func runExperiment(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("experiment", flag.ContinueOnError)
+	storePath := flags.String("store", "", "experiments JSON file")
+	definition := flags.String("define", "", "JSON file with one experiment to create or replace")
+	name := flags.String("name", "", "experiment name (for delete and assign)")
+	unit := flags.String("unit", "", "user or session ID (for assign)")
+	eventsPath := flags.String("events", "-", "NDJSON experiment events (for report, - for stdin)")
+	confidence := flags.Float64("confidence", catalog.DefaultConfidenceLevel, "confidence level (for report)")
+	mde := flags.Float64("mde", catalog.DefaultMinimumDetectableEffect, "minimum detectable relative lift (for report)")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *storePath == "" {
+		return fmt.Errorf("experiment needs a -store file")
+	}
+
+	app, err := application.New(application.Config{Logger: logger, Context: ctx, ExperimentsFile: *storePath})
+	if err != nil {
+		return err
+	}
+
+	action := flags.Arg(0)
+	switch action {
+	case "", "list":
+		experiments, err := app.ListExperiments(ctx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, definition := range experiments {
+			status := "stopped"
+			if definition.IsActiveAt(now) {
+				status = "running"
+			}
+			fmt.Printf("%s (%s)\n", definition.Name, status)
+			for _, variant := range definition.Variants {
+				fmt.Printf("  %-20s %-32s %6.2f%%\n", variant.Name, variant.Strategy, variant.Traffic)
+			}
+		}
+		return nil
+	case "define":
+		if *definition == "" {
+			return fmt.Errorf("define needs a -define file")
+		}
+		data, err := os.ReadFile(*definition)
+		if err != nil {
+			return fmt.Errorf("failed to read experiment definition: %w", err)
+		}
+		var defined catalog.Experiment
+		if err := json.Unmarshal(data, &defined); err != nil {
+			return fmt.Errorf("failed to parse experiment definition: %w", err)
+		}
+		return app.SaveExperiment(ctx, defined)
+	case "delete":
+		return app.DeleteExperiment(ctx, *name)
+	case "assign":
+		assignment, err := app.AssignExperiment(ctx, *name, *unit)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(assignment)
+	case "report":
+		report, err := reportExperiment(ctx, app, *name, *eventsPath, catalog.ReportOptions{ConfidenceLevel: *confidence, MinimumDetectableEffect: *mde})
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		}
+		printExperimentReport(os.Stdout, report)
+		return nil
+	default:
+		return fmt.Errorf("unknown experiment action: %s", action)
+	}
+}
+
+// runBandit manages the bandits file: list (default), create, select and
+// reward. It goes through the application like the server does; the file
+// must not be in use by a running server at the same time.
+func runBandit(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("bandit", flag.ContinueOnError)
+	storePath := flags.String("store", "", "bandit state JSON file")
+	definition := flags.String("define", "", "JSON file with a bandit configuration (for create)")
+	name := flags.String("name", "", "bandit name (for select and reward)")
+	strategy := flags.String("strategy", "", "strategy that served the request (for reward)")
+	reward := flags.Float64("reward", 0, "reward between 0 and 1 (for reward)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *storePath == "" {
+		return fmt.Errorf("bandit needs a -store file")
+	}
+
+	app, err := application.New(application.Config{Logger: logger, Context: ctx, BanditsFile: *storePath})
+	if err != nil {
+		return err
+	}
+
+	action := flags.Arg(0)
+	switch action {
+	case "", "list":
+		states, err := app.ListBandits(ctx)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			fmt.Printf("%s (%s)\n", state.Config.Name, state.Config.Policy)
+			for _, arm := range state.Arms {
+				fmt.Printf("  %-32s pulls %8d  rewards %8d  mean %.4f\n", arm.Strategy, arm.Pulls, arm.Rewards, arm.MeanReward())
+			}
+		}
+		return nil
+	case "create":
+		if *definition == "" {
+			return fmt.Errorf("create needs a -define file")
+		}
+		data, err := os.ReadFile(*definition)
+		if err != nil {
+			return fmt.Errorf("failed to read bandit configuration: %w", err)
+		}
+		var config catalog.BanditConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse bandit configuration: %w", err)
+		}
+		return app.CreateBandit(ctx, config)
+	case "select":
+		selection, err := app.SelectBandit(ctx, *name)
+		if err != nil {
+			return err
+		}
+		fmt.Println(selection.Strategy)
+		return nil
+	case "reward":
+		return app.RecordBanditReward(ctx, catalog.BanditReward{
+			Bandit:   *name,
+			Strategy: catalog.SortStrategy(*strategy),
+			Reward:   *reward,
+		})
+	default:
+		return fmt.Errorf("unknown bandit action: %s", action)
+	}
+}
+
+// reportExperiment aggregates an event log and reports on the experiment
+func reportExperiment(ctx context.Context, app *application.Application, name, eventsPath string, options catalog.ReportOptions) (*catalog.ExperimentReport, error) {
+	reader, err := openInput(eventsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	events, err := experiment.ReadEvents(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []catalog.ExperimentEvent
+	for _, event := range events {
+		if event.Experiment == name {
+			matched = append(matched, event)
+		}
+	}
+	if err := app.RecordExperimentEvents(ctx, matched); err != nil {
+		return nil, err
+	}
+
+	return app.ExperimentReport(ctx, name, options)
+}
+
+// printExperimentReport renders an experiment report as text
+func printExperimentReport(w io.Writer, report *catalog.ExperimentReport) {
+	fmt.Fprintf(w, "%s (control: %s, %.0f%% confidence)\n", report.Experiment, report.Control, report.ConfidenceLevel*100)
+	fmt.Fprintf(w, "  %-16s %8s %10s %8s %8s %18s %10s\n", "variant", "units", "impr", "ctr", "conv", "conv interval", "rev/impr")
+	for _, variant := range report.Variants {
+		fmt.Fprintf(w, "  %-16s %8d %10d %7.2f%% %7.2f%%   [%6.2f%%, %6.2f%%] %10.4f\n",
+			variant.Variant, variant.Units, variant.Impressions, variant.ClickThroughRate*100, variant.ConversionRate*100,
+			variant.ConversionInterval.Lower*100, variant.ConversionInterval.Upper*100, variant.RevenuePerImpression)
+	}
+	for _, comparison := range report.Comparisons {
+		verdict := "not significant"
+		if comparison.Significant {
+			verdict = "significant"
+		}
+		fmt.Fprintf(w, "  %s vs %s: lift %+.2f%%, z=%.3f, p=%.4f (%s)\n",
+			comparison.Variant, report.Control, comparison.Lift*100, comparison.ZScore, comparison.PValue, verdict)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "  warning: %s\n", warning)
+	}
+}
+
+// readAllProducts loads a JSONL catalog into memory
+func readAllProducts(path string) ([]catalog.Product, error) {
+	reader, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var products []catalog.Product
+	source := catalog.NewJSONProductIterator(reader)
+	for source.Next() {
+		products = append(products, source.Product())
+	}
+	if err := source.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// runServer serves the HTTP API until the context is cancelled
+func runServer(ctx context.Context, app *application.Application, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "listen address")
+	rates := flags.String("rates", "", "exchange rates JSON file (enables currency conversion)")
+	promotions := flags.String("promotions", "", "promotions JSON file (enables effective prices)")
+	rules := flags.String("rules", "", "merchandising rules JSON file")
+	experiments := flags.String("experiments", "", "experiments JSON file (enables /v1/experiments)")
+	events := flags.String("events", "", "experiment events NDJSON log (keeps outcomes across restarts)")
+	bandits := flags.String("bandits", "", "bandit state JSON file (enables /v1/bandits); owned by the server while it runs")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// The server owns its stores, so it gets an application configured with them
+	if *rates != "" || *promotions != "" || *rules != "" || *experiments != "" || *events != "" || *bandits != "" {
+		var err error
+		app, err = application.New(application.Config{
+			Logger:                 logger,
+			Context:                ctx,
+			ExchangeRatesFile:      *rates,
+			PromotionsFile:         *promotions,
+			MerchandisingRulesFile: *rules,
+			ExperimentsFile:        *experiments,
+			ExperimentEventsFile:   *events,
+			BanditsFile:            *bandits,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize server: %w", err)
+		}
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           httpapi.NewHandler(app.CatalogService(), logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("HTTP server shutdown failed", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Starting HTTP server", zap.String("addr", *addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+
+	return nil
+}
+
+// openInput opens a file for reading, treating "-" as stdin
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input: %w", err)
+	}
+	return file, nil
+}
+
+// openOutput opens a file for writing, treating "-" as stdout
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output: %w", err)
+	}
+	return file, nil
+}
+
+// nopWriteCloser keeps stdout open when an output is closed
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -35,8 +35,16 @@ func main() {
 		GoVersion:  GoVersion,
 	}
 
+	// Subcommands stream their results to stdout, so their logs go to stderr;
+	// the demonstration keeps logging to stdout
+	command := isCommand(os.Args[1:])
+	logOutput := "stdout"
+	if command {
+		logOutput = "stderr"
+	}
+
 	// Initialize logger
-	logger := initializeLogger()
+	logger := initializeLogger(logOutput)
 	defer func() {
 		if err := logger.Sync(); err != nil {
 			// Ignore sync errors on stdout/stderr
@@ -70,6 +78,15 @@ func main() {
 		logger.Fatal("Failed to initialize application", zap.Error(err))
 	}
 
+	// Run the requested subcommand instead of the demonstration
+	if command {
+		if err := runCommand(ctx, app, logger, os.Args[1], os.Args[2:]); err != nil {
+			logger.Error("Command failed", zap.String("command", os.Args[1]), zap.Error(err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Run demonstration
 	if err := runDemonstration(ctx, app, logger); err != nil {
 		logger.Error("Demonstration failed", zap.Error(err))
@@ -80,10 +97,10 @@ func main() {
 }
 
 // initializeLogger creates a production-ready structured logger
-func initializeLogger() *zap.Logger {
+func initializeLogger(outputPath string) *zap.Logger {
 	config := zap.NewProductionConfig()
 	config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	config.OutputPaths = []string{"stdout"}
+	config.OutputPaths = []string{outputPath}
 	config.ErrorOutputPaths = []string{"stderr"}
 	config.EncoderConfig.TimeKey = "timestamp"
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -149,7 +166,7 @@ func loadSampleProducts() ([]catalog.Product, error) {
 		{
 			ID:         catalog.ProductID(1),
 			Name:       "Alabaster Table",
-			Price:      catalog.Price(12.99),
+			Price:      catalog.NewMoney(1299, catalog.USD),
 			CreatedAt:  parseDate("2019-01-04"),
 			SalesCount: 32,
 			ViewsCount: 730,
@@ -157,7 +174,7 @@ func loadSampleProducts() ([]catalog.Product, error) {
 		{
 			ID:         catalog.ProductID(2),
 			Name:       "Zebra Table",
-			Price:      catalog.Price(44.49),
+			Price:      catalog.NewMoney(4449, catalog.USD),
 			CreatedAt:  parseDate("2012-01-04"),
 			SalesCount: 301,
 			ViewsCount: 3279,
@@ -165,7 +182,7 @@ func loadSampleProducts() ([]catalog.Product, error) {
 		{
 			ID:         catalog.ProductID(3),
 			Name:       "Coffee Table",
-			Price:      catalog.Price(10.00),
+			Price:      catalog.NewMoney(1000, catalog.USD),
 			CreatedAt:  parseDate("2014-05-28"),
 			SalesCount: 1048,
 			ViewsCount: 20123,
@@ -223,8 +240,8 @@ func demonstrateSortingStrategies(ctx context.Context, app *application.Applicat
 		// Display all results for the 3 products
 		fmt.Printf("\n🎯 %s:\n", strategy.Description())
 		for i, product := range result.Products {
-			fmt.Printf("  %d. %s - $%.2f (Sales: %d, Views: %d, Ratio: %.4f, Revenue: $%.2f)\n",
-				i+1, product.Name, float64(product.Price), product.SalesCount, product.ViewsCount,
+			fmt.Printf("  %d. %s - %s (Sales: %d, Views: %d, Ratio: %.4f, Revenue: %s)\n",
+				i+1, product.Name, formatPrice(result, product), product.SalesCount, product.ViewsCount,
 				product.SalesConversionRatio(), product.RevenueGenerated())
 		}
 	}
@@ -259,14 +276,24 @@ func demonstrateABTesting(ctx context.Context, app *application.Application, pro
 	for strategy, result := range results.Results {
 		if len(result.Products) > 0 {
 			top := result.Products[0]
-			fmt.Printf("  %s: %s - $%.2f\n", 
-				strategy.Description(), top.Name, float64(top.Price))
+			fmt.Printf("  %s: %s - %s\n", 
+				strategy.Description(), top.Name, formatPrice(result, top))
 		}
 	}
 
 	return nil
 }
 
+// formatPrice renders the price a product sold for when the result was
+// sorted, showing the list price alongside any promotional price
+func formatPrice(result *catalog.SortResult, product catalog.Product) string {
+	price := result.EffectivePrice(product)
+	if price == product.Price {
+		return price.String()
+	}
+	return fmt.Sprintf("%s (was %s)", price, product.Price)
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/stretchr/testify v1.8.4
 	go.uber.org/zap v1.26.0
+	golang.org/x/text v0.14.0
 )
 
 require (
@@ -2,10 +2,17 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"go.uber.org/zap"
 
 	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/bandit"
+	"product-catalog-sorting/internal/infrastructure/exchange"
+	"product-catalog-sorting/internal/infrastructure/experiment"
+	"product-catalog-sorting/internal/infrastructure/merchandising"
+	"product-catalog-sorting/internal/infrastructure/promotion"
 	"product-catalog-sorting/internal/infrastructure/sorting"
 )
 
@@ -13,24 +20,114 @@ import (
 type Config struct {
 	Logger  *zap.Logger
 	Context context.Context
+
+	// ExchangeRatesFile is an optional JSON rates table enabling multi-currency sorting
+	ExchangeRatesFile string
+
+	// PromotionsFile is an optional JSON list of promotions; when set, price
+	// and revenue sorts use effective prices
+	PromotionsFile string
+
+	// MerchandisingRulesFile is an optional JSON list of merchandising rules
+	// applied after every sort
+	MerchandisingRulesFile string
+
+	// ExperimentsFile is an optional JSON store of A/B experiments; it is
+	// created on the first save when missing
+	ExperimentsFile string
+
+	// ExperimentEventsFile is an optional NDJSON log of experiment events;
+	// without it experiment outcomes are kept in memory only and are lost on
+	// restart
+	ExperimentEventsFile string
+
+	// BanditsFile is an optional JSON store of multi-armed bandit state; it
+	// is created on the first save when missing. Only one process may use
+	// the file at a time.
+	BanditsFile string
+
+	// RatingPrior overrides the prior of the top rated strategy
+	RatingPrior *catalog.RatingPrior
 }
 
 // Application represents the main application
 type Application struct {
 	catalogService catalog.Service
+	sorterFactory  catalog.SorterFactory
 	logger         *zap.Logger
 }
 
 // New creates a new application instance
 func New(config Config) (*Application, error) {
 	// Create sorter factory
-	sorterFactory := sorting.NewSorterFactory()
+	var factoryOptions []sorting.FactoryOption
+	if config.RatingPrior != nil {
+		if err := config.RatingPrior.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rating prior: %w", err)
+		}
+		factoryOptions = append(factoryOptions, sorting.WithRatingPrior(*config.RatingPrior))
+	}
+	sorterFactory := sorting.NewSorterFactory(factoryOptions...)
+
+	// Load exchange rates when multi-currency sorting is configured
+	var options []catalog.ServiceOption
+	if config.ExchangeRatesFile != "" {
+		rates, err := exchange.NewFileRateProvider(config.ExchangeRatesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load exchange rates: %w", err)
+		}
+		options = append(options, catalog.WithExchangeRates(rates))
+	}
+
+	// Load promotions when effective-price sorting is configured
+	if config.PromotionsFile != "" {
+		promotions, err := promotion.NewFileProvider(config.PromotionsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load promotions: %w", err)
+		}
+		options = append(options, catalog.WithPromotions(promotions))
+	}
+
+	// Load merchandising rules when configured
+	if config.MerchandisingRulesFile != "" {
+		rules, err := merchandising.NewFileProvider(config.MerchandisingRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load merchandising rules: %w", err)
+		}
+		options = append(options, catalog.WithMerchandisingRules(rules))
+	}
+
+	// Open the experiment store when A/B testing is configured
+	if config.ExperimentsFile != "" {
+		experiments, err := experiment.NewFileStore(config.ExperimentsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load experiments: %w", err)
+		}
+		options = append(options, catalog.WithExperiments(experiments))
+	}
+	if config.ExperimentEventsFile != "" {
+		events, err := experiment.NewEventLog(config.ExperimentEventsFile, config.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open experiment events: %w", err)
+		}
+		options = append(options, catalog.WithExperimentEventLog(events))
+	}
+
+	// Open the bandit store when bandit strategy selection is configured
+	if config.BanditsFile != "" {
+		bandits, err := bandit.NewFileStore(config.BanditsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bandits: %w", err)
+		}
+		options = append(options, catalog.WithBandits(bandits))
+	}
 
 	// Create catalog service
-	catalogService := catalog.NewService(sorterFactory, config.Logger)
+	catalogService := catalog.NewService(sorterFactory, config.Logger, options...)
 
 	return &Application{
 		catalogService: catalogService,
+		sorterFactory:  sorterFactory,
 		logger:         config.Logger,
 	}, nil
 }
@@ -41,12 +138,115 @@ func (a *Application) SortProducts(ctx context.Context, products []catalog.Produ
 	return a.catalogService.SortProducts(ctx, productCollection, strategy)
 }
 
+// SortProductsInCurrency sorts products comparing prices and revenue in the given currency
+func (a *Application) SortProductsInCurrency(ctx context.Context, products []catalog.Product, strategy catalog.SortStrategy, currency catalog.Currency) (*catalog.SortResult, error) {
+	productCollection := catalog.ProductCollection(products)
+	return a.catalogService.SortProductsInCurrency(ctx, productCollection, strategy, currency)
+}
+
+// SortProductsWithOptions sorts products with optional currency conversion and stock demotion
+func (a *Application) SortProductsWithOptions(ctx context.Context, products []catalog.Product, strategy catalog.SortStrategy, options catalog.SortOptions) (*catalog.SortResult, error) {
+	productCollection := catalog.ProductCollection(products)
+	return a.catalogService.SortProductsWithOptions(ctx, productCollection, strategy, options)
+}
+
 // BatchSort sorts products using multiple strategies
 func (a *Application) BatchSort(ctx context.Context, products []catalog.Product, strategies catalog.SortStrategySet) (*catalog.BatchSortResult, error) {
 	productCollection := catalog.ProductCollection(products)
 	return a.catalogService.BatchSort(ctx, productCollection, strategies)
 }
 
+// FuseStrategies merges the rankings of several strategies into one
+func (a *Application) FuseStrategies(ctx context.Context, products []catalog.Product, strategies catalog.SortStrategySet, options catalog.FusionOptions) (*catalog.SortResult, error) {
+	return a.catalogService.FuseStrategies(ctx, catalog.ProductCollection(products), strategies, options)
+}
+
+// CompareStrategies reports how the candidate strategy's ranking differs from the base's
+func (a *Application) CompareStrategies(ctx context.Context, products []catalog.Product, base, candidate catalog.SortStrategy, options catalog.RankingDiffOptions) (*catalog.RankingDiff, error) {
+	return a.catalogService.CompareStrategies(ctx, catalog.ProductCollection(products), base, candidate, options)
+}
+
+// EvaluateStrategies scores strategies against relevance judgments
+func (a *Application) EvaluateStrategies(ctx context.Context, products []catalog.Product, strategies catalog.SortStrategySet, judgments catalog.Judgments, k int) (*catalog.EvaluationReport, error) {
+	return a.catalogService.EvaluateStrategies(ctx, catalog.ProductCollection(products), strategies, judgments, k)
+}
+
+// SimulateStrategies estimates the revenue each strategy's ranking would produce
+func (a *Application) SimulateStrategies(ctx context.Context, products []catalog.Product, strategies catalog.SortStrategySet, options catalog.SimulationOptions) (*catalog.SimulationReport, error) {
+	return a.catalogService.SimulateStrategies(ctx, catalog.ProductCollection(products), strategies, options)
+}
+
+// SaveExperiment validates and stores an experiment, replacing one with the same name
+func (a *Application) SaveExperiment(ctx context.Context, experiment catalog.Experiment) error {
+	return a.catalogService.SaveExperiment(ctx, experiment)
+}
+
+// ListExperiments returns every experiment, running or not, ordered by name
+func (a *Application) ListExperiments(ctx context.Context) ([]catalog.Experiment, error) {
+	return a.catalogService.ListExperiments(ctx)
+}
+
+// DeleteExperiment removes an experiment
+func (a *Application) DeleteExperiment(ctx context.Context, experiment string) error {
+	return a.catalogService.DeleteExperiment(ctx, experiment)
+}
+
+// AssignExperiment returns the experiment variant a user or session is served
+func (a *Application) AssignExperiment(ctx context.Context, experiment, unitID string) (*catalog.ExperimentAssignment, error) {
+	return a.catalogService.AssignExperiment(ctx, experiment, unitID)
+}
+
+// SortForExperiment sorts products with the strategy of the unit's experiment variant
+func (a *Application) SortForExperiment(ctx context.Context, products []catalog.Product, experiment, unitID string, options catalog.SortOptions) (*catalog.SortResult, error) {
+	return a.catalogService.SortForExperiment(ctx, catalog.ProductCollection(products), experiment, unitID, options)
+}
+
+// RecordExperimentEvents ingests impression, click and purchase events of experiment variants
+func (a *Application) RecordExperimentEvents(ctx context.Context, events []catalog.ExperimentEvent) error {
+	return a.catalogService.RecordExperimentEvents(ctx, events)
+}
+
+// ExperimentReport reports per-variant outcomes and significance tests for an experiment
+func (a *Application) ExperimentReport(ctx context.Context, experiment string, options catalog.ReportOptions) (*catalog.ExperimentReport, error) {
+	return a.catalogService.ExperimentReport(ctx, experiment, options)
+}
+
+// CreateBandit stores a new multi-armed bandit over sort strategies
+func (a *Application) CreateBandit(ctx context.Context, config catalog.BanditConfig) error {
+	return a.catalogService.CreateBandit(ctx, config)
+}
+
+// ListBandits returns the state of every bandit ordered by name
+func (a *Application) ListBandits(ctx context.Context) ([]catalog.BanditState, error) {
+	return a.catalogService.ListBandits(ctx)
+}
+
+// SelectBandit asks a bandit for the strategy to serve the next request with
+func (a *Application) SelectBandit(ctx context.Context, bandit string) (*catalog.BanditSelection, error) {
+	return a.catalogService.SelectBandit(ctx, bandit)
+}
+
+// SortWithBandit sorts products with the strategy a bandit picks for this request
+func (a *Application) SortWithBandit(ctx context.Context, products []catalog.Product, bandit string, options catalog.SortOptions) (*catalog.SortResult, error) {
+	return a.catalogService.SortWithBandit(ctx, catalog.ProductCollection(products), bandit, options)
+}
+
+// RecordBanditReward updates a bandit with the reward of a request it served
+func (a *Application) RecordBanditReward(ctx context.Context, reward catalog.BanditReward) error {
+	return a.catalogService.RecordBanditReward(ctx, reward)
+}
+
+// SortPage sorts products and returns the page following the request cursor
+func (a *Application) SortPage(ctx context.Context, products []catalog.Product, strategy catalog.SortStrategy, page catalog.PageRequest) (*catalog.PageResult, error) {
+	productCollection := catalog.ProductCollection(products)
+	return a.catalogService.SortPage(ctx, productCollection, strategy, page)
+}
+
+// StreamSort sorts products from an iterator, emitting them one at a time
+func (a *Application) StreamSort(ctx context.Context, source catalog.ProductIterator, strategy catalog.SortStrategy, options catalog.StreamOptions, emit catalog.ProductEmitter) (*catalog.StreamSortResult, error) {
+	return a.catalogService.StreamSort(ctx, source, strategy, options, emit)
+}
+
 // GetSupportedStrategies returns all supported sorting strategies
 func (a *Application) GetSupportedStrategies() catalog.SortStrategySet {
 	return a.catalogService.GetSupportedStrategies()
@@ -57,3 +257,36 @@ func (a *Application) ValidateProducts(ctx context.Context, products []catalog.P
 	productCollection := catalog.ProductCollection(products)
 	return a.catalogService.ValidateProducts(ctx, productCollection)
 }
+
+// ExternalSort sorts a JSONL product stream that may be larger than memory
+func (a *Application) ExternalSort(ctx context.Context, r io.Reader, w io.Writer, strategy catalog.SortStrategy, config sorting.ExternalSortConfig) (*sorting.ExternalSortStats, error) {
+	sorter, err := a.sorterFactory.CreateSorter(strategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sorter for strategy %s: %w", strategy, err)
+	}
+
+	externalSorter, err := sorting.NewExternalSorter(sorter, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create external sorter: %w", err)
+	}
+
+	stats, err := externalSorter.Sort(ctx, r, w)
+	if err != nil {
+		return nil, fmt.Errorf("external sort failed for strategy %s: %w", strategy, err)
+	}
+
+	a.logger.Info("External sort completed",
+		zap.String("strategy", string(strategy)),
+		zap.Int("product_count", stats.ProductCount),
+		zap.Int("run_count", stats.RunCount),
+		zap.Int("merge_passes", stats.MergePasses),
+	)
+
+	return stats, nil
+}
+
+// CatalogService returns the service the application delegates to, for
+// adapters such as the HTTP API that serve it directly
+func (a *Application) CatalogService() catalog.Service {
+	return a.catalogService
+}
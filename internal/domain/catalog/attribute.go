@@ -0,0 +1,397 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrMissingAttribute is returned when an attribute sort requires a value a product lacks
+var ErrMissingAttribute = errors.New("missing attribute")
+
+// AttributeType is the type of a custom attribute value
+type AttributeType string
+
+// Attribute types
+const (
+	AttributeString AttributeType = "string"
+	AttributeNumber AttributeType = "number"
+	AttributeBool   AttributeType = "bool"
+	AttributeTime   AttributeType = "time"
+)
+
+// IsValid checks if the attribute type is known
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeString, AttributeNumber, AttributeBool, AttributeTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// AttributeValue is a typed custom attribute value. In JSON it is encoded as
+// {"type": "number", "value": 1.5}; bare strings, numbers and booleans are
+// also accepted when decoding.
+type AttributeValue struct {
+	Type   AttributeType
+	String string
+	Number float64
+	Bool   bool
+	Time   time.Time
+}
+
+// StringAttribute creates a string attribute value
+func StringAttribute(value string) AttributeValue {
+	return AttributeValue{Type: AttributeString, String: value}
+}
+
+// NumberAttribute creates a number attribute value
+func NumberAttribute(value float64) AttributeValue {
+	return AttributeValue{Type: AttributeNumber, Number: value}
+}
+
+// BoolAttribute creates a boolean attribute value
+func BoolAttribute(value bool) AttributeValue {
+	return AttributeValue{Type: AttributeBool, Bool: value}
+}
+
+// TimeAttribute creates a time attribute value
+func TimeAttribute(value time.Time) AttributeValue {
+	return AttributeValue{Type: AttributeTime, Time: value}
+}
+
+// Validate ensures the value is well formed
+func (v AttributeValue) Validate() error {
+	if !v.Type.IsValid() {
+		return fmt.Errorf("invalid attribute type: %q", v.Type)
+	}
+	if v.Type == AttributeNumber && (math.IsNaN(v.Number) || math.IsInf(v.Number, 0)) {
+		return fmt.Errorf("attribute number must be finite")
+	}
+	return nil
+}
+
+// Compare orders two values of the same type, returning -1, 0 or +1.
+// Values of different types are ordered by type name so every pair of
+// values has a defined order.
+func (v AttributeValue) Compare(other AttributeValue) int {
+	if v.Type != other.Type {
+		return strings.Compare(string(v.Type), string(other.Type))
+	}
+
+	switch v.Type {
+	case AttributeString:
+		return strings.Compare(v.String, other.String)
+	case AttributeNumber:
+		switch {
+		case v.Number < other.Number:
+			return -1
+		case v.Number > other.Number:
+			return 1
+		}
+	case AttributeBool:
+		if v.Bool != other.Bool {
+			if other.Bool {
+				return -1
+			}
+			return 1
+		}
+	case AttributeTime:
+		return v.Time.Compare(other.Time)
+	}
+	return 0
+}
+
+// Equal reports whether two values have the same type and value
+func (v AttributeValue) Equal(other AttributeValue) bool {
+	return v.Type == other.Type && v.Compare(other) == 0
+}
+
+// attributeJSON is the wire format of an attribute value
+type attributeJSON struct {
+	Type  AttributeType   `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalJSON encodes the value with its type so it round-trips exactly.
+// The zero value encodes as null.
+func (v AttributeValue) MarshalJSON() ([]byte, error) {
+	var value interface{}
+	switch v.Type {
+	case AttributeString:
+		value = v.String
+	case AttributeNumber:
+		value = v.Number
+	case AttributeBool:
+		value = v.Bool
+	case AttributeTime:
+		value = v.Time
+	case "":
+		return []byte("null"), nil
+	default:
+		return nil, fmt.Errorf("invalid attribute type: %q", v.Type)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(attributeJSON{Type: v.Type, Value: raw})
+}
+
+// UnmarshalJSON decodes a typed value or a bare string, number or boolean
+func (v *AttributeValue) UnmarshalJSON(data []byte) error {
+	var bare interface{}
+	if err := json.Unmarshal(data, &bare); err != nil {
+		return err
+	}
+
+	switch value := bare.(type) {
+	case nil:
+		*v = AttributeValue{}
+		return nil
+	case string:
+		*v = StringAttribute(value)
+		return nil
+	case float64:
+		*v = NumberAttribute(value)
+		return nil
+	case bool:
+		*v = BoolAttribute(value)
+		return nil
+	case map[string]interface{}:
+		// Typed form, decoded below
+	default:
+		return fmt.Errorf("invalid attribute value: %s", data)
+	}
+
+	var typed attributeJSON
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+
+	decoded := AttributeValue{Type: typed.Type}
+	var err error
+	switch typed.Type {
+	case AttributeString:
+		err = json.Unmarshal(typed.Value, &decoded.String)
+	case AttributeNumber:
+		err = json.Unmarshal(typed.Value, &decoded.Number)
+	case AttributeBool:
+		err = json.Unmarshal(typed.Value, &decoded.Bool)
+	case AttributeTime:
+		err = json.Unmarshal(typed.Value, &decoded.Time)
+	default:
+		return fmt.Errorf("invalid attribute type: %q", typed.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s attribute value: %w", typed.Type, err)
+	}
+
+	*v = decoded
+	return nil
+}
+
+// Attributes holds a product's custom attributes by name
+type Attributes map[string]AttributeValue
+
+// Get returns the named attribute, if present
+func (a Attributes) Get(name string) (AttributeValue, bool) {
+	value, ok := a[name]
+	return value, ok
+}
+
+// validateAttributeName checks a name can be used in attribute sort strategies
+func validateAttributeName(name string) error {
+	if name == "" {
+		return fmt.Errorf("attribute name cannot be empty")
+	}
+	if strings.ContainsAny(name, ": \t\n") {
+		return fmt.Errorf("attribute name %q cannot contain colons or whitespace", name)
+	}
+	return nil
+}
+
+// Validate ensures every attribute has a usable name and value
+func (a Attributes) Validate() error {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := validateAttributeName(name); err != nil {
+			return err
+		}
+		if err := a[name].Validate(); err != nil {
+			return fmt.Errorf("attribute %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// AttributeOp is a comparison used by attribute filter predicates
+type AttributeOp string
+
+// Attribute predicate operators
+const (
+	AttrExists AttributeOp = "exists"
+	AttrEq     AttributeOp = "eq"
+	AttrNe     AttributeOp = "ne"
+	AttrLt     AttributeOp = "lt"
+	AttrLte    AttributeOp = "lte"
+	AttrGt     AttributeOp = "gt"
+	AttrGte    AttributeOp = "gte"
+)
+
+// AttributePredicate matches products whose attribute compares to Value
+// with Op. Ordering operators only match values of the same type, and
+// products without the attribute match only "ne".
+type AttributePredicate struct {
+	Name  string         `json:"name"`
+	Op    AttributeOp    `json:"op"`
+	Value AttributeValue `json:"value"`
+}
+
+// Validate ensures the predicate is well formed
+func (p AttributePredicate) Validate() error {
+	if err := validateAttributeName(p.Name); err != nil {
+		return err
+	}
+
+	switch p.Op {
+	case AttrExists:
+		return nil
+	case AttrEq, AttrNe, AttrLt, AttrLte, AttrGt, AttrGte:
+		return p.Value.Validate()
+	default:
+		return fmt.Errorf("invalid attribute operator: %q", p.Op)
+	}
+}
+
+// Matches reports whether the product satisfies the predicate
+func (p AttributePredicate) Matches(product Product) bool {
+	value, ok := product.Attributes.Get(p.Name)
+	if !ok {
+		return p.Op == AttrNe
+	}
+
+	switch p.Op {
+	case AttrExists:
+		return true
+	case AttrEq:
+		return value.Equal(p.Value)
+	case AttrNe:
+		return !value.Equal(p.Value)
+	}
+
+	if value.Type != p.Value.Type {
+		return false
+	}
+	cmp := value.Compare(p.Value)
+	switch p.Op {
+	case AttrLt:
+		return cmp < 0
+	case AttrLte:
+		return cmp <= 0
+	case AttrGt:
+		return cmp > 0
+	case AttrGte:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+// attributeStrategyPrefix starts every attribute sort strategy
+const attributeStrategyPrefix = "attr:"
+
+// MissingAttributePolicy decides where an attribute sort places products
+// that lack the attribute
+type MissingAttributePolicy string
+
+// Missing attribute policies
+const (
+	MissingFirst MissingAttributePolicy = "first"
+	MissingLast  MissingAttributePolicy = "last"
+	MissingError MissingAttributePolicy = "error"
+)
+
+// AttributeSortSpec describes a sort by one custom attribute. It is written
+// as the strategy "attr:<name>:<asc|desc>[:<first|last|error>]", e.g.
+// "attr:weight_kg:asc"; products missing the attribute go last by default.
+type AttributeSortSpec struct {
+	Name      string
+	Ascending bool
+	Missing   MissingAttributePolicy
+}
+
+// AttributeSortStrategy builds the strategy sorting by the named attribute
+func AttributeSortStrategy(name string, ascending bool, missing MissingAttributePolicy) SortStrategy {
+	direction := "desc"
+	if ascending {
+		direction = "asc"
+	}
+	strategy := attributeStrategyPrefix + name + ":" + direction
+	if missing != "" && missing != MissingLast {
+		strategy += ":" + string(missing)
+	}
+	return SortStrategy(strategy)
+}
+
+// AttributeSpec parses an attribute sort strategy. It reports false for
+// every other strategy and for malformed attribute strategies.
+func (s SortStrategy) AttributeSpec() (AttributeSortSpec, bool) {
+	if !strings.HasPrefix(string(s), attributeStrategyPrefix) {
+		return AttributeSortSpec{}, false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(string(s), attributeStrategyPrefix), ":")
+	if len(parts) < 2 || len(parts) > 3 || validateAttributeName(parts[0]) != nil {
+		return AttributeSortSpec{}, false
+	}
+
+	spec := AttributeSortSpec{Name: parts[0], Missing: MissingLast}
+	switch parts[1] {
+	case "asc":
+		spec.Ascending = true
+	case "desc":
+	default:
+		return AttributeSortSpec{}, false
+	}
+
+	if len(parts) == 3 {
+		switch policy := MissingAttributePolicy(parts[2]); policy {
+		case MissingFirst, MissingLast, MissingError:
+			spec.Missing = policy
+		default:
+			return AttributeSortSpec{}, false
+		}
+	}
+
+	return spec, true
+}
+
+// Description returns a human-readable description of the attribute sort
+func (spec AttributeSortSpec) Description() string {
+	direction := "Descending"
+	if spec.Ascending {
+		direction = "Ascending"
+	}
+
+	missing := "Missing Last"
+	switch spec.Missing {
+	case MissingFirst:
+		missing = "Missing First"
+	case MissingError:
+		missing = "Required"
+	}
+
+	return fmt.Sprintf("Attribute %s (%s, %s)", spec.Name, direction, missing)
+}
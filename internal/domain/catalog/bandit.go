@@ -0,0 +1,360 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrBanditNotFound is returned when no bandit has the requested name
+var ErrBanditNotFound = errors.New("bandit not found")
+
+// BanditPolicy selects how a bandit trades exploration against exploitation
+type BanditPolicy string
+
+// Bandit policies
+const (
+	// PolicyThompson samples each strategy's Beta posterior and picks the
+	// highest draw
+	PolicyThompson BanditPolicy = "thompson"
+	// PolicyEpsilonGreedy picks a random strategy with probability epsilon
+	// and the best mean reward otherwise
+	PolicyEpsilonGreedy BanditPolicy = "epsilon_greedy"
+)
+
+// DefaultBanditEpsilon is the exploration rate of epsilon-greedy bandits
+// when none is set
+const DefaultBanditEpsilon = 0.1
+
+// IsValid checks if the policy is known
+func (p BanditPolicy) IsValid() bool {
+	switch p {
+	case PolicyThompson, PolicyEpsilonGreedy:
+		return true
+	default:
+		return false
+	}
+}
+
+// BanditConfig defines a bandit choosing between sort strategies
+type BanditConfig struct {
+	Name       string          `json:"name"`
+	Policy     BanditPolicy    `json:"policy"`
+	Strategies SortStrategySet `json:"strategies"`
+
+	// Epsilon is the exploration rate of epsilon-greedy bandits; nil uses
+	// DefaultBanditEpsilon, and zero never explores
+	Epsilon *float64 `json:"epsilon,omitempty"`
+
+	// Seed initialises the random number generator, so a bandit replays the
+	// same selections for the same sequence of calls
+	Seed int64 `json:"seed"`
+}
+
+// Validate ensures the configuration is well formed
+func (c BanditConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("bandit name cannot be empty")
+	}
+	if !c.Policy.IsValid() {
+		return fmt.Errorf("bandit %s: invalid policy: %q", c.Name, c.Policy)
+	}
+	if len(c.Strategies) < 2 {
+		return fmt.Errorf("bandit %s needs at least two strategies", c.Name)
+	}
+	if err := c.Strategies.Validate(); err != nil {
+		return fmt.Errorf("bandit %s: %w", c.Name, err)
+	}
+	seen := make(map[SortStrategy]bool, len(c.Strategies))
+	for _, strategy := range c.Strategies {
+		if seen[strategy] {
+			return fmt.Errorf("bandit %s: duplicate strategy %s", c.Name, strategy)
+		}
+		seen[strategy] = true
+	}
+	if epsilon := c.epsilon(); epsilon < 0 || epsilon > 1 || math.IsNaN(epsilon) {
+		return fmt.Errorf("bandit %s: epsilon must be between 0 and 1", c.Name)
+	}
+	return nil
+}
+
+// copy returns a configuration sharing no slices or pointers with c
+func (c BanditConfig) copy() BanditConfig {
+	c.Strategies = append(SortStrategySet(nil), c.Strategies...)
+	if c.Epsilon != nil {
+		epsilon := *c.Epsilon
+		c.Epsilon = &epsilon
+	}
+	return c
+}
+
+// epsilon returns the exploration rate, or the default when unset
+func (c BanditConfig) epsilon() float64 {
+	if c.Epsilon == nil {
+		return DefaultBanditEpsilon
+	}
+	return *c.Epsilon
+}
+
+// ArmState is what a bandit has learned about one strategy. Alpha and Beta
+// are the Beta posterior of its reward, starting from a uniform prior.
+type ArmState struct {
+	Strategy SortStrategy `json:"strategy"`
+	Pulls    int64        `json:"pulls"`
+	Rewards  int64        `json:"rewards"`
+	Reward   float64      `json:"reward"`
+	Alpha    float64      `json:"alpha"`
+	Beta     float64      `json:"beta"`
+}
+
+// MeanReward returns the average reward per reported outcome
+func (a ArmState) MeanReward() float64 {
+	if a.Rewards == 0 {
+		return 0
+	}
+	return a.Reward / float64(a.Rewards)
+}
+
+// BanditState is the persisted form of a bandit: its configuration, what it
+// has learned and the position of its random number generator
+type BanditState struct {
+	Config    BanditConfig `json:"config"`
+	Arms      []ArmState   `json:"arms"`
+	RNGState  uint64       `json:"rng_state"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// BanditSelection is the strategy a bandit picked for one request
+type BanditSelection struct {
+	Bandit   string       `json:"bandit"`
+	Strategy SortStrategy `json:"strategy"`
+
+	// Explored is set when epsilon-greedy picked a random strategy
+	Explored bool `json:"explored,omitempty"`
+}
+
+// BanditReward reports the reward of a request served with a strategy,
+// between 0 and 1, e.g. 1 for a purchase and 0 otherwise
+type BanditReward struct {
+	Bandit   string       `json:"bandit"`
+	Strategy SortStrategy `json:"strategy"`
+	Reward   float64      `json:"reward"`
+}
+
+// BanditStore persists bandit states
+type BanditStore interface {
+	// Find returns the state of the named bandit or ErrBanditNotFound
+	Find(ctx context.Context, name string) (*BanditState, error)
+
+	// FindAll returns every bandit state ordered by name
+	FindAll(ctx context.Context) ([]BanditState, error)
+
+	// Save stores a bandit state, replacing one with the same name
+	Save(ctx context.Context, state BanditState) error
+}
+
+// Bandit picks a sort strategy per request and learns from rewards. It is
+// safe for concurrent use; selections are deterministic for a given seed and
+// sequence of calls.
+type Bandit struct {
+	mu     sync.Mutex
+	config BanditConfig
+	arms   []ArmState
+	source *banditSource
+	rng    *rand.Rand
+}
+
+// NewBandit creates a bandit with no observations
+func NewBandit(config BanditConfig) (*Bandit, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	arms := make([]ArmState, len(config.Strategies))
+	for i, strategy := range config.Strategies {
+		arms[i] = ArmState{Strategy: strategy, Alpha: 1, Beta: 1}
+	}
+	return newBandit(config, arms, uint64(config.Seed)), nil
+}
+
+// RestoreBandit recreates a bandit from a persisted state, continuing its
+// random sequence where it stopped
+func RestoreBandit(state BanditState) (*Bandit, error) {
+	if err := state.Config.Validate(); err != nil {
+		return nil, err
+	}
+	if len(state.Arms) != len(state.Config.Strategies) {
+		return nil, fmt.Errorf("bandit %s: state has %d arms for %d strategies",
+			state.Config.Name, len(state.Arms), len(state.Config.Strategies))
+	}
+	for i, arm := range state.Arms {
+		if arm.Strategy != state.Config.Strategies[i] {
+			return nil, fmt.Errorf("bandit %s: arm %d is %s, expected %s",
+				state.Config.Name, i, arm.Strategy, state.Config.Strategies[i])
+		}
+		if !(arm.Alpha > 0) || !(arm.Beta > 0) {
+			return nil, fmt.Errorf("bandit %s: arm %s has an invalid posterior", state.Config.Name, arm.Strategy)
+		}
+	}
+
+	return newBandit(state.Config, append([]ArmState(nil), state.Arms...), state.RNGState), nil
+}
+
+// newBandit assembles a bandit around its random number generator
+func newBandit(config BanditConfig, arms []ArmState, rngState uint64) *Bandit {
+	config = config.copy()
+	source := &banditSource{state: rngState}
+	return &Bandit{config: config, arms: arms, source: source, rng: rand.New(source)}
+}
+
+// clone returns an independent bandit with the same state and random
+// sequence position
+func (b *Bandit) clone() *Bandit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return newBandit(b.config, append([]ArmState(nil), b.arms...), b.source.state)
+}
+
+// Name returns the bandit's name
+func (b *Bandit) Name() string {
+	return b.config.Name
+}
+
+// Select picks the strategy to serve the next request with
+func (b *Bandit) Select() BanditSelection {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	selection := BanditSelection{Bandit: b.config.Name}
+	best := 0
+	switch b.config.Policy {
+	case PolicyThompson:
+		bestDraw := -1.0
+		for i, arm := range b.arms {
+			if draw := sampleBeta(b.rng, arm.Alpha, arm.Beta); draw > bestDraw {
+				best, bestDraw = i, draw
+			}
+		}
+	case PolicyEpsilonGreedy:
+		if b.rng.Float64() < b.config.epsilon() {
+			best = b.rng.Intn(len(b.arms))
+			selection.Explored = true
+			break
+		}
+		// Strategies without rewards yet are tried first, in order
+		bestMean := -1.0
+		for i, arm := range b.arms {
+			if arm.Rewards == 0 {
+				best = i
+				break
+			}
+			if mean := arm.MeanReward(); mean > bestMean {
+				best, bestMean = i, mean
+			}
+		}
+	}
+
+	b.arms[best].Pulls++
+	selection.Strategy = b.arms[best].Strategy
+	return selection
+}
+
+// Update records the reward of a request served with a strategy
+func (b *Bandit) Update(strategy SortStrategy, reward float64) error {
+	if reward < 0 || reward > 1 || math.IsNaN(reward) {
+		return fmt.Errorf("bandit reward must be between 0 and 1")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := range b.arms {
+		if b.arms[i].Strategy == strategy {
+			b.arms[i].Rewards++
+			b.arms[i].Reward += reward
+			b.arms[i].Alpha += reward
+			b.arms[i].Beta += 1 - reward
+			return nil
+		}
+	}
+	return fmt.Errorf("bandit %s has no strategy %s", b.config.Name, strategy)
+}
+
+// State returns a snapshot of the bandit for persistence
+func (b *Bandit) State() BanditState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BanditState{
+		Config:    b.config.copy(),
+		Arms:      append([]ArmState(nil), b.arms...),
+		RNGState:  b.source.state,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// sampleBeta draws from a Beta(alpha, beta) distribution as the ratio of two
+// Gamma draws
+func sampleBeta(rng *rand.Rand, alpha, beta float64) float64 {
+	x := sampleGamma(rng, alpha)
+	y := sampleGamma(rng, beta)
+	if x+y == 0 {
+		return 0
+	}
+	return x / (x + y)
+}
+
+// sampleGamma draws from a Gamma(shape, 1) distribution using the
+// Marsaglia-Tsang method
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// Boost the shape above 1 and scale the draw back down
+		return sampleGamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// banditSource is a SplitMix64 generator. Unlike the standard library
+// sources its whole state is one integer, so it can be persisted and
+// resumed exactly.
+type banditSource struct {
+	state uint64
+}
+
+// Uint64 returns the next pseudo-random value
+func (s *banditSource) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer
+func (s *banditSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Seed resets the generator
+func (s *banditSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
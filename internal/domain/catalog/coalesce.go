@@ -0,0 +1,146 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// cacheKeyVersion is bumped whenever sorting semantics change, so results
+// computed by older code are never shared with newer requests
+const cacheKeyVersion = "1"
+
+// errSortIncomplete is reported to waiters if the shared sort never returned
+var errSortIncomplete = errors.New("coalesced sort did not complete")
+
+// NewCacheKey builds the key identifying a sort of products by strategy
+func NewCacheKey(products ProductCollection, strategy SortStrategy) CacheKey {
+	return CacheKey{
+		ProductHash: products.Hash(),
+		Strategy:    strategy,
+		Version:     cacheKeyVersion,
+	}
+}
+
+// sortCall is an in-flight sort shared by coalesced callers. The products
+// are hashed only when another caller needs to compare them.
+type sortCall struct {
+	done   chan struct{}
+	result *SortResult
+	err    error
+
+	products ProductCollection
+	hashOnce sync.Once
+	hash     string
+}
+
+// productHash returns the hash of the call's products, computing it once
+func (c *sortCall) productHash() string {
+	c.hashOnce.Do(func() { c.hash = c.products.Hash() })
+	return c.hash
+}
+
+// matches reports whether the call sorts the same products. The same slice
+// matches without hashing; otherwise both collections are hashed.
+func (c *sortCall) matches(products ProductCollection, hash func() string) bool {
+	if len(c.products) != len(products) {
+		return false
+	}
+	if len(products) == 0 || &c.products[0] == &products[0] {
+		return true
+	}
+	return c.productHash() == hash()
+}
+
+// sortCallGroup deduplicates concurrent identical sort requests so that only
+// one of them validates and sorts while the others wait for its result. Calls
+// are grouped by a key without the product hash; a caller only hashes the
+// catalog when another sort with the same key is already in flight, so
+// uncontended sorts never pay for hashing.
+type sortCallGroup struct {
+	mu    sync.Mutex
+	calls map[CacheKey][]*sortCall
+}
+
+// newSortCallGroup creates an empty call group
+func newSortCallGroup() *sortCallGroup {
+	return &sortCallGroup{calls: make(map[CacheKey][]*sortCall)}
+}
+
+// do runs fn once for all concurrent callers with the same key and products.
+// The returned result is shared and must be cloned before being handed out;
+// shared reports whether it was computed by another caller. Waiting callers
+// stop early if their own context is cancelled.
+//
+// Pending calls are compared outside the lock, since that may hash both
+// catalogs. A caller registers its own call only in the same critical
+// section that confirms every pending call has been compared, so two
+// identical requests arriving together never both sort.
+func (g *sortCallGroup) do(ctx context.Context, key CacheKey, products ProductCollection, fn func() (*SortResult, error)) (result *SortResult, shared bool, err error) {
+	var once sync.Once
+	var hash string
+	ownHash := func() string {
+		once.Do(func() { hash = products.Hash() })
+		return hash
+	}
+
+	var compared map[*sortCall]bool
+	var call *sortCall
+	for call == nil {
+		g.mu.Lock()
+		var unseen []*sortCall
+		for _, pending := range g.calls[key] {
+			if !compared[pending] {
+				unseen = append(unseen, pending)
+			}
+		}
+		if len(unseen) == 0 {
+			call = &sortCall{done: make(chan struct{}), err: errSortIncomplete, products: products}
+			g.calls[key] = append(g.calls[key], call)
+		}
+		g.mu.Unlock()
+
+		if compared == nil && len(unseen) > 0 {
+			compared = make(map[*sortCall]bool, len(unseen))
+		}
+		for _, pending := range unseen {
+			compared[pending] = true
+			if !pending.matches(products, ownHash) {
+				continue
+			}
+			select {
+			case <-pending.done:
+				return pending.result, true, pending.err
+			case <-ctx.Done():
+				return nil, true, ctx.Err()
+			}
+		}
+	}
+
+	// Always release waiters, even if fn panics
+	defer func() {
+		g.mu.Lock()
+		g.remove(key, call)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.result, call.err = fn()
+	return call.result, false, call.err
+}
+
+// remove forgets a finished call; the caller holds g.mu
+func (g *sortCallGroup) remove(key CacheKey, call *sortCall) {
+	calls := g.calls[key]
+	for i, pending := range calls {
+		if pending == call {
+			calls = append(calls[:i:i], calls[i+1:]...)
+			break
+		}
+	}
+	if len(calls) == 0 {
+		delete(g.calls, key)
+		return
+	}
+	g.calls[key] = calls
+}
@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Collation selects how product names are compared
+type Collation struct {
+	// Locale is a BCP 47 tag such as "de" or "sv-SE"; names are compared
+	// with that language's collation rules and its localized names are used.
+	// The root collation applies when it is empty.
+	Locale string `json:"locale,omitempty"`
+
+	// Numeric orders digit runs by value, so "Table 2" sorts before "Table 10"
+	Numeric bool `json:"numeric,omitempty"`
+}
+
+// Validate ensures the locale is a well-formed language tag
+func (c Collation) Validate() error {
+	if c.Locale == "" {
+		return nil
+	}
+	if _, err := language.Parse(c.Locale); err != nil {
+		return fmt.Errorf("invalid locale %q: %w", c.Locale, err)
+	}
+	return nil
+}
+
+// String returns a compact representation used in cache keys
+func (c Collation) String() string {
+	locale := c.Locale
+	if locale == "" {
+		locale = "und"
+	}
+	if c.Numeric {
+		return locale + ":numeric"
+	}
+	return locale
+}
+
+// CollatingSorter is implemented by sorters that compare names.
+// WithCollation returns a sorter comparing localized names with the
+// collation's rules.
+type CollatingSorter interface {
+	Sorter
+
+	// WithCollation returns a copy of the sorter using the collation
+	WithCollation(collation Collation) Sorter
+}
+
+// NameIn returns the product's name for the locale. It tries the exact tag,
+// then ever shorter prefixes ("de-CH" falls back to "de"), and finally the
+// default name. Tags match case-insensitively; should an unvalidated product
+// hold tags differing only by case, the exact tag wins, then the lowest one.
+func (p Product) NameIn(locale string) string {
+	for locale != "" {
+		if name, ok := p.LocalizedNames[locale]; ok {
+			return name
+		}
+		match, found := "", false
+		for tag := range p.LocalizedNames {
+			if strings.EqualFold(tag, locale) && (!found || tag < match) {
+				match, found = tag, true
+			}
+		}
+		if found {
+			return p.LocalizedNames[match]
+		}
+
+		cut := strings.LastIndexAny(locale, "-_")
+		if cut < 0 {
+			break
+		}
+		locale = locale[:cut]
+	}
+	return p.Name
+}
+
+// sortedKeys returns the keys of a localized name map in order, so
+// validation reports problems deterministically
+func sortedKeys(names map[string]string) []string {
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package catalog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a sorted result for keyset pagination. It holds
+// the sort key chain of the last product returned, so the next page starts
+// strictly after it even when products are added or removed in between.
+type Cursor struct {
+	Strategy SortStrategy `json:"s"`
+	Key      Product      `json:"k"`
+}
+
+// NewCursor creates a cursor positioned at the given product
+func NewCursor(strategy SortStrategy, product Product) Cursor {
+	return Cursor{
+		Strategy: strategy,
+		Key:      strategy.SortKey(product),
+	}
+}
+
+// Encode returns the cursor as an opaque URL-safe token
+func (c Cursor) Encode() string {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		// Products always marshal, so this only guards against future changes
+		panic(fmt.Sprintf("failed to encode cursor: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor parses an opaque cursor token
+func DecodeCursor(token string) (Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if !cursor.Strategy.IsValid() {
+		return Cursor{}, fmt.Errorf("%w: unknown strategy %s", ErrInvalidCursor, cursor.Strategy)
+	}
+
+	return cursor, nil
+}
+
+// PageRequest asks for one page of a sorted result
+type PageRequest struct {
+	// After is an opaque cursor from a previous page; empty starts at the top
+	After string `json:"after,omitempty"`
+
+	// Limit is the maximum number of products in the page
+	Limit int `json:"limit"`
+}
+
+// Validate ensures the page request is usable
+func (r PageRequest) Validate() error {
+	if r.Limit <= 0 {
+		return fmt.Errorf("limit must be positive")
+	}
+	return nil
+}
+
+// PageResult is one page of a sorted result
+type PageResult struct {
+	Products   ProductCollection `json:"products"`
+	Strategy   SortStrategy      `json:"strategy"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	HasMore    bool              `json:"has_more"`
+
+	// EffectivePrices holds the discounted price of promoted products on the page
+	EffectivePrices map[ProductID]Money `json:"effective_prices,omitempty"`
+}
+
+// NewPageResult creates a page result, issuing a cursor when more products follow
+func NewPageResult(products ProductCollection, strategy SortStrategy, hasMore bool) *PageResult {
+	result := &PageResult{
+		Products: products,
+		Strategy: strategy,
+		HasMore:  hasMore,
+	}
+
+	if hasMore && len(products) > 0 {
+		result.NextCursor = NewCursor(strategy, products[len(products)-1]).Encode()
+	}
+
+	return result
+}
+
+// ResolveCursor decodes the request cursor and checks it belongs to strategy.
+// It returns nil when the request starts at the top.
+func (r PageRequest) ResolveCursor(strategy SortStrategy) (*Cursor, error) {
+	if r.After == "" {
+		return nil, nil
+	}
+
+	cursor, err := DecodeCursor(r.After)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Strategy != strategy {
+		return nil, fmt.Errorf("%w: issued for strategy %s, not %s", ErrInvalidCursor, cursor.Strategy, strategy)
+	}
+
+	return &cursor, nil
+}
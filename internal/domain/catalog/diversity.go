@@ -0,0 +1,192 @@
+package catalog
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+)
+
+// Diversity grouping keys
+const (
+	GroupByCategory = "category"
+	// GroupByBrand groups by the "brand" custom attribute
+	GroupByBrand = "brand"
+)
+
+// DiversityPolicy re-ranks a sorted collection so that no more than
+// MaxPerWindow products from the same group appear in any Window consecutive
+// positions. It applies on top of any SortStrategy and moves as few products
+// as possible: each position takes the highest-ranked remaining product that
+// keeps the constraint, and the base order is kept when no product does.
+// Products without a group value are never constrained.
+type DiversityPolicy struct {
+	// GroupBy is "category", "brand" or "attr:<name>" for any custom attribute
+	GroupBy string `json:"group_by"`
+
+	MaxPerWindow int `json:"max_per_window"`
+	Window       int `json:"window"`
+
+	// Depth limits re-ranking to the first Depth positions; zero re-ranks the
+	// whole collection
+	Depth int `json:"depth,omitempty"`
+}
+
+// Validate ensures the policy is well formed
+func (dp DiversityPolicy) Validate() error {
+	switch {
+	case dp.GroupBy == GroupByCategory, dp.GroupBy == GroupByBrand:
+	case strings.HasPrefix(dp.GroupBy, attributeStrategyPrefix):
+		if err := validateAttributeName(strings.TrimPrefix(dp.GroupBy, attributeStrategyPrefix)); err != nil {
+			return fmt.Errorf("invalid diversity group: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid diversity group: %q", dp.GroupBy)
+	}
+
+	if dp.Window < 1 {
+		return fmt.Errorf("diversity window must be at least 1")
+	}
+	if dp.MaxPerWindow < 1 || dp.MaxPerWindow > dp.Window {
+		return fmt.Errorf("max per window must be between 1 and the window size")
+	}
+	if dp.Depth < 0 {
+		return fmt.Errorf("diversity depth cannot be negative")
+	}
+
+	return nil
+}
+
+// String returns a compact description of the policy, e.g. "category:2/5:top20"
+func (dp DiversityPolicy) String() string {
+	description := fmt.Sprintf("%s:%d/%d", dp.GroupBy, dp.MaxPerWindow, dp.Window)
+	if dp.Depth > 0 {
+		description += fmt.Sprintf(":top%d", dp.Depth)
+	}
+	return description
+}
+
+// Group returns the product's group value, or "" when it has none
+func (dp DiversityPolicy) Group(product Product) string {
+	if dp.GroupBy == GroupByCategory {
+		return product.Category
+	}
+
+	name := strings.TrimPrefix(dp.GroupBy, attributeStrategyPrefix)
+	value, ok := product.Attributes.Get(name)
+	if !ok {
+		return ""
+	}
+	switch value.Type {
+	case AttributeString:
+		return "s:" + value.String
+	case AttributeNumber:
+		return fmt.Sprintf("n:%g", value.Number)
+	case AttributeBool:
+		return fmt.Sprintf("b:%t", value.Bool)
+	case AttributeTime:
+		return "t:" + value.Time.UTC().String()
+	default:
+		return ""
+	}
+}
+
+// Apply re-ranks a sorted collection, returning a new collection.
+//
+// Products wait in one queue per group, and the queues sit in a heap ordered
+// by the base position of their next product. Each position pops queues until
+// one is allowed; only groups already at MaxPerWindow in the trailing window
+// are skipped, so each position costs O(Window log groups) rather than a scan
+// of every remaining product.
+func (dp DiversityPolicy) Apply(sorted ProductCollection) ProductCollection {
+	depth := len(sorted)
+	if dp.Depth > 0 && dp.Depth < depth {
+		depth = dp.Depth
+	}
+
+	queues := make(map[string]*groupQueue)
+	pending := make(groupHeap, 0)
+	groups := make([]string, len(sorted))
+	for i, product := range sorted {
+		group := dp.Group(product)
+		groups[i] = group
+		queue, exists := queues[group]
+		if !exists {
+			queue = &groupQueue{group: group}
+			queues[group] = queue
+			pending = append(pending, queue)
+		}
+		queue.positions = append(queue.positions, i)
+	}
+	heap.Init(&pending)
+
+	reranked := make(ProductCollection, 0, len(sorted))
+	placed := make([]bool, len(sorted))
+	var placedGroups []string
+	inWindow := make(map[string]int)
+	var skipped []*groupQueue
+	for len(reranked) < depth {
+		// Take the first allowed queue; when none is allowed keep the base order
+		var chosen *groupQueue
+		for pending.Len() > 0 {
+			queue := heap.Pop(&pending).(*groupQueue)
+			if queue.group == "" || inWindow[queue.group] < dp.MaxPerWindow {
+				chosen = queue
+				break
+			}
+			skipped = append(skipped, queue)
+		}
+		if chosen == nil {
+			chosen, skipped = skipped[0], skipped[1:]
+		}
+		for _, queue := range skipped {
+			heap.Push(&pending, queue)
+		}
+		skipped = skipped[:0]
+
+		position := chosen.positions[chosen.next]
+		chosen.next++
+		if chosen.next < len(chosen.positions) {
+			heap.Push(&pending, chosen)
+		}
+
+		reranked = append(reranked, sorted[position])
+		placed[position] = true
+		placedGroups = append(placedGroups, groups[position])
+
+		// Count groups over the trailing Window-1 positions the next pick follows
+		inWindow[groups[position]]++
+		if leaving := len(placedGroups) - dp.Window; leaving >= 0 {
+			inWindow[placedGroups[leaving]]--
+		}
+	}
+
+	for i, product := range sorted {
+		if !placed[i] {
+			reranked = append(reranked, product)
+		}
+	}
+	return reranked
+}
+
+// groupQueue holds the base positions of one group's products not yet placed
+type groupQueue struct {
+	group     string
+	positions []int
+	next      int
+}
+
+// groupHeap orders group queues by the base position of their next product
+type groupHeap []*groupQueue
+
+func (h groupHeap) Len() int { return len(h) }
+func (h groupHeap) Less(i, j int) bool {
+	return h[i].positions[h[i].next] < h[j].positions[h[j].next]
+}
+func (h groupHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *groupHeap) Push(x interface{}) { *h = append(*h, x.(*groupQueue)) }
+func (h *groupHeap) Pop() interface{} {
+	old := *h
+	queue := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return queue
+}
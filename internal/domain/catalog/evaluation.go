@@ -0,0 +1,273 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultEvaluationK is the metric cut-off used when none is set
+const DefaultEvaluationK = 10
+
+// MaxRelevanceGain bounds judgment gains. NDCG uses exponential gain, and
+// 2^gain overflows to +Inf long before a gain reaches float64's range.
+const MaxRelevanceGain = 64
+
+// RelevanceQuery holds graded relevance judgments for one query or session:
+// the gain of every relevant product. Products without a gain are irrelevant.
+type RelevanceQuery struct {
+	ID    string                `json:"id"`
+	Gains map[ProductID]float64 `json:"gains"`
+}
+
+// PurchaseSession lists the products bought in one session. Each purchase
+// counts as one unit of gain.
+type PurchaseSession struct {
+	ID        string      `json:"id"`
+	Purchases []ProductID `json:"purchases"`
+}
+
+// Judgments is the ground truth rankings are evaluated against. Graded
+// queries and purchase sessions can be mixed.
+type Judgments struct {
+	Queries  []RelevanceQuery  `json:"queries,omitempty"`
+	Sessions []PurchaseSession `json:"sessions,omitempty"`
+}
+
+// Validate ensures every query has an ID, at least one relevant product and
+// non-negative gains no larger than MaxRelevanceGain
+func (j Judgments) Validate() error {
+	if len(j.Queries) == 0 && len(j.Sessions) == 0 {
+		return fmt.Errorf("judgments must contain at least one query or session")
+	}
+
+	seen := make(map[string]bool)
+	for _, query := range j.RelevanceQueries() {
+		if query.ID == "" {
+			return fmt.Errorf("judgment query ID cannot be empty")
+		}
+		if seen[query.ID] {
+			return fmt.Errorf("duplicate judgment query ID %s", query.ID)
+		}
+		seen[query.ID] = true
+
+		relevant := 0
+		for id, gain := range query.Gains {
+			if gain < 0 || math.IsNaN(gain) || math.IsInf(gain, 0) {
+				return fmt.Errorf("query %s: gain for product %d must be a non-negative number", query.ID, id)
+			}
+			if gain > MaxRelevanceGain {
+				return fmt.Errorf("query %s: gain %g for product %d exceeds %d", query.ID, gain, id, MaxRelevanceGain)
+			}
+			if gain > 0 {
+				relevant++
+			}
+		}
+		if relevant == 0 {
+			return fmt.Errorf("query %s has no relevant products", query.ID)
+		}
+	}
+	return nil
+}
+
+// RelevanceQueries returns every judgment as a graded query, converting
+// purchase sessions into gains
+func (j Judgments) RelevanceQueries() []RelevanceQuery {
+	queries := append([]RelevanceQuery(nil), j.Queries...)
+	for _, session := range j.Sessions {
+		gains := make(map[ProductID]float64, len(session.Purchases))
+		for _, id := range session.Purchases {
+			gains[id]++
+		}
+		queries = append(queries, RelevanceQuery{ID: session.ID, Gains: gains})
+	}
+	return queries
+}
+
+// Fingerprint identifies the judgments, so only reports evaluated against
+// the same ground truth are compared
+func (j Judgments) Fingerprint() string {
+	queries := j.RelevanceQueries()
+	sort.Slice(queries, func(a, b int) bool { return queries[a].ID < queries[b].ID })
+
+	// Encoding maps sorts their keys, so equal judgments encode identically
+	data, _ := json.Marshal(queries)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RankingMetrics are the mean metrics of one ranking over all queries
+type RankingMetrics struct {
+	NDCG      float64 `json:"ndcg"`
+	MAP       float64 `json:"map"`
+	MRR       float64 `json:"mrr"`
+	Precision float64 `json:"precision"`
+}
+
+// EvaluateRanking scores a ranking against every query and returns the mean
+// metrics. NDCG and precision are cut off at k; MAP and MRR use the full
+// ranking. Gains use the exponential form 2^gain - 1.
+func EvaluateRanking(ranking ProductCollection, queries []RelevanceQuery, k int) RankingMetrics {
+	var total RankingMetrics
+	if len(queries) == 0 {
+		return total
+	}
+
+	for _, query := range queries {
+		metrics := evaluateQuery(ranking, query, k)
+		total.NDCG += metrics.NDCG
+		total.MAP += metrics.MAP
+		total.MRR += metrics.MRR
+		total.Precision += metrics.Precision
+	}
+
+	count := float64(len(queries))
+	return RankingMetrics{
+		NDCG:      total.NDCG / count,
+		MAP:       total.MAP / count,
+		MRR:       total.MRR / count,
+		Precision: total.Precision / count,
+	}
+}
+
+// evaluateQuery scores a ranking against one query
+func evaluateQuery(ranking ProductCollection, query RelevanceQuery, k int) RankingMetrics {
+	var metrics RankingMetrics
+
+	relevantTotal := 0
+	ideal := make([]float64, 0, len(query.Gains))
+	for _, gain := range query.Gains {
+		if gain > 0 {
+			relevantTotal++
+			ideal = append(ideal, gain)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(ideal)))
+
+	dcg, idcg := 0.0, 0.0
+	for i := 0; i < k && i < len(ideal); i++ {
+		idcg += discountedGain(ideal[i], i)
+	}
+
+	relevantSeen := 0
+	precisionSum := 0.0
+	for i, product := range ranking {
+		gain := query.Gains[product.ID]
+		if gain <= 0 {
+			continue
+		}
+
+		relevantSeen++
+		precisionSum += float64(relevantSeen) / float64(i+1)
+		if relevantSeen == 1 {
+			metrics.MRR = 1 / float64(i+1)
+		}
+		if i < k {
+			dcg += discountedGain(gain, i)
+			metrics.Precision++
+		}
+	}
+
+	if idcg > 0 {
+		metrics.NDCG = dcg / idcg
+	}
+	if relevantTotal > 0 {
+		metrics.MAP = precisionSum / float64(relevantTotal)
+	}
+	metrics.Precision /= float64(k)
+	return metrics
+}
+
+// discountedGain returns the DCG contribution of a gain at a 0-based position
+func discountedGain(gain float64, position int) float64 {
+	return (math.Pow(2, gain) - 1) / math.Log2(float64(position+2))
+}
+
+// LeaderboardEntry is one strategy's place on an evaluation leaderboard
+type LeaderboardEntry struct {
+	Rank     int          `json:"rank"`
+	Strategy SortStrategy `json:"strategy"`
+	RankingMetrics
+}
+
+// EvaluationReport ranks strategies by NDCG, then MAP, then strategy name.
+// Reports with the same K, catalog hash and judgments fingerprint are
+// comparable across runs.
+type EvaluationReport struct {
+	K                    int                `json:"k"`
+	Queries              int                `json:"queries"`
+	CatalogHash          string             `json:"catalog_hash"`
+	JudgmentsFingerprint string             `json:"judgments_fingerprint"`
+	Leaderboard          []LeaderboardEntry `json:"leaderboard"`
+	EvaluatedAt          time.Time          `json:"evaluated_at"`
+}
+
+// Comparable reports whether two reports evaluated the same catalog against
+// the same judgments with the same cut-off
+func (r *EvaluationReport) Comparable(other *EvaluationReport) bool {
+	return r.K == other.K && r.CatalogHash == other.CatalogHash &&
+		r.JudgmentsFingerprint == other.JudgmentsFingerprint
+}
+
+// Entry returns the leaderboard entry of a strategy, if it was evaluated
+func (r *EvaluationReport) Entry(strategy SortStrategy) (LeaderboardEntry, bool) {
+	for _, entry := range r.Leaderboard {
+		if entry.Strategy == strategy {
+			return entry, true
+		}
+	}
+	return LeaderboardEntry{}, false
+}
+
+// EvaluateBatch scores every ranking of a batch sort against the judgments
+// and builds the leaderboard. A k of zero uses DefaultEvaluationK.
+func EvaluateBatch(batch *BatchSortResult, products ProductCollection, judgments Judgments, k int) (*EvaluationReport, error) {
+	if err := batch.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid batch sort result: %w", err)
+	}
+	if err := judgments.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid judgments: %w", err)
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("evaluation cut-off cannot be negative")
+	}
+	if k == 0 {
+		k = DefaultEvaluationK
+	}
+
+	queries := judgments.RelevanceQueries()
+	report := &EvaluationReport{
+		K:                    k,
+		Queries:              len(queries),
+		CatalogHash:          products.Hash(),
+		JudgmentsFingerprint: judgments.Fingerprint(),
+		EvaluatedAt:          time.Now(),
+	}
+
+	for strategy, result := range batch.Results {
+		report.Leaderboard = append(report.Leaderboard, LeaderboardEntry{
+			Strategy:       strategy,
+			RankingMetrics: EvaluateRanking(result.Products, queries, k),
+		})
+	}
+
+	sort.Slice(report.Leaderboard, func(i, j int) bool {
+		a, b := report.Leaderboard[i], report.Leaderboard[j]
+		if a.NDCG != b.NDCG {
+			return a.NDCG > b.NDCG
+		}
+		if a.MAP != b.MAP {
+			return a.MAP > b.MAP
+		}
+		return a.Strategy < b.Strategy
+	})
+	for i := range report.Leaderboard {
+		report.Leaderboard[i].Rank = i + 1
+	}
+
+	return report, nil
+}
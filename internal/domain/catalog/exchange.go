@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrRateUnavailable is returned when a snapshot has no rate for a currency
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// ExchangeRateProvider supplies exchange rate snapshots for currency conversion
+type ExchangeRateProvider interface {
+	// Snapshot returns the current set of exchange rates
+	Snapshot(ctx context.Context) (*RateSnapshot, error)
+}
+
+// RateSnapshot is an immutable table of exchange rates taken at one point in
+// time. Rates are expressed as units of each currency per one unit of Base.
+type RateSnapshot struct {
+	ID    string               `json:"id"`
+	Base  Currency             `json:"base"`
+	Rates map[Currency]float64 `json:"rates"`
+	AsOf  time.Time            `json:"as_of"`
+}
+
+// Validate ensures the snapshot can be used for conversion
+func (rs *RateSnapshot) Validate() error {
+	if rs == nil {
+		return fmt.Errorf("rate snapshot cannot be nil")
+	}
+
+	if rs.ID == "" {
+		return fmt.Errorf("rate snapshot ID cannot be empty")
+	}
+
+	if !rs.Base.IsValid() {
+		return fmt.Errorf("invalid base currency: %s", rs.Base)
+	}
+
+	for currency, rate := range rs.Rates {
+		if !currency.IsValid() {
+			return fmt.Errorf("invalid currency in rates: %s", currency)
+		}
+		if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			return fmt.Errorf("rate for %s must be a positive number", currency)
+		}
+	}
+
+	return nil
+}
+
+// Rate returns the number of units of currency per unit of the base currency
+func (rs *RateSnapshot) Rate(currency Currency) (float64, error) {
+	currency = currency.OrDefault()
+	if currency == rs.Base.OrDefault() {
+		return 1, nil
+	}
+
+	rate, exists := rs.Rates[currency]
+	if !exists {
+		return 0, fmt.Errorf("%w: %s in snapshot %s", ErrRateUnavailable, currency, rs.ID)
+	}
+	return rate, nil
+}
+
+// Supports reports whether amounts in the currency can be converted
+func (rs *RateSnapshot) Supports(currency Currency) bool {
+	_, err := rs.Rate(currency)
+	return err == nil
+}
+
+// Convert converts an amount into the target currency, rounding to the
+// nearest minor unit of the target
+func (rs *RateSnapshot) Convert(amount Money, target Currency) (Money, error) {
+	source := amount.Currency.OrDefault()
+	target = target.OrDefault()
+	if source == target {
+		return amount, nil
+	}
+
+	fromRate, err := rs.Rate(source)
+	if err != nil {
+		return Money{}, err
+	}
+	toRate, err := rs.Rate(target)
+	if err != nil {
+		return Money{}, err
+	}
+
+	scale := math.Pow10(target.Exponent() - source.Exponent())
+	converted := float64(amount.Amount) * (toRate / fromRate) * scale
+	return NewMoney(int64(math.Round(converted)), target), nil
+}
+
+// CurrencyConversion converts amounts into a single target currency using
+// one rate snapshot, so that prices in different currencies can be compared
+type CurrencyConversion struct {
+	Snapshot *RateSnapshot
+	Target   Currency
+}
+
+// NewCurrencyConversion creates a conversion into target using snapshot
+func NewCurrencyConversion(snapshot *RateSnapshot, target Currency) (*CurrencyConversion, error) {
+	if err := snapshot.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate snapshot: %w", err)
+	}
+
+	if !target.IsValid() {
+		return nil, fmt.Errorf("invalid target currency: %s", target)
+	}
+
+	if !snapshot.Supports(target) {
+		return nil, fmt.Errorf("%w: %s in snapshot %s", ErrRateUnavailable, target.OrDefault(), snapshot.ID)
+	}
+
+	return &CurrencyConversion{Snapshot: snapshot, Target: target.OrDefault()}, nil
+}
+
+// Convert converts an amount into the target currency
+func (c *CurrencyConversion) Convert(amount Money) (Money, error) {
+	return c.Snapshot.Convert(amount, c.Target)
+}
+
+// ConvertOrKeep converts an amount, returning it unchanged if no rate exists.
+// Comparators use it after the catalog has been checked with CheckProducts.
+func (c *CurrencyConversion) ConvertOrKeep(amount Money) Money {
+	converted, err := c.Convert(amount)
+	if err != nil {
+		return amount
+	}
+	return converted
+}
+
+// CheckProducts ensures every product price can be converted
+func (c *CurrencyConversion) CheckProducts(products ProductCollection) error {
+	for i, product := range products {
+		if !c.Snapshot.Supports(product.Price.Currency) {
+			return fmt.Errorf("product at index %d (ID: %d): %w: %s in snapshot %s",
+				i, product.ID, ErrRateUnavailable, product.Price.Currency.OrDefault(), c.Snapshot.ID)
+		}
+	}
+	return nil
+}
+
+// CurrencyAwareSorter is implemented by sorters whose ordering depends on
+// monetary amounts. WithCurrency returns a sorter that compares amounts after
+// converting them into the conversion's target currency.
+type CurrencyAwareSorter interface {
+	Sorter
+
+	// WithCurrency returns a copy of the sorter that compares converted amounts
+	WithCurrency(conversion *CurrencyConversion) Sorter
+}
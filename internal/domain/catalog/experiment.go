@@ -0,0 +1,169 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrExperimentNotFound is returned when no experiment has the requested name
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// ExperimentBuckets is the number of buckets units are hashed into; traffic
+// percentages resolve to 0.01%
+const ExperimentBuckets = 10000
+
+// ExperimentVariant is one arm of an experiment, served with its own strategy
+type ExperimentVariant struct {
+	Name     string       `json:"name"`
+	Strategy SortStrategy `json:"strategy"`
+
+	// Traffic is the percentage of units assigned to the variant
+	Traffic float64 `json:"traffic"`
+}
+
+// Experiment splits users or sessions between sort strategies.
+//
+// Units are assigned by hashing the experiment name, salt and unit ID into
+// one of ExperimentBuckets buckets, so a unit keeps its variant for as long
+// as the name, salt and traffic split are unchanged. Changing the salt
+// reshuffles every unit. The first variant is the control: it is served to
+// everyone while the experiment is not running.
+type Experiment struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Salt        string              `json:"salt"`
+	Variants    []ExperimentVariant `json:"variants"`
+
+	// StartsAt and EndsAt bound when the experiment runs; zero values leave
+	// the window open at that end
+	StartsAt time.Time `json:"starts_at,omitempty"`
+	EndsAt   time.Time `json:"ends_at,omitempty"`
+}
+
+// Validate ensures the experiment is well formed. Traffic percentages must be
+// positive, use at most two decimal places and add up to 100.
+func (e Experiment) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("experiment name cannot be empty")
+	}
+	if e.Salt == "" {
+		return fmt.Errorf("experiment %s: salt cannot be empty", e.Name)
+	}
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("experiment %s needs at least two variants", e.Name)
+	}
+
+	seen := make(map[string]bool, len(e.Variants))
+	total := 0.0
+	for _, variant := range e.Variants {
+		if variant.Name == "" {
+			return fmt.Errorf("experiment %s: variant name cannot be empty", e.Name)
+		}
+		if seen[variant.Name] {
+			return fmt.Errorf("experiment %s: duplicate variant %s", e.Name, variant.Name)
+		}
+		seen[variant.Name] = true
+
+		if !variant.Strategy.IsValid() {
+			return fmt.Errorf("experiment %s: variant %s has invalid strategy: %s", e.Name, variant.Name, variant.Strategy)
+		}
+		if variant.Traffic <= 0 || math.IsNaN(variant.Traffic) || math.IsInf(variant.Traffic, 0) {
+			return fmt.Errorf("experiment %s: variant %s traffic must be positive", e.Name, variant.Name)
+		}
+		if buckets := variant.Traffic * ExperimentBuckets / 100; math.Abs(buckets-math.Round(buckets)) > 1e-6 {
+			return fmt.Errorf("experiment %s: variant %s traffic cannot be finer than 0.01%%", e.Name, variant.Name)
+		}
+		total += variant.Traffic
+	}
+	if math.Abs(total-100) > 1e-6 {
+		return fmt.Errorf("experiment %s: variant traffic must add up to 100, got %g", e.Name, total)
+	}
+
+	if !e.StartsAt.IsZero() && !e.EndsAt.IsZero() && !e.EndsAt.After(e.StartsAt) {
+		return fmt.Errorf("experiment %s: end time must be after start time", e.Name)
+	}
+	return nil
+}
+
+// IsActiveAt reports whether the experiment is running at the given time
+func (e Experiment) IsActiveAt(at time.Time) bool {
+	if !e.StartsAt.IsZero() && at.Before(e.StartsAt) {
+		return false
+	}
+	return e.EndsAt.IsZero() || at.Before(e.EndsAt)
+}
+
+// Bucket returns the unit's bucket, from 0 to ExperimentBuckets-1
+func (e Experiment) Bucket(unitID string) int {
+	sum := sha256.Sum256([]byte(e.Name + "\x00" + e.Salt + "\x00" + unitID))
+	return int(binary.BigEndian.Uint64(sum[:8]) % ExperimentBuckets)
+}
+
+// Assign returns the unit's variant at the given time. Variants own
+// consecutive bucket ranges in definition order.
+func (e Experiment) Assign(unitID string, at time.Time) (*ExperimentAssignment, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	if unitID == "" {
+		return nil, fmt.Errorf("experiment %s: unit ID cannot be empty", e.Name)
+	}
+
+	bucket := e.Bucket(unitID)
+	assignment := &ExperimentAssignment{Experiment: e.Name, UnitID: unitID, Bucket: bucket}
+	if !e.IsActiveAt(at) {
+		assignment.Variant = e.Variants[0].Name
+		assignment.Strategy = e.Variants[0].Strategy
+		return assignment, nil
+	}
+
+	assignment.Active = true
+	upper := 0.0
+	for _, variant := range e.Variants {
+		upper += variant.Traffic * ExperimentBuckets / 100
+		if float64(bucket) < math.Round(upper) {
+			assignment.Variant = variant.Name
+			assignment.Strategy = variant.Strategy
+			return assignment, nil
+		}
+	}
+
+	// Unreachable once traffic adds up to 100; guard against rounding
+	last := e.Variants[len(e.Variants)-1]
+	assignment.Variant = last.Name
+	assignment.Strategy = last.Strategy
+	return assignment, nil
+}
+
+// ExperimentAssignment is the variant a unit was served
+type ExperimentAssignment struct {
+	Experiment string       `json:"experiment"`
+	UnitID     string       `json:"unit_id"`
+	Variant    string       `json:"variant"`
+	Strategy   SortStrategy `json:"strategy"`
+	Bucket     int          `json:"bucket"`
+
+	// Active is false when the experiment was not running and the unit got
+	// the control variant
+	Active bool `json:"active"`
+}
+
+// ExperimentStore persists experiment definitions
+type ExperimentStore interface {
+	// Find returns the experiment with the given name or ErrExperimentNotFound
+	Find(ctx context.Context, name string) (*Experiment, error)
+
+	// FindAll returns every experiment, running or not, ordered by name
+	FindAll(ctx context.Context) ([]Experiment, error)
+
+	// Save validates and stores an experiment, replacing one with the same name
+	Save(ctx context.Context, experiment Experiment) error
+
+	// Delete removes an experiment or returns ErrExperimentNotFound
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,274 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OutcomeType is the kind of user interaction an experiment event records
+type OutcomeType string
+
+// Experiment outcome types
+const (
+	OutcomeImpression OutcomeType = "impression"
+	OutcomeClick      OutcomeType = "click"
+	OutcomePurchase   OutcomeType = "purchase"
+)
+
+// IsValid checks if the outcome type is known
+func (t OutcomeType) IsValid() bool {
+	switch t {
+	case OutcomeImpression, OutcomeClick, OutcomePurchase:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExperimentEvent is one interaction of a unit served an experiment variant
+type ExperimentEvent struct {
+	Experiment string      `json:"experiment"`
+	Variant    string      `json:"variant"`
+	Type       OutcomeType `json:"type"`
+	UnitID     string      `json:"unit_id"`
+
+	// ProductID is the clicked or purchased product; optional for impressions
+	ProductID ProductID `json:"product_id,omitempty"`
+
+	// Revenue is the purchase amount; only purchases carry revenue
+	Revenue Money `json:"revenue"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate ensures the event is well formed
+func (e ExperimentEvent) Validate() error {
+	if e.Experiment == "" || e.Variant == "" {
+		return fmt.Errorf("event must name an experiment and a variant")
+	}
+	if !e.Type.IsValid() {
+		return fmt.Errorf("invalid outcome type: %q", e.Type)
+	}
+	if e.UnitID == "" {
+		return fmt.Errorf("event unit ID cannot be empty")
+	}
+	if e.Revenue.IsNegative() {
+		return fmt.Errorf("event revenue cannot be negative")
+	}
+	if !e.Revenue.IsZero() && e.Type != OutcomePurchase {
+		return fmt.Errorf("only purchase events carry revenue")
+	}
+	return nil
+}
+
+// VariantOutcome aggregates the events of one experiment variant.
+//
+// Units counts the distinct units with an impression and Converters those of
+// them with a purchase, so the conversion rate is a proportion of exposed
+// units. Impressions, Clicks and Purchases count events.
+type VariantOutcome struct {
+	Variant     string `json:"variant"`
+	Units       int    `json:"units"`
+	Converters  int    `json:"converters"`
+	Impressions int    `json:"impressions"`
+	Clicks      int    `json:"clicks"`
+	Purchases   int    `json:"purchases"`
+	Revenue     Money  `json:"revenue"`
+}
+
+// variantCounts accumulates a variant's events and the units behind them
+type variantCounts struct {
+	outcome   VariantOutcome
+	exposed   map[string]bool
+	purchased map[string]bool
+}
+
+// experimentCounts accumulates the events of one experiment
+type experimentCounts struct {
+	currency Currency
+	variants map[string]*variantCounts
+}
+
+// ExperimentEventLog persists recorded experiment events so outcomes survive
+// restarts
+type ExperimentEventLog interface {
+	// Append durably stores validated events
+	Append(ctx context.Context, events []ExperimentEvent) error
+
+	// Events returns every stored event in the order it was appended
+	Events(ctx context.Context) ([]ExperimentEvent, error)
+}
+
+// OutcomeTracker aggregates experiment events in memory. It is safe for
+// concurrent use. Every exposed and purchasing unit is kept per variant for
+// the lifetime of the tracker, so memory grows with the number of units.
+type OutcomeTracker struct {
+	// writeMu serialises recorders, so a batch validated against the
+	// counts is aggregated before the next one is validated, while persisting
+	// happens outside mu and never blocks readers
+	writeMu sync.Mutex
+
+	mu          sync.Mutex
+	experiments map[string]*experimentCounts
+	alerted     map[string]bool
+}
+
+// NewOutcomeTracker creates an empty tracker
+func NewOutcomeTracker() *OutcomeTracker {
+	return &OutcomeTracker{
+		experiments: make(map[string]*experimentCounts),
+		alerted:     make(map[string]bool),
+	}
+}
+
+// Record validates and aggregates events. All revenue of an experiment must
+// be in one currency. Nothing is recorded if any event is invalid.
+func (t *OutcomeTracker) Record(events ...ExperimentEvent) error {
+	return t.record(events, nil)
+}
+
+// record validates events, calls persist when set and aggregates the events
+// only if it succeeds. Persisting does not hold t.mu, so reports never wait
+// on the event log.
+func (t *OutcomeTracker) record(events []ExperimentEvent, persist func() error) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	currencies, err := t.validate(events)
+	if err != nil {
+		return err
+	}
+
+	if persist != nil {
+		if err := persist(); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, event := range events {
+		counts, exists := t.experiments[event.Experiment]
+		if !exists {
+			counts = &experimentCounts{variants: make(map[string]*variantCounts)}
+			t.experiments[event.Experiment] = counts
+		}
+		if currency, ok := currencies[event.Experiment]; ok {
+			counts.currency = currency
+		}
+
+		variant, exists := counts.variants[event.Variant]
+		if !exists {
+			variant = &variantCounts{
+				outcome:   VariantOutcome{Variant: event.Variant},
+				exposed:   make(map[string]bool),
+				purchased: make(map[string]bool),
+			}
+			counts.variants[event.Variant] = variant
+		}
+		variant.record(event)
+	}
+	return nil
+}
+
+// validate checks every event first so a batch is recorded entirely or not
+// at all, and returns the revenue currency of each experiment in the batch
+func (t *OutcomeTracker) validate(events []ExperimentEvent) (map[string]Currency, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	currencies := make(map[string]Currency)
+	for i, event := range events {
+		if err := event.Validate(); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		if event.Type != OutcomePurchase || event.Revenue.IsZero() {
+			continue
+		}
+
+		currency, seen := currencies[event.Experiment]
+		if !seen {
+			if counts, exists := t.experiments[event.Experiment]; exists {
+				currency = counts.currency
+			}
+		}
+		revenueCurrency := event.Revenue.Currency.OrDefault()
+		if currency != "" && currency != revenueCurrency {
+			return nil, fmt.Errorf("event %d: %w: experiment %s records revenue in %s, got %s",
+				i, ErrCurrencyMismatch, event.Experiment, currency, revenueCurrency)
+		}
+		currencies[event.Experiment] = revenueCurrency
+	}
+	return currencies, nil
+}
+
+// record adds one event to the variant's counts
+func (c *variantCounts) record(event ExperimentEvent) {
+	switch event.Type {
+	case OutcomeImpression:
+		c.outcome.Impressions++
+		c.exposed[event.UnitID] = true
+	case OutcomeClick:
+		c.outcome.Clicks++
+	case OutcomePurchase:
+		c.outcome.Purchases++
+		c.purchased[event.UnitID] = true
+		if !event.Revenue.IsZero() {
+			c.outcome.Revenue = NewMoney(c.outcome.Revenue.Amount+event.Revenue.Amount, event.Revenue.Currency)
+		}
+	}
+}
+
+// Outcomes returns the aggregated outcomes of an experiment's variants,
+// ordered by variant name
+func (t *OutcomeTracker) Outcomes(experiment string) []VariantOutcome {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts, exists := t.experiments[experiment]
+	if !exists {
+		return nil
+	}
+
+	outcomes := make([]VariantOutcome, 0, len(counts.variants))
+	for _, variant := range counts.variants {
+		outcome := variant.outcome
+		outcome.Units = len(variant.exposed)
+		outcome.Converters = 0
+		for unit := range variant.purchased {
+			if variant.exposed[unit] {
+				outcome.Converters++
+			}
+		}
+		if outcome.Revenue.IsZero() {
+			outcome.Revenue = NewMoney(0, counts.currency)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].Variant < outcomes[j].Variant })
+	return outcomes
+}
+
+// markAlerted records that an alert was raised and reports whether it is new
+func (t *OutcomeTracker) markAlerted(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.alerted[key] {
+		return false
+	}
+	t.alerted[key] = true
+	return true
+}
+
+// clearAlerted forgets an alert so it can be raised again
+func (t *OutcomeTracker) clearAlerted(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.alerted, key)
+}
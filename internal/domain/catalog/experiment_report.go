@@ -0,0 +1,314 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Defaults for experiment reports
+const (
+	DefaultConfidenceLevel         = 0.95
+	DefaultMinimumDetectableEffect = 0.10
+	DefaultStatisticalPower        = 0.80
+)
+
+// Alert types raised from experiment reports
+const (
+	AlertExperimentWinner     = "experiment_winner"
+	AlertExperimentRegression = "experiment_regression"
+)
+
+// ReportOptions configures the statistics of an experiment report. Zero
+// values use the defaults.
+type ReportOptions struct {
+	// ConfidenceLevel sets the confidence intervals and the significance
+	// threshold, e.g. 0.95 for a 5% false positive rate
+	ConfidenceLevel float64 `json:"confidence_level,omitempty"`
+
+	// MinimumDetectableEffect is the smallest relative lift in conversion
+	// the sample size is planned for, e.g. 0.1 for 10%
+	MinimumDetectableEffect float64 `json:"minimum_detectable_effect,omitempty"`
+
+	// Power is the chance of detecting a lift of MinimumDetectableEffect
+	Power float64 `json:"power,omitempty"`
+}
+
+// withDefaults validates the options and fills in defaults
+func (o ReportOptions) withDefaults() (ReportOptions, error) {
+	if o.ConfidenceLevel == 0 {
+		o.ConfidenceLevel = DefaultConfidenceLevel
+	}
+	if o.MinimumDetectableEffect == 0 {
+		o.MinimumDetectableEffect = DefaultMinimumDetectableEffect
+	}
+	if o.Power == 0 {
+		o.Power = DefaultStatisticalPower
+	}
+
+	if !(o.ConfidenceLevel > 0 && o.ConfidenceLevel < 1) {
+		return o, fmt.Errorf("confidence level must be between 0 and 1")
+	}
+	if !(o.Power > 0 && o.Power < 1) {
+		return o, fmt.Errorf("power must be between 0 and 1")
+	}
+	if !(o.MinimumDetectableEffect > 0) || math.IsInf(o.MinimumDetectableEffect, 0) {
+		return o, fmt.Errorf("minimum detectable effect must be positive")
+	}
+	return o, nil
+}
+
+// Interval is a two-sided confidence interval
+type Interval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// VariantReport holds a variant's outcomes and rates
+type VariantReport struct {
+	VariantOutcome
+	Strategy SortStrategy `json:"strategy"`
+
+	// ClickThroughRate is clicks per impression
+	ClickThroughRate float64 `json:"click_through_rate"`
+
+	// ConversionRate is the share of exposed units that purchased, with its
+	// Wilson score interval
+	ConversionRate     float64  `json:"conversion_rate"`
+	ConversionInterval Interval `json:"conversion_interval"`
+
+	// RevenuePerImpression is in major units of the revenue currency
+	RevenuePerImpression float64 `json:"revenue_per_impression"`
+}
+
+// VariantComparison tests a variant's conversion rate against the control
+// with a two-proportion z-test
+type VariantComparison struct {
+	Variant  string       `json:"variant"`
+	Strategy SortStrategy `json:"strategy"`
+
+	// Difference is the variant's conversion rate minus the control's, with
+	// its confidence interval; Lift is the difference relative to the control
+	Difference         float64  `json:"difference"`
+	DifferenceInterval Interval `json:"difference_interval"`
+	Lift               float64  `json:"lift"`
+
+	ZScore      float64 `json:"z_score"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+}
+
+// ExperimentReport summarises an experiment's outcomes per variant. The
+// first variant of the experiment is the control every other variant is
+// compared with.
+type ExperimentReport struct {
+	Experiment      string  `json:"experiment"`
+	Control         string  `json:"control"`
+	ConfidenceLevel float64 `json:"confidence_level"`
+
+	Variants    []VariantReport     `json:"variants"`
+	Comparisons []VariantComparison `json:"comparisons"`
+
+	// RequiredSampleSize is the number of exposed units each variant needs to
+	// detect the minimum detectable effect at the configured power; zero
+	// until the control has converted
+	RequiredSampleSize int `json:"required_sample_size"`
+
+	// Warnings flag results that should not be acted on yet
+	Warnings []string `json:"warnings,omitempty"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// SampleSizeReached reports whether every variant has enough exposed units
+func (r *ExperimentReport) SampleSizeReached() bool {
+	if r.RequiredSampleSize == 0 {
+		return false
+	}
+	for _, variant := range r.Variants {
+		if variant.Units < r.RequiredSampleSize {
+			return false
+		}
+	}
+	return true
+}
+
+// Alerts returns an alert for every variant that significantly beats or
+// trails the control once the sample size is reached
+func (r *ExperimentReport) Alerts() []PerformanceAlertEvent {
+	if !r.SampleSizeReached() {
+		return nil
+	}
+
+	var alerts []PerformanceAlertEvent
+	for _, comparison := range r.Comparisons {
+		if !comparison.Significant {
+			continue
+		}
+
+		alert := PerformanceAlertEvent{
+			AlertType: AlertExperimentWinner,
+			Severity:  "info",
+			Message: fmt.Sprintf("experiment %s: variant %s (%s) converts %+.1f%% against %s",
+				r.Experiment, comparison.Variant, comparison.Strategy, comparison.Lift*100, r.Control),
+			Metadata: map[string]interface{}{
+				"experiment": r.Experiment,
+				"variant":    comparison.Variant,
+				"strategy":   string(comparison.Strategy),
+				"control":    r.Control,
+				"lift":       comparison.Lift,
+				"p_value":    comparison.PValue,
+			},
+			Timestamp: r.GeneratedAt,
+		}
+		if comparison.Difference < 0 {
+			alert.AlertType = AlertExperimentRegression
+			alert.Severity = "warning"
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// BuildExperimentReport computes rates, significance tests and sample size
+// warnings from an experiment's outcomes. Variants without events report
+// zeros; outcomes of variants not in the experiment are ignored.
+func BuildExperimentReport(experiment Experiment, outcomes []VariantOutcome, options ReportOptions) (*ExperimentReport, error) {
+	if err := experiment.Validate(); err != nil {
+		return nil, err
+	}
+	options, err := options.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	byVariant := make(map[string]VariantOutcome, len(outcomes))
+	for _, outcome := range outcomes {
+		byVariant[outcome.Variant] = outcome
+	}
+
+	z := normalQuantile((1 + options.ConfidenceLevel) / 2)
+	report := &ExperimentReport{
+		Experiment:      experiment.Name,
+		Control:         experiment.Variants[0].Name,
+		ConfidenceLevel: options.ConfidenceLevel,
+		GeneratedAt:     time.Now(),
+	}
+
+	for _, variant := range experiment.Variants {
+		outcome, ok := byVariant[variant.Name]
+		if !ok {
+			outcome = VariantOutcome{Variant: variant.Name}
+		}
+
+		entry := VariantReport{VariantOutcome: outcome, Strategy: variant.Strategy}
+		if outcome.Impressions > 0 {
+			entry.ClickThroughRate = float64(outcome.Clicks) / float64(outcome.Impressions)
+			entry.RevenuePerImpression = outcome.Revenue.ToFloat64() / float64(outcome.Impressions)
+		}
+		if outcome.Units > 0 {
+			entry.ConversionRate = float64(outcome.Converters) / float64(outcome.Units)
+		}
+		entry.ConversionInterval = wilsonInterval(outcome.Converters, outcome.Units, z)
+		report.Variants = append(report.Variants, entry)
+	}
+
+	control := report.Variants[0]
+	for _, variant := range report.Variants[1:] {
+		comparison := compareProportions(control, variant, z)
+		comparison.Significant = variant.Units > 0 && control.Units > 0 &&
+			comparison.PValue < 1-options.ConfidenceLevel
+		report.Comparisons = append(report.Comparisons, comparison)
+	}
+
+	report.RequiredSampleSize = requiredSampleSize(control.ConversionRate, options)
+	switch {
+	case report.RequiredSampleSize > 0:
+	case control.ConversionRate >= 1:
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("every unit of control %s converted; no lift can be detected and the required sample size cannot be estimated", control.Variant))
+	default:
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("control %s has no conversions yet; the required sample size cannot be estimated", control.Variant))
+	}
+	for _, variant := range report.Variants {
+		if variant.Units < report.RequiredSampleSize {
+			report.Warnings = append(report.Warnings,
+				fmt.Sprintf("variant %s has %d of the %d units needed to detect a %.0f%% lift",
+					variant.Variant, variant.Units, report.RequiredSampleSize, options.MinimumDetectableEffect*100))
+		}
+	}
+
+	return report, nil
+}
+
+// compareProportions runs a two-proportion z-test of a variant's conversion
+// rate against the control's. The test uses the pooled rate; the interval
+// of the difference uses the unpooled standard error.
+func compareProportions(control, variant VariantReport, z float64) VariantComparison {
+	comparison := VariantComparison{
+		Variant:    variant.Variant,
+		Strategy:   variant.Strategy,
+		Difference: variant.ConversionRate - control.ConversionRate,
+		PValue:     1,
+	}
+	if control.ConversionRate > 0 {
+		comparison.Lift = comparison.Difference / control.ConversionRate
+	}
+
+	n1, n2 := float64(control.Units), float64(variant.Units)
+	if n1 == 0 || n2 == 0 {
+		return comparison
+	}
+
+	p1, p2 := control.ConversionRate, variant.ConversionRate
+	spread := z * math.Sqrt(p1*(1-p1)/n1+p2*(1-p2)/n2)
+	comparison.DifferenceInterval = Interval{Lower: comparison.Difference - spread, Upper: comparison.Difference + spread}
+
+	pooled := float64(control.Converters+variant.Converters) / (n1 + n2)
+	standardError := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2))
+	if standardError == 0 {
+		return comparison
+	}
+	comparison.ZScore = comparison.Difference / standardError
+	comparison.PValue = math.Erfc(math.Abs(comparison.ZScore) / math.Sqrt2)
+	return comparison
+}
+
+// wilsonInterval returns the Wilson score interval of a proportion, which
+// stays within [0, 1] for small samples and extreme rates
+func wilsonInterval(successes, trials int, z float64) Interval {
+	if trials == 0 {
+		return Interval{Lower: 0, Upper: 1}
+	}
+
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	spread := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return Interval{Lower: math.Max(0, center-spread), Upper: math.Min(1, center+spread)}
+}
+
+// requiredSampleSize returns the units per variant a two-sided two-proportion
+// test needs to detect a relative lift of the minimum detectable effect over
+// the baseline rate, or zero when the baseline is zero or one
+func requiredSampleSize(baseline float64, options ReportOptions) int {
+	if baseline <= 0 || baseline >= 1 {
+		return 0
+	}
+
+	target := math.Min(baseline*(1+options.MinimumDetectableEffect), 1)
+	alpha := normalQuantile((1 + options.ConfidenceLevel) / 2)
+	beta := normalQuantile(options.Power)
+	mean := (baseline + target) / 2
+
+	numerator := alpha*math.Sqrt(2*mean*(1-mean)) +
+		beta*math.Sqrt(baseline*(1-baseline)+target*(1-target))
+	return int(math.Ceil(numerator * numerator / ((target - baseline) * (target - baseline))))
+}
+
+// normalQuantile returns the standard normal quantile of a probability
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
@@ -0,0 +1,182 @@
+package catalog
+
+// RankingCriterion is one value a sorter compares, in the order its Less
+// applies them
+type RankingCriterion struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// ExplainingSorter is implemented by sorters that can report the criteria
+// they rank a product by, from the primary key down to the final ID
+// tie-breaker
+type ExplainingSorter interface {
+	Sorter
+
+	// Criteria returns the product's values for each comparison criterion
+	Criteria(product Product) []RankingCriterion
+}
+
+// Ranking stages, in the order a sort applies them
+const (
+	StageSorter        = "sorter"
+	StageStock         = "stock"
+	StageDiversity     = "diversity"
+	StageMerchandising = "merchandising"
+)
+
+// NeighbourComparison explains the order of a product and an adjacent one
+type NeighbourComparison struct {
+	ProductID ProductID `json:"product_id"`
+
+	// DecidedBy is the first criterion on which the two products differ
+	DecidedBy string `json:"decided_by,omitempty"`
+
+	// Overridden is set when the sorter alone would order the pair the other
+	// way and a later stage swapped them
+	Overridden bool `json:"overridden,omitempty"`
+}
+
+// RankAdjustment records a stage after the sorter that moved a product
+type RankAdjustment struct {
+	Stage string `json:"stage"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+
+	// RuleIDs lists the merchandising rules that affected the product
+	RuleIDs []string `json:"rule_ids,omitempty"`
+}
+
+// RankExplanation explains why a product holds its position in a result.
+// Positions are 1-based.
+type RankExplanation struct {
+	ProductID ProductID `json:"product_id"`
+	Position  int       `json:"position"`
+
+	// SorterPosition is where the sorter alone ranked the product
+	SorterPosition int `json:"sorter_position"`
+
+	Criteria    []RankingCriterion   `json:"criteria,omitempty"`
+	Previous    *NeighbourComparison `json:"previous,omitempty"`
+	Next        *NeighbourComparison `json:"next,omitempty"`
+	Adjustments []RankAdjustment     `json:"adjustments,omitempty"`
+}
+
+// clone returns an independent copy of the explanation
+func (e RankExplanation) clone() RankExplanation {
+	e.Criteria = append([]RankingCriterion(nil), e.Criteria...)
+	if e.Previous != nil {
+		previous := *e.Previous
+		e.Previous = &previous
+	}
+	if e.Next != nil {
+		next := *e.Next
+		e.Next = &next
+	}
+	adjustments := e.Adjustments
+	e.Adjustments = nil
+	for _, adjustment := range adjustments {
+		adjustment.RuleIDs = append([]string(nil), adjustment.RuleIDs...)
+		e.Adjustments = append(e.Adjustments, adjustment)
+	}
+	return e
+}
+
+// rankingStage is the order of products after one stage of a sort
+type rankingStage struct {
+	name      string
+	positions map[ProductID]int
+}
+
+// newRankingStage records the 1-based positions of products after a stage
+func newRankingStage(name string, products ProductCollection) rankingStage {
+	positions := make(map[ProductID]int, len(products))
+	for i, product := range products {
+		positions[product.ID] = i + 1
+	}
+	return rankingStage{name: name, positions: positions}
+}
+
+// explainRanking explains every product of a final ranking. The first stage
+// is the sorter's own order; later stages are the adjustments applied to it.
+func explainRanking(final ProductCollection, sorter Sorter, stages []rankingStage, fired []FiredRule) []RankExplanation {
+	explaining, _ := sorter.(ExplainingSorter)
+	comparator, _ := sorter.(Comparator)
+
+	rulesByProduct := make(map[ProductID][]string)
+	for _, rule := range fired {
+		for _, id := range rule.ProductIDs {
+			rulesByProduct[id] = append(rulesByProduct[id], rule.RuleID)
+		}
+	}
+
+	criteria := make([][]RankingCriterion, len(final))
+	if explaining != nil {
+		for i, product := range final {
+			criteria[i] = explaining.Criteria(product)
+		}
+	}
+
+	compare := func(i, j int) *NeighbourComparison {
+		comparison := &NeighbourComparison{ProductID: final[j].ID}
+		comparison.DecidedBy = firstDifference(criteria[i], criteria[j])
+		if comparator != nil {
+			// The sorter orders products i and j by position; check it agrees
+			first, second := final[i], final[j]
+			if i > j {
+				first, second = second, first
+			}
+			comparison.Overridden = comparator.Less(second, first)
+		}
+		return comparison
+	}
+
+	explanations := make([]RankExplanation, len(final))
+	for i, product := range final {
+		explanation := RankExplanation{
+			ProductID: product.ID,
+			Position:  i + 1,
+			Criteria:  criteria[i],
+		}
+		if len(stages) > 0 {
+			explanation.SorterPosition = stages[0].positions[product.ID]
+		}
+		if i > 0 {
+			explanation.Previous = compare(i, i-1)
+		}
+		if i < len(final)-1 {
+			explanation.Next = compare(i, i+1)
+		}
+
+		for s := 1; s < len(stages); s++ {
+			from, to := stages[s-1].positions[product.ID], stages[s].positions[product.ID]
+			rules := rulesByProduct[product.ID]
+			if stages[s].name != StageMerchandising {
+				rules = nil
+			}
+			// A rule can fire without moving the product, e.g. a pin at its
+			// current position, and is still reported
+			if from == to && len(rules) == 0 {
+				continue
+			}
+			explanation.Adjustments = append(explanation.Adjustments,
+				RankAdjustment{Stage: stages[s].name, From: from, To: to, RuleIDs: rules})
+		}
+
+		explanations[i] = explanation
+	}
+
+	return explanations
+}
+
+// firstDifference returns the name of the first criterion on which two
+// products differ
+func firstDifference(a, b []RankingCriterion) string {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k].Value != b[k].Value {
+			return a[k].Name
+		}
+	}
+	return ""
+}
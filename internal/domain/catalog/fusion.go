@@ -0,0 +1,244 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// FusionMethod selects how several rankings are merged into one
+type FusionMethod string
+
+// Fusion methods
+const (
+	// FuseBorda awards each product n-rank points per ranking of n products
+	FuseBorda FusionMethod = "borda"
+	// FuseRRF sums 1/(k+rank) over the rankings (reciprocal rank fusion)
+	FuseRRF FusionMethod = "rrf"
+	// FuseMedian ranks products by their weighted median rank
+	FuseMedian FusionMethod = "median"
+)
+
+// DefaultRRFConstant is the k of reciprocal rank fusion when none is set
+const DefaultRRFConstant = 60
+
+// fusionStrategyPrefix starts the strategy recorded on fused results
+const fusionStrategyPrefix = "fusion:"
+
+// IsValid checks if the fusion method is known
+func (m FusionMethod) IsValid() bool {
+	switch m {
+	case FuseBorda, FuseRRF, FuseMedian:
+		return true
+	default:
+		return false
+	}
+}
+
+// Strategy returns the strategy recorded on results fused with the method,
+// e.g. "fusion:borda"
+func (m FusionMethod) Strategy() SortStrategy {
+	return SortStrategy(fusionStrategyPrefix + string(m))
+}
+
+// FusionOptions configures rank fusion
+type FusionOptions struct {
+	Method FusionMethod `json:"method"`
+
+	// Weights scales each strategy's contribution; strategies without a
+	// weight count once
+	Weights map[SortStrategy]float64 `json:"weights,omitempty"`
+
+	// RRFConstant is the k of reciprocal rank fusion; zero uses DefaultRRFConstant
+	RRFConstant float64 `json:"rrf_constant,omitempty"`
+}
+
+// Validate ensures the options are well formed
+func (o FusionOptions) Validate() error {
+	if !o.Method.IsValid() {
+		return fmt.Errorf("invalid fusion method: %q", o.Method)
+	}
+	for strategy, weight := range o.Weights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf("weight for strategy %s must be a non-negative number", strategy)
+		}
+	}
+	if o.RRFConstant < 0 || math.IsNaN(o.RRFConstant) || math.IsInf(o.RRFConstant, 0) {
+		return fmt.Errorf("rrf constant must be a non-negative number")
+	}
+	return nil
+}
+
+// weight returns the weight of a strategy's ranking
+func (o FusionOptions) weight(strategy SortStrategy) float64 {
+	if weight, ok := o.Weights[strategy]; ok {
+		return weight
+	}
+	return 1
+}
+
+// RankContribution is one ranking's part in a product's fused score
+type RankContribution struct {
+	Strategy SortStrategy `json:"strategy"`
+
+	// Rank is the 1-based position in the strategy's ranking; zero when the
+	// product was missing from it
+	Rank   int     `json:"rank"`
+	Weight float64 `json:"weight"`
+
+	// Score is the weighted points the ranking contributed; median fusion
+	// uses ranks rather than points and leaves it zero
+	Score float64 `json:"score"`
+}
+
+// FusedScore is a product's fused score with the contribution of every ranking
+type FusedScore struct {
+	// Score is the summed points for Borda and RRF, higher first, and the
+	// weighted median rank for median fusion, lower first
+	Score         float64            `json:"score"`
+	Contributions []RankContribution `json:"contributions"`
+}
+
+// FusionDetails describes how a fused result was produced
+type FusionDetails struct {
+	Method     FusionMethod             `json:"method"`
+	Strategies SortStrategySet          `json:"strategies"`
+	Scores     map[ProductID]FusedScore `json:"scores"`
+}
+
+// clone returns an independent copy of the details
+func (d *FusionDetails) clone() *FusionDetails {
+	clone := *d
+	clone.Strategies = append(SortStrategySet(nil), d.Strategies...)
+	clone.Scores = make(map[ProductID]FusedScore, len(d.Scores))
+	for id, score := range d.Scores {
+		score.Contributions = append([]RankContribution(nil), score.Contributions...)
+		clone.Scores[id] = score
+	}
+	return &clone
+}
+
+// FuseRankings merges the rankings of a batch sort into one consensus
+// ranking. Products missing from a ranking score nothing from it under
+// Borda and RRF, and count as ranked last under median fusion. Ties are
+// broken by product ID.
+func FuseRankings(batch *BatchSortResult, options FusionOptions) (*SortResult, error) {
+	if err := batch.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid batch sort result: %w", err)
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	// Visit rankings in a fixed order so contributions are deterministic
+	strategies := make(SortStrategySet, 0, len(batch.Results))
+	for strategy := range batch.Results {
+		strategies = append(strategies, strategy)
+	}
+	sort.Slice(strategies, func(i, j int) bool { return strategies[i] < strategies[j] })
+
+	totalWeight := 0.0
+	for _, strategy := range strategies {
+		totalWeight += options.weight(strategy)
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("at least one strategy must have a positive weight")
+	}
+
+	products := make(map[ProductID]Product)
+	var order []ProductID
+	ranks := make(map[SortStrategy]map[ProductID]int, len(strategies))
+	for _, strategy := range strategies {
+		ranking := make(map[ProductID]int)
+		for i, product := range batch.Results[strategy].Products {
+			ranking[product.ID] = i + 1
+			if _, seen := products[product.ID]; !seen {
+				products[product.ID] = product
+				order = append(order, product.ID)
+			}
+		}
+		ranks[strategy] = ranking
+	}
+
+	k := options.RRFConstant
+	if k == 0 {
+		k = DefaultRRFConstant
+	}
+
+	scores := make(map[ProductID]FusedScore, len(order))
+	for _, id := range order {
+		var fused FusedScore
+		for _, strategy := range strategies {
+			rank := ranks[strategy][id]
+			weight := options.weight(strategy)
+			contribution := RankContribution{Strategy: strategy, Rank: rank, Weight: weight}
+			if rank > 0 {
+				switch options.Method {
+				case FuseBorda:
+					contribution.Score = weight * float64(len(ranks[strategy])-rank)
+				case FuseRRF:
+					contribution.Score = weight / (k + float64(rank))
+				}
+			}
+			fused.Score += contribution.Score
+			fused.Contributions = append(fused.Contributions, contribution)
+		}
+		if options.Method == FuseMedian {
+			fused.Score = weightedMedianRank(fused.Contributions, ranks)
+		}
+		scores[id] = fused
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		a, b := scores[order[i]].Score, scores[order[j]].Score
+		if a != b {
+			if options.Method == FuseMedian {
+				return a < b
+			}
+			return a > b
+		}
+		return order[i] < order[j]
+	})
+
+	fused := make(ProductCollection, len(order))
+	for i, id := range order {
+		fused[i] = products[id]
+	}
+
+	result := NewSortResult(fused, options.Method.Strategy(), time.Since(start))
+	result.Fusion = &FusionDetails{Method: options.Method, Strategies: strategies, Scores: scores}
+	return result, nil
+}
+
+// weightedMedianRank returns the lowest rank at which at least half of the
+// total weight has ranked the product, counting missing ranks as last
+func weightedMedianRank(contributions []RankContribution, ranks map[SortStrategy]map[ProductID]int) float64 {
+	type weightedRank struct {
+		rank   int
+		weight float64
+	}
+
+	entries := make([]weightedRank, 0, len(contributions))
+	total := 0.0
+	for _, contribution := range contributions {
+		rank := contribution.Rank
+		if rank == 0 {
+			rank = len(ranks[contribution.Strategy]) + 1
+		}
+		entries = append(entries, weightedRank{rank: rank, weight: contribution.Weight})
+		total += contribution.Weight
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].rank < entries[j].rank })
+
+	cumulative := 0.0
+	for _, entry := range entries {
+		cumulative += entry.weight
+		if cumulative >= total/2 {
+			return float64(entry.rank)
+		}
+	}
+	return float64(entries[len(entries)-1].rank)
+}
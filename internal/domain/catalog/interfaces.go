@@ -122,7 +122,8 @@ type PerformanceAnalysis struct {
 	HighPerformers      ProductCollection      `json:"high_performers"`
 	LowPerformers       ProductCollection      `json:"low_performers"`
 	AverageConversion   float64               `json:"average_conversion"`
-	TotalRevenue        float64               `json:"total_revenue"`
+	TotalRevenue        Money                 `json:"total_revenue"`
+	TotalProfit         Money                 `json:"total_profit"`
 	TopCategories       []CategoryMetrics      `json:"top_categories"`
 	PerformanceMetrics  map[string]interface{} `json:"performance_metrics"`
 	GeneratedAt         time.Time             `json:"generated_at"`
@@ -132,7 +133,7 @@ type PerformanceAnalysis struct {
 type CategoryMetrics struct {
 	Category    string  `json:"category"`
 	ProductCount int    `json:"product_count"`
-	TotalRevenue float64 `json:"total_revenue"`
+	TotalRevenue Money   `json:"total_revenue"`
 	AvgConversion float64 `json:"avg_conversion"`
 }
 
@@ -151,6 +152,17 @@ type CacheKey struct {
 	ProductHash  string       `json:"product_hash"`
 	Strategy     SortStrategy `json:"strategy"`
 	Version      string       `json:"version"`
+	Currency     Currency     `json:"currency,omitempty"`
+	RateSnapshot string       `json:"rate_snapshot,omitempty"`
+	StockPolicy  string       `json:"stock_policy,omitempty"`
+	Diversity    string       `json:"diversity,omitempty"`
+	Promotions   string       `json:"promotions,omitempty"`
+	Variants     VariantMode  `json:"variants,omitempty"`
+	Collation    string       `json:"collation,omitempty"`
+	Rules        string       `json:"rules,omitempty"`
+
+	IncludeInactive bool `json:"include_inactive,omitempty"`
+	Explain         bool `json:"explain,omitempty"`
 }
 
 // Domain Events
@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition is returned for lifecycle transitions the state machine does not allow
+var ErrInvalidTransition = errors.New("invalid lifecycle transition")
+
+// LifecycleStatus is the publication state of a product
+type LifecycleStatus string
+
+// Lifecycle statuses
+const (
+	// StatusUnset is the status of products that predate lifecycle tracking;
+	// they are treated as active
+	StatusUnset        LifecycleStatus = ""
+	StatusDraft        LifecycleStatus = "draft"
+	StatusActive       LifecycleStatus = "active"
+	StatusDiscontinued LifecycleStatus = "discontinued"
+	StatusArchived     LifecycleStatus = "archived"
+)
+
+// lifecycleTransitions lists the statuses reachable from each status
+var lifecycleTransitions = map[LifecycleStatus][]LifecycleStatus{
+	StatusDraft:        {StatusActive, StatusArchived},
+	StatusActive:       {StatusDiscontinued, StatusArchived},
+	StatusDiscontinued: {StatusArchived},
+	StatusArchived:     {},
+}
+
+// IsValid checks if the status is known
+func (s LifecycleStatus) IsValid() bool {
+	if s == StatusUnset {
+		return true
+	}
+	_, exists := lifecycleTransitions[s]
+	return exists
+}
+
+// OrDefault returns the status, or StatusActive when it is unset
+func (s LifecycleStatus) OrDefault() LifecycleStatus {
+	if s == StatusUnset {
+		return StatusActive
+	}
+	return s
+}
+
+// CanTransitionTo reports whether the state machine allows moving to next
+func (s LifecycleStatus) CanTransitionTo(next LifecycleStatus) bool {
+	for _, allowed := range lifecycleTransitions[s.OrDefault()] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the product is published. Sorts and repository
+// queries exclude products that are not active unless explicitly asked to
+// include them.
+func (p Product) IsActive() bool {
+	return p.Status.OrDefault() == StatusActive
+}
+
+// TransitionTo moves the product to the next lifecycle status, recording
+// when the transition happened
+func (p *Product) TransitionTo(next LifecycleStatus, at time.Time) error {
+	current := p.Status.OrDefault()
+	if !current.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current, next)
+	}
+
+	if at.IsZero() {
+		return fmt.Errorf("transition time must be set")
+	}
+
+	switch next {
+	case StatusActive:
+		p.ActivatedAt = &at
+	case StatusDiscontinued:
+		p.DiscontinuedAt = &at
+	case StatusArchived:
+		p.ArchivedAt = &at
+	}
+	p.Status = next
+
+	return nil
+}
+
+// FilterActive returns only active products
+func (pc ProductCollection) FilterActive() ProductCollection {
+	var active ProductCollection
+	for _, product := range pc {
+		if product.IsActive() {
+			active = append(active, product)
+		}
+	}
+	return active
+}
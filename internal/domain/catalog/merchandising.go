@@ -0,0 +1,370 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// RuleAction is what a merchandising rule does to the products it matches
+type RuleAction string
+
+// Merchandising rule actions
+const (
+	ActionPin     RuleAction = "pin"
+	ActionBoost   RuleAction = "boost"
+	ActionBury    RuleAction = "bury"
+	ActionExclude RuleAction = "exclude"
+)
+
+// MerchandisingRule overrides the algorithmic ranking for products matching
+// its condition while the rule is active.
+//
+// Rules run after the sorter, in phases: exclusions first, then score
+// boosts and buries, then position boosts and buries, then pins. Within a
+// phase rules run by descending Priority, then ID. A product pinned by
+// several rules keeps its highest-priority pin, and when pins compete for a
+// position the highest-priority pin takes it and the others shift down.
+type MerchandisingRule struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name,omitempty"`
+	Priority int        `json:"priority,omitempty"`
+	Action   RuleAction `json:"action"`
+
+	// Condition selects the products the rule applies to. Visibility is
+	// decided by the sort, so IncludeInactive, Limit and Offset are ignored.
+	Condition ProductFilter `json:"condition"`
+
+	// Position is the 1-based position pinned products are placed at
+	Position int `json:"position,omitempty"`
+
+	// Positions moves boosted products up, or buried products down, by a
+	// number of places
+	Positions int `json:"positions,omitempty"`
+
+	// Score adjusts the rank score of boosted or buried products. Each
+	// product's rank score runs from 1 for the top product down towards 0 for
+	// the last, so a boost of 1 lifts matches above every unmatched product.
+	Score float64 `json:"score,omitempty"`
+
+	// StartsAt and EndsAt bound when the rule applies; zero values leave the
+	// window open at that end
+	StartsAt time.Time `json:"starts_at,omitempty"`
+	EndsAt   time.Time `json:"ends_at,omitempty"`
+}
+
+// Validate ensures the rule is well formed
+func (r MerchandisingRule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule ID cannot be empty")
+	}
+
+	switch r.Action {
+	case ActionPin:
+		if r.Position < 1 {
+			return fmt.Errorf("rule %s: pin position must be at least 1", r.ID)
+		}
+	case ActionBoost, ActionBury:
+		byScore := r.Score != 0
+		byPositions := r.Positions != 0
+		if byScore == byPositions {
+			return fmt.Errorf("rule %s: %s needs either a score or a number of positions", r.ID, r.Action)
+		}
+		if r.Positions < 0 || r.Score < 0 || math.IsNaN(r.Score) || math.IsInf(r.Score, 0) {
+			return fmt.Errorf("rule %s: %s amount must be positive", r.ID, r.Action)
+		}
+	case ActionExclude:
+	default:
+		return fmt.Errorf("rule %s: invalid action: %s", r.ID, r.Action)
+	}
+
+	if !r.StartsAt.IsZero() && !r.EndsAt.IsZero() && !r.EndsAt.After(r.StartsAt) {
+		return fmt.Errorf("rule %s: end time must be after start time", r.ID)
+	}
+
+	for _, predicate := range r.Condition.Attributes {
+		if err := predicate.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// IsActiveAt reports whether the rule applies at the given time
+func (r MerchandisingRule) IsActiveAt(at time.Time) bool {
+	if !r.StartsAt.IsZero() && at.Before(r.StartsAt) {
+		return false
+	}
+	return r.EndsAt.IsZero() || at.Before(r.EndsAt)
+}
+
+// Matches reports whether the rule's condition selects the product
+func (r MerchandisingRule) Matches(product Product) bool {
+	condition := r.Condition
+	condition.IncludeInactive = true
+	return condition.Matches(product)
+}
+
+// FiredRule records a rule that changed a sort result and the products it
+// affected, in result order for pins, boosts and buries
+type FiredRule struct {
+	RuleID     string      `json:"rule_id"`
+	Action     RuleAction  `json:"action"`
+	ProductIDs []ProductID `json:"product_ids"`
+}
+
+// MerchandisingRuleProvider supplies the merchandising rules known to the catalog
+type MerchandisingRuleProvider interface {
+	// Rules returns all configured rules, active or not
+	Rules(ctx context.Context) ([]MerchandisingRule, error)
+}
+
+// RuleSet applies the merchandising rules active at one point in time
+type RuleSet struct {
+	rules []MerchandisingRule
+}
+
+// NewRuleSet creates a rule set from the rules active at the given time
+func NewRuleSet(rules []MerchandisingRule, at time.Time) (*RuleSet, error) {
+	active := make([]MerchandisingRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		if rule.IsActiveAt(at) {
+			active = append(active, rule)
+		}
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Priority != active[j].Priority {
+			return active[i].Priority > active[j].Priority
+		}
+		return active[i].ID < active[j].ID
+	})
+
+	return &RuleSet{rules: active}, nil
+}
+
+// Fingerprint identifies the set of active rules, so results ranked with the
+// same rules can be shared
+func (rs *RuleSet) Fingerprint() string {
+	if len(rs.rules) == 0 {
+		return ""
+	}
+
+	// Encoding plain rule values never fails
+	data, _ := json.Marshal(rs.rules)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Apply returns the products reordered by the active rules, and the rules
+// that matched at least one product. The input is not modified.
+func (rs *RuleSet) Apply(products ProductCollection) (ProductCollection, []FiredRule) {
+	result := products.Copy()
+	var fired []FiredRule
+
+	record := func(rule MerchandisingRule, ids []ProductID) {
+		if len(ids) > 0 {
+			fired = append(fired, FiredRule{RuleID: rule.ID, Action: rule.Action, ProductIDs: ids})
+		}
+	}
+
+	// Phase 1: exclusions
+	for _, rule := range rs.withAction(ActionExclude) {
+		var kept ProductCollection
+		var excluded []ProductID
+		for _, product := range result {
+			if rule.Matches(product) {
+				excluded = append(excluded, product.ID)
+			} else {
+				kept = append(kept, product)
+			}
+		}
+		result = kept
+		record(rule, excluded)
+	}
+
+	// Phase 2: score adjustments against each product's rank score
+	if scoreRules := rs.boostsAndBuries(true); len(scoreRules) > 0 {
+		scores := make(map[ProductID]float64, len(result))
+		for i, product := range result {
+			scores[product.ID] = float64(len(result)-i) / float64(len(result))
+		}
+		for _, rule := range scoreRules {
+			matched := matchingIDs(rule, result)
+			for _, id := range matched {
+				scores[id] += rule.signedScore()
+			}
+			record(rule, matched)
+		}
+		sort.SliceStable(result, func(i, j int) bool { return scores[result[i].ID] > scores[result[j].ID] })
+	}
+
+	// Phase 3: position moves. Boosted products move in result order and
+	// buried ones in reverse, and no move passes the previously moved match,
+	// so matches keep their relative order even when clamped at the ends.
+	for _, rule := range rs.boostsAndBuries(false) {
+		matched := matchingIDs(rule, result)
+		if rule.Action == ActionBoost {
+			bound := 0
+			for _, id := range matched {
+				var to int
+				result, to = moveProduct(result, id, -rule.Positions, bound, len(result)-1)
+				bound = to + 1
+			}
+		} else {
+			bound := len(result) - 1
+			for i := len(matched) - 1; i >= 0; i-- {
+				var to int
+				result, to = moveProduct(result, matched[i], rule.Positions, 0, bound)
+				bound = to - 1
+			}
+		}
+		record(rule, matched)
+	}
+
+	// Phase 4: pins take their position unless a higher-priority pin holds
+	// it, in which case they take the next free one
+	slots := make(map[int]Product)
+	pinned := make(map[ProductID]bool)
+	for _, rule := range rs.withAction(ActionPin) {
+		var ids []ProductID
+		for _, product := range result {
+			if pinned[product.ID] || !rule.Matches(product) {
+				continue
+			}
+			position := rule.Position - 1
+			for {
+				if _, taken := slots[position]; !taken {
+					break
+				}
+				position++
+			}
+			slots[position] = product
+			pinned[product.ID] = true
+			ids = append(ids, product.ID)
+		}
+		record(rule, ids)
+	}
+	if len(pinned) > 0 {
+		result = placePinned(result, slots, pinned)
+	}
+
+	return result, fired
+}
+
+// withAction returns the active rules with the given action in application order
+func (rs *RuleSet) withAction(action RuleAction) []MerchandisingRule {
+	var rules []MerchandisingRule
+	for _, rule := range rs.rules {
+		if rule.Action == action {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// boostsAndBuries returns the active boosts and buries that adjust scores,
+// or those that move positions
+func (rs *RuleSet) boostsAndBuries(byScore bool) []MerchandisingRule {
+	var rules []MerchandisingRule
+	for _, rule := range rs.rules {
+		if (rule.Action == ActionBoost || rule.Action == ActionBury) && (rule.Score != 0) == byScore {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// matchingIDs returns the IDs of the products the rule matches, in order
+func matchingIDs(rule MerchandisingRule, products ProductCollection) []ProductID {
+	var ids []ProductID
+	for _, product := range products {
+		if rule.Matches(product) {
+			ids = append(ids, product.ID)
+		}
+	}
+	return ids
+}
+
+// placePinned fills the pinned slots and places the remaining products in
+// order around them. Pins past the end follow the other products.
+func placePinned(products ProductCollection, slots map[int]Product, pinned map[ProductID]bool) ProductCollection {
+	rest := make(ProductCollection, 0, len(products)-len(pinned))
+	for _, product := range products {
+		if !pinned[product.ID] {
+			rest = append(rest, product)
+		}
+	}
+
+	positions := make([]int, 0, len(slots))
+	for position := range slots {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+
+	// Walk the pinned slots rather than every position up to them, so a pin
+	// far past the end costs no more than one near it
+	placed := make(ProductCollection, 0, len(products))
+	next := 0
+	for _, position := range positions {
+		for len(placed) < position && next < len(rest) {
+			placed = append(placed, rest[next])
+			next++
+		}
+		placed = append(placed, slots[position])
+	}
+	return append(placed, rest[next:]...)
+}
+
+// signedScore returns the score adjustment, negative for buries
+func (r MerchandisingRule) signedScore() float64 {
+	if r.Action == ActionBury {
+		return -r.Score
+	}
+	return r.Score
+}
+
+// moveProduct moves a product by offset places, clamped to the positions
+// between lowest and highest, and returns the position it moved to
+func moveProduct(products ProductCollection, id ProductID, offset, lowest, highest int) (ProductCollection, int) {
+	from := indexOfProduct(products, id)
+	if from < 0 {
+		return products, from
+	}
+
+	to := from + offset
+	if to < lowest {
+		to = lowest
+	}
+	if to > highest {
+		to = highest
+	}
+
+	product := products[from]
+	if to < from {
+		copy(products[to+1:from+1], products[to:from])
+	} else {
+		copy(products[from:to], products[from+1:to+1])
+	}
+	products[to] = product
+	return products, to
+}
+
+// indexOfProduct returns the position of the product with the ID, or -1
+func indexOfProduct(products ProductCollection, id ProductID) int {
+	for i, product := range products {
+		if product.ID == id {
+			return i
+		}
+	}
+	return -1
+}
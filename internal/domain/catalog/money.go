@@ -0,0 +1,308 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+// Commonly used currencies
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	JPY Currency = "JPY"
+)
+
+// DefaultCurrency is assumed for amounts without an explicit currency,
+// including prices decoded from the legacy float JSON format
+const DefaultCurrency = USD
+
+// maxPriceMajorUnits is the exclusive upper bound for a product price
+const maxPriceMajorUnits = 1000000
+
+// currencyExponents lists currencies whose minor unit is not 1/100
+var currencyExponents = map[Currency]int{
+	"BHD": 3,
+	"CLP": 0,
+	"ISK": 0,
+	"JOD": 3,
+	JPY:   0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+// currencySymbols lists display symbols for common currencies
+var currencySymbols = map[Currency]string{
+	USD: "$",
+	EUR: "€",
+	GBP: "£",
+	JPY: "¥",
+}
+
+// Exponent returns the number of decimal places in the currency's minor unit
+func (c Currency) Exponent() int {
+	if exponent, exists := currencyExponents[c.OrDefault()]; exists {
+		return exponent
+	}
+	return 2
+}
+
+// OrDefault returns the currency, or DefaultCurrency when it is unset
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+// IsValid checks that the currency looks like an ISO 4217 code
+func (c Currency) IsValid() bool {
+	code := c.OrDefault()
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Money is a fixed-point monetary amount stored as integer minor units
+// (e.g. cents), so sums and products are exact
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// Price is the monetary amount a product sells for
+type Price = Money
+
+// NewMoney creates an amount from minor units
+func NewMoney(minorUnits int64, currency Currency) Money {
+	return Money{Amount: minorUnits, Currency: currency.OrDefault()}
+}
+
+// MoneyFromFloat converts a float amount, rounding to the nearest minor unit
+func MoneyFromFloat(amount float64, currency Currency) Money {
+	scale := math.Pow10(currency.Exponent())
+	return NewMoney(int64(math.Round(amount*scale)), currency)
+}
+
+// ParseMoney parses a decimal string such as "12.99" exactly
+func ParseMoney(amount string, currency Currency) (Money, error) {
+	exponent := currency.Exponent()
+
+	value := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, hasFraction := strings.Cut(value, ".")
+	if whole == "" && (!hasFraction || fraction == "") {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if len(fraction) > exponent {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places for %s",
+			amount, exponent, currency.OrDefault())
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	digits := whole + fraction
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("invalid amount %q", amount)
+		}
+	}
+
+	minorUnits, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	if negative {
+		minorUnits = -minorUnits
+	}
+
+	return NewMoney(minorUnits, currency), nil
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1. It is only meaningful for
+// amounts in the same currency: amounts in different currencies are neither
+// converted nor scaled by their exponents, and are merely given a stable
+// order by amount, then currency code. Convert amounts before comparing them.
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	}
+
+	a, b := m.Currency.OrDefault(), other.Currency.OrDefault()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Add sums two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency.OrDefault() != other.Currency.OrDefault() {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch,
+			m.Currency.OrDefault(), other.Currency.OrDefault())
+	}
+	return NewMoney(m.Amount+other.Amount, m.Currency), nil
+}
+
+// Sub subtracts an amount in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(other.Mul(-1))
+}
+
+// Mul multiplies the amount by a quantity
+func (m Money) Mul(quantity int64) Money {
+	return NewMoney(m.Amount*quantity, m.Currency)
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// IsValid checks if the amount is a valid product price
+func (m Money) IsValid() bool {
+	return !m.IsNegative() && !m.ExceedsMaxPrice() && m.Currency.IsValid()
+}
+
+// ExceedsMaxPrice reports whether the amount is above the maximum product price
+func (m Money) ExceedsMaxPrice() bool {
+	return m.Cmp(MaxPrice(m.Currency)) > 0
+}
+
+// MaxPrice returns the highest valid product price in a currency,
+// e.g. 999999.99 for currencies with cents
+func MaxPrice(currency Currency) Money {
+	limit := int64(maxPriceMajorUnits)
+	for i := 0; i < currency.Exponent(); i++ {
+		limit *= 10
+	}
+	return NewMoney(limit-1, currency)
+}
+
+// ToFloat64 converts the amount to major units as a float, for display and ratios only
+func (m Money) ToFloat64() float64 {
+	return float64(m.Amount) / math.Pow10(m.Currency.Exponent())
+}
+
+// Decimal formats the amount in major units without a currency, e.g. "12.99"
+func (m Money) Decimal() string {
+	exponent := m.Currency.Exponent()
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	split := len(digits) - exponent
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// String formats the amount with its currency symbol, e.g. "$12.99"
+func (m Money) String() string {
+	currency := m.Currency.OrDefault()
+	decimal := m.Decimal()
+
+	symbol, exists := currencySymbols[currency]
+	if !exists {
+		return fmt.Sprintf("%s %s", currency, decimal)
+	}
+	if strings.HasPrefix(decimal, "-") {
+		return "-" + symbol + decimal[1:]
+	}
+	return symbol + decimal
+}
+
+// moneyJSON is the wire format of Money
+type moneyJSON struct {
+	Amount   int64    `json:"minor_units"`
+	Currency Currency `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as minor units with an explicit currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount, Currency: m.Currency.OrDefault()})
+}
+
+// UnmarshalJSON decodes the current object format as well as the legacy
+// format, where prices were plain numbers (or numeric strings) in DefaultCurrency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '{':
+		var decoded moneyJSON
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		*m = NewMoney(decoded.Amount, decoded.Currency)
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var amount string
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return err
+		}
+		parsed, err := ParseMoney(amount, DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	// Legacy float: parse the literal exactly, rounding only if it carries
+	// more precision than the currency supports (e.g. 12.999 or 1e-3)
+	if parsed, err := ParseMoney(string(data), DefaultCurrency); err == nil {
+		*m = parsed
+		return nil
+	}
+
+	amount, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid price %s: %w", data, err)
+	}
+	*m = MoneyFromFloat(amount, DefaultCurrency)
+	return nil
+}
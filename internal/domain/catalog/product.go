@@ -1,16 +1,19 @@
 package catalog
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+
+	"golang.org/x/text/language"
 )
 
 // ProductID represents a unique product identifier
 type ProductID int64
 
-// Price represents a product price with proper precision
-type Price float64
-
 // Product represents a product in the catalog domain
 // This is the core domain entity following DDD principles
 type Product struct {
@@ -20,6 +23,40 @@ type Product struct {
 	CreatedAt  time.Time `json:"created_at"`
 	SalesCount int       `json:"sales_count"`
 	ViewsCount int       `json:"views_count"`
+
+	// LocalizedNames holds translated names keyed by BCP 47 language tag
+	LocalizedNames map[string]string `json:"localized_names,omitempty"`
+
+	// ParentID links a variant (e.g. a size or color) to its parent product
+	ParentID ProductID `json:"parent_id,omitempty"`
+
+	// Category groups products for promotions and reporting
+	Category string `json:"category,omitempty"`
+
+	// RatingCount and RatingSum summarize star reviews; each review scores
+	// between MinRating and MaxRating stars
+	RatingCount int `json:"rating_count,omitempty"`
+	RatingSum   int `json:"rating_sum,omitempty"`
+
+	// Attributes holds typed custom attributes such as weight_kg or brand
+	Attributes Attributes `json:"attributes,omitempty"`
+
+	// StockQuantity and Availability describe inventory; products without
+	// an availability status are untracked and treated as in stock
+	StockQuantity int          `json:"stock_quantity,omitempty"`
+	Availability  Availability `json:"availability,omitempty"`
+
+	// CostPrice and ShippingCost are per-unit costs in the price currency;
+	// LossLeader allows the cost price to exceed the selling price
+	CostPrice    *Money `json:"cost_price,omitempty"`
+	ShippingCost *Money `json:"shipping_cost,omitempty"`
+	LossLeader   bool   `json:"loss_leader,omitempty"`
+
+	// Status is the lifecycle state; each transition records its timestamp
+	Status         LifecycleStatus `json:"status,omitempty"`
+	ActivatedAt    *time.Time      `json:"activated_at,omitempty"`
+	DiscontinuedAt *time.Time      `json:"discontinued_at,omitempty"`
+	ArchivedAt     *time.Time      `json:"archived_at,omitempty"`
 }
 
 // ProductValidationError represents validation errors for products
@@ -56,9 +93,53 @@ func (p Product) DaysOnMarket() int {
 	return int(time.Since(p.CreatedAt).Hours() / 24)
 }
 
-// RevenueGenerated calculates total revenue from this product
-func (p Product) RevenueGenerated() float64 {
-	return float64(p.Price) * float64(p.SalesCount)
+// RevenueGenerated calculates total revenue from this product exactly,
+// in the product's price currency
+func (p Product) RevenueGenerated() Money {
+	return p.Price.Mul(int64(p.SalesCount))
+}
+
+// Clone returns a copy of the product that shares no maps or pointers with
+// the original
+func (p Product) Clone() Product {
+	if p.LocalizedNames != nil {
+		names := make(map[string]string, len(p.LocalizedNames))
+		for tag, name := range p.LocalizedNames {
+			names[tag] = name
+		}
+		p.LocalizedNames = names
+	}
+	if p.Attributes != nil {
+		attributes := make(Attributes, len(p.Attributes))
+		for name, value := range p.Attributes {
+			attributes[name] = value
+		}
+		p.Attributes = attributes
+	}
+	p.CostPrice = cloneMoney(p.CostPrice)
+	p.ShippingCost = cloneMoney(p.ShippingCost)
+	p.ActivatedAt = cloneTime(p.ActivatedAt)
+	p.DiscontinuedAt = cloneTime(p.DiscontinuedAt)
+	p.ArchivedAt = cloneTime(p.ArchivedAt)
+	return p
+}
+
+// cloneMoney copies an optional amount
+func cloneMoney(amount *Money) *Money {
+	if amount == nil {
+		return nil
+	}
+	copied := *amount
+	return &copied
+}
+
+// cloneTime copies an optional timestamp
+func cloneTime(at *time.Time) *time.Time {
+	if at == nil {
+		return nil
+	}
+	copied := *at
+	return &copied
 }
 
 // Validation methods
@@ -77,6 +158,42 @@ func (p Product) Validate() error {
 		})
 	}
 
+	// Validate localized names
+	folded := make(map[string]string, len(p.LocalizedNames))
+	for _, tag := range sortedKeys(p.LocalizedNames) {
+		name := p.LocalizedNames[tag]
+		if tag == "" || name == "" || len(name) > 255 {
+			validationErrors = append(validationErrors, ProductValidationError{
+				Field:   "LocalizedNames",
+				Value:   tag,
+				Message: "locale and name must be set and names cannot exceed 255 characters",
+			})
+		} else if _, err := language.Parse(tag); err != nil {
+			validationErrors = append(validationErrors, ProductValidationError{
+				Field:   "LocalizedNames",
+				Value:   tag,
+				Message: "locale must be a well-formed BCP 47 language tag",
+			})
+		} else if other, ok := folded[strings.ToLower(tag)]; ok {
+			validationErrors = append(validationErrors, ProductValidationError{
+				Field:   "LocalizedNames",
+				Value:   tag,
+				Message: fmt.Sprintf("locale duplicates %q; tags are case-insensitive", other),
+			})
+		} else {
+			folded[strings.ToLower(tag)] = tag
+		}
+	}
+
+	// Validate ParentID
+	if p.ParentID < 0 || (p.ParentID != 0 && p.ParentID == p.ID) {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "ParentID",
+			Value:   p.ParentID,
+			Message: "must be a positive ID other than the product's own",
+		})
+	}
+
 	// Validate Name
 	if p.Name == "" {
 		validationErrors = append(validationErrors, ProductValidationError{
@@ -94,18 +211,60 @@ func (p Product) Validate() error {
 	}
 
 	// Validate Price
-	if p.Price < 0 {
+	if p.Price.IsNegative() {
 		validationErrors = append(validationErrors, ProductValidationError{
 			Field:   "Price",
 			Value:   p.Price,
 			Message: "cannot be negative",
 		})
 	}
-	if p.Price > 999999.99 {
+	if p.Price.ExceedsMaxPrice() {
 		validationErrors = append(validationErrors, ProductValidationError{
 			Field:   "Price",
 			Value:   p.Price,
-			Message: "exceeds maximum allowed value (999999.99)",
+			Message: fmt.Sprintf("exceeds maximum allowed value (%s)", MaxPrice(p.Price.Currency).Decimal()),
+		})
+	}
+	if !p.Price.Currency.IsValid() {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "Price",
+			Value:   p.Price.Currency,
+			Message: "currency must be a three-letter ISO 4217 code",
+		})
+	}
+
+	// Validate costs
+	costs := []struct {
+		field string
+		cost  *Money
+	}{
+		{"CostPrice", p.CostPrice},
+		{"ShippingCost", p.ShippingCost},
+	}
+	for _, c := range costs {
+		if c.cost == nil {
+			continue
+		}
+		if c.cost.IsNegative() {
+			validationErrors = append(validationErrors, ProductValidationError{
+				Field:   c.field,
+				Value:   *c.cost,
+				Message: "cannot be negative",
+			})
+		}
+		if c.cost.Currency.OrDefault() != p.Price.Currency.OrDefault() {
+			validationErrors = append(validationErrors, ProductValidationError{
+				Field:   c.field,
+				Value:   c.cost.Currency,
+				Message: fmt.Sprintf("currency must match price currency %s", p.Price.Currency.OrDefault()),
+			})
+		}
+	}
+	if p.CostPrice != nil && p.CostPrice.Amount > p.Price.Amount && !p.LossLeader {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "CostPrice",
+			Value:   *p.CostPrice,
+			Message: "cannot exceed price unless the product is a loss leader",
 		})
 	}
 
@@ -143,6 +302,56 @@ func (p Product) Validate() error {
 		})
 	}
 
+	// Validate inventory
+	if p.StockQuantity < 0 {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "StockQuantity",
+			Value:   p.StockQuantity,
+			Message: "cannot be negative",
+		})
+	}
+	if !p.Availability.IsValid() {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "Availability",
+			Value:   p.Availability,
+			Message: "must be one of in_stock, out_of_stock or backorder",
+		})
+	}
+
+	// Validate ratings
+	if p.RatingCount < 0 {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "RatingCount",
+			Value:   p.RatingCount,
+			Message: "cannot be negative",
+		})
+	}
+	if p.RatingSum < p.RatingCount*MinRating || p.RatingSum > p.RatingCount*MaxRating {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "RatingSum",
+			Value:   fmt.Sprintf("sum: %d, count: %d", p.RatingSum, p.RatingCount),
+			Message: fmt.Sprintf("average rating must be between %d and %d stars", MinRating, MaxRating),
+		})
+	}
+
+	// Validate custom attributes
+	if err := p.Attributes.Validate(); err != nil {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "Attributes",
+			Value:   len(p.Attributes),
+			Message: err.Error(),
+		})
+	}
+
+	// Validate lifecycle
+	if !p.Status.IsValid() {
+		validationErrors = append(validationErrors, ProductValidationError{
+			Field:   "Status",
+			Value:   p.Status,
+			Message: "must be one of draft, active, discontinued or archived",
+		})
+	}
+
 	// Business rule validation: Sales cannot exceed views
 	if p.SalesCount > p.ViewsCount {
 		validationErrors = append(validationErrors, ProductValidationError{
@@ -168,7 +377,7 @@ func (p Product) IsValid() bool {
 
 // String provides a detailed string representation for debugging
 func (p Product) String() string {
-	return fmt.Sprintf("Product{ID: %d, Name: %q, Price: %.2f, Sales: %d, Views: %d, Ratio: %.4f, Created: %s}",
+	return fmt.Sprintf("Product{ID: %d, Name: %q, Price: %s, Sales: %d, Views: %d, Ratio: %.4f, Created: %s}",
 		p.ID, p.Name, p.Price, p.SalesCount, p.ViewsCount, 
 		p.SalesConversionRatio(), p.CreatedAt.Format("2006-01-02"))
 }
@@ -195,8 +404,9 @@ func (pc ProductCollection) Validate() error {
 	return nil
 }
 
-// Copy creates a deep copy of the product collection
-// Ensures immutability in sorting operations
+// Copy creates a copy of the product collection that can be reordered
+// without affecting the original. Products still share their maps and
+// pointer fields; use Clone for a fully independent copy.
 func (pc ProductCollection) Copy() ProductCollection {
 	if pc == nil {
 		return nil
@@ -207,6 +417,32 @@ func (pc ProductCollection) Copy() ProductCollection {
 	return copied
 }
 
+// Clone creates a deep copy of the product collection that shares no maps
+// or pointers with the original
+func (pc ProductCollection) Clone() ProductCollection {
+	if pc == nil {
+		return nil
+	}
+
+	cloned := make(ProductCollection, len(pc))
+	for i, product := range pc {
+		cloned[i] = product.Clone()
+	}
+	return cloned
+}
+
+// Hash returns a content hash of the collection that changes whenever any
+// product field or the product order changes
+func (pc ProductCollection) Hash() string {
+	hasher := sha256.New()
+	encoder := json.NewEncoder(hasher)
+	for _, product := range pc {
+		// Encoding into a hash never fails for plain product values
+		_ = encoder.Encode(product)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
 // FilterHighPerformers returns only high-performing products
 func (pc ProductCollection) FilterHighPerformers() ProductCollection {
 	var highPerformers ProductCollection
@@ -218,13 +454,62 @@ func (pc ProductCollection) FilterHighPerformers() ProductCollection {
 	return highPerformers
 }
 
-// TotalRevenue calculates total revenue for all products in collection
-func (pc ProductCollection) TotalRevenue() float64 {
-	var total float64
+// FilterInStock returns only products that are in stock
+func (pc ProductCollection) FilterInStock() ProductCollection {
+	var inStock ProductCollection
+	for _, product := range pc {
+		if product.IsInStock() {
+			inStock = append(inStock, product)
+		}
+	}
+	return inStock
+}
+
+// TotalRevenue calculates the exact total revenue for all products in the
+// collection. Every product must be priced in the same currency; mixed
+// catalogs return ErrCurrencyMismatch and should use TotalRevenueIn or
+// TotalRevenueByCurrency.
+func (pc ProductCollection) TotalRevenue() (Money, error) {
+	if len(pc) == 0 {
+		return NewMoney(0, DefaultCurrency), nil
+	}
+
+	totals := pc.TotalRevenueByCurrency()
+	if len(totals) > 1 {
+		return Money{}, fmt.Errorf("%w: collection is priced in %d currencies", ErrCurrencyMismatch, len(totals))
+	}
+	return totals[pc[0].Price.Currency.OrDefault()], nil
+}
+
+// TotalRevenueIn calculates total revenue converted into the conversion's
+// target currency. Each product's revenue is converted before summing.
+func (pc ProductCollection) TotalRevenueIn(conversion *CurrencyConversion) (Money, error) {
+	total := NewMoney(0, conversion.Target)
 	for _, product := range pc {
-		total += product.RevenueGenerated()
+		revenue, err := conversion.Convert(product.RevenueGenerated())
+		if err != nil {
+			return Money{}, fmt.Errorf("product %d: %w", product.ID, err)
+		}
+		total.Amount += revenue.Amount
+	}
+	return total, nil
+}
+
+// TotalRevenueByCurrency calculates exact revenue totals per currency
+func (pc ProductCollection) TotalRevenueByCurrency() map[Currency]Money {
+	totals := make(map[Currency]Money)
+	for _, product := range pc {
+		revenue := product.RevenueGenerated()
+		currency := revenue.Currency.OrDefault()
+
+		total, exists := totals[currency]
+		if !exists {
+			total = NewMoney(0, currency)
+		}
+		// Totals are keyed by currency, so the addition cannot mismatch
+		totals[currency], _ = total.Add(revenue)
 	}
-	return total
+	return totals
 }
 
 // AverageConversionRatio calculates average conversion ratio
@@ -266,18 +551,3 @@ func (id ProductID) String() string {
 func (id ProductID) IsValid() bool {
 	return id > 0
 }
-
-// String returns the string representation of Price
-func (p Price) String() string {
-	return fmt.Sprintf("$%.2f", float64(p))
-}
-
-// IsValid checks if Price is valid
-func (p Price) IsValid() bool {
-	return p >= 0 && p <= 999999.99
-}
-
-// ToFloat64 converts Price to float64
-func (p Price) ToFloat64() float64 {
-	return float64(p)
-}
@@ -0,0 +1,67 @@
+package catalog
+
+// HasCost reports whether the product's cost price is known. Profit
+// strategies rank products without cost data after those with it.
+func (p Product) HasCost() bool {
+	return p.CostPrice != nil
+}
+
+// UnitCost returns the landed cost of one unit: cost price plus shipping.
+// Unknown costs count as zero.
+func (p Product) UnitCost() Money {
+	cost := NewMoney(0, p.Price.Currency)
+	if p.CostPrice != nil {
+		cost.Amount += p.CostPrice.Amount
+	}
+	if p.ShippingCost != nil {
+		cost.Amount += p.ShippingCost.Amount
+	}
+	return cost
+}
+
+// UnitProfit returns the profit made on one sale, in the price currency.
+// Validation guarantees costs share the price currency.
+func (p Product) UnitProfit() Money {
+	return NewMoney(p.Price.Amount-p.UnitCost().Amount, p.Price.Currency)
+}
+
+// GrossMargin returns unit profit as a fraction of the price, e.g. 0.25 for
+// a 25% margin. Loss leaders have a negative margin; free products have none.
+func (p Product) GrossMargin() float64 {
+	if p.Price.Amount == 0 {
+		return 0.0
+	}
+	return float64(p.UnitProfit().Amount) / float64(p.Price.Amount)
+}
+
+// TotalProfit calculates total profit from this product exactly, analogous
+// to RevenueGenerated
+func (p Product) TotalProfit() Money {
+	return p.UnitProfit().Mul(int64(p.SalesCount))
+}
+
+// ProfitPerView returns total profit in major currency units per product view
+func (p Product) ProfitPerView() float64 {
+	if p.ViewsCount == 0 {
+		return 0.0
+	}
+	return p.TotalProfit().ToFloat64() / float64(p.ViewsCount)
+}
+
+// TotalProfit calculates the exact total profit for all products in the
+// collection, in the currency of the first product. Products priced in other
+// currencies are skipped.
+func (pc ProductCollection) TotalProfit() Money {
+	if len(pc) == 0 {
+		return NewMoney(0, DefaultCurrency)
+	}
+
+	currency := pc[0].Price.Currency.OrDefault()
+	total := NewMoney(0, currency)
+	for _, product := range pc {
+		if product.Price.Currency.OrDefault() == currency {
+			total.Amount += product.TotalProfit().Amount
+		}
+	}
+	return total
+}
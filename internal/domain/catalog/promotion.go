@@ -0,0 +1,267 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DiscountType selects how a promotion reduces the price
+type DiscountType string
+
+// Discount types
+const (
+	DiscountPercentage DiscountType = "percentage"
+	DiscountFixed      DiscountType = "fixed"
+)
+
+// Promotion is a time-boxed discount scoped to products or categories.
+//
+// Promotions never stack. When several apply to a product at the same time,
+// precedence is:
+//  1. the highest Priority;
+//  2. product-scoped promotions over category-scoped ones;
+//  3. the largest discount for that product;
+//  4. the lowest promotion ID, so resolution is deterministic.
+type Promotion struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name,omitempty"`
+	Type     DiscountType `json:"type"`
+	Priority int          `json:"priority,omitempty"`
+
+	// Percentage is the discount for DiscountPercentage, e.g. 15 for 15% off
+	Percentage float64 `json:"percentage,omitempty"`
+
+	// Amount is the discount for DiscountFixed; it only applies to prices in
+	// the same currency
+	Amount Money `json:"amount"`
+
+	// StartsAt is inclusive; a zero EndsAt means the promotion never ends
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at,omitempty"`
+
+	// ProductIDs and Categories scope the promotion; at least one is required
+	ProductIDs []ProductID `json:"product_ids,omitempty"`
+	Categories []string    `json:"categories,omitempty"`
+}
+
+// Validate ensures the promotion is well formed
+func (p Promotion) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("promotion ID cannot be empty")
+	}
+
+	switch p.Type {
+	case DiscountPercentage:
+		if p.Percentage <= 0 || p.Percentage > 100 || math.IsNaN(p.Percentage) {
+			return fmt.Errorf("promotion %s: percentage must be in (0, 100]", p.ID)
+		}
+	case DiscountFixed:
+		if p.Amount.Amount <= 0 {
+			return fmt.Errorf("promotion %s: fixed discount must be positive", p.ID)
+		}
+		if !p.Amount.Currency.IsValid() {
+			return fmt.Errorf("promotion %s: invalid currency %s", p.ID, p.Amount.Currency)
+		}
+	default:
+		return fmt.Errorf("promotion %s: invalid discount type: %s", p.ID, p.Type)
+	}
+
+	if p.StartsAt.IsZero() {
+		return fmt.Errorf("promotion %s: start time must be set", p.ID)
+	}
+
+	if !p.EndsAt.IsZero() && !p.EndsAt.After(p.StartsAt) {
+		return fmt.Errorf("promotion %s: end time must be after start time", p.ID)
+	}
+
+	if len(p.ProductIDs) == 0 && len(p.Categories) == 0 {
+		return fmt.Errorf("promotion %s: must be scoped to products or categories", p.ID)
+	}
+
+	return nil
+}
+
+// IsActiveAt reports whether the promotion runs at the given time
+func (p Promotion) IsActiveAt(at time.Time) bool {
+	if at.Before(p.StartsAt) {
+		return false
+	}
+	return p.EndsAt.IsZero() || at.Before(p.EndsAt)
+}
+
+// targetsProduct reports whether the promotion names the product explicitly
+func (p Promotion) targetsProduct(product Product) bool {
+	return containsProductID(p.ProductIDs, product.ID)
+}
+
+// AppliesTo reports whether the promotion's scope covers the product
+func (p Promotion) AppliesTo(product Product) bool {
+	if p.targetsProduct(product) {
+		return true
+	}
+	if product.Category == "" {
+		return false
+	}
+	for _, category := range p.Categories {
+		if category == product.Category {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns the amount taken off the product's list price, never
+// more than the price itself
+func (p Promotion) Discount(product Product) Money {
+	price := product.Price
+	discount := NewMoney(0, price.Currency)
+
+	switch p.Type {
+	case DiscountPercentage:
+		discount.Amount = int64(math.Round(float64(price.Amount) * p.Percentage / 100))
+	case DiscountFixed:
+		if p.Amount.Currency.OrDefault() == price.Currency.OrDefault() {
+			discount.Amount = p.Amount.Amount
+		}
+	}
+
+	if discount.Amount > price.Amount {
+		discount.Amount = price.Amount
+	}
+	return discount
+}
+
+// PriceResolver determines the price a product currently sells for
+type PriceResolver interface {
+	// EffectivePrice returns the product's price after any discounts
+	EffectivePrice(product Product) Money
+}
+
+// PriceResolvingSorter is implemented by sorters whose ordering depends on
+// the selling price. WithPriceResolver returns a sorter that compares the
+// resolver's effective prices instead of list prices.
+type PriceResolvingSorter interface {
+	Sorter
+
+	// WithPriceResolver returns a copy of the sorter that uses effective prices
+	WithPriceResolver(resolver PriceResolver) Sorter
+}
+
+// PromotionProvider supplies the promotions known to the catalog
+type PromotionProvider interface {
+	// Promotions returns all configured promotions, active or not
+	Promotions(ctx context.Context) ([]Promotion, error)
+}
+
+// PromotionResolver resolves effective prices from the promotions active at
+// one point in time, following the precedence documented on Promotion
+type PromotionResolver struct {
+	at         time.Time
+	promotions []Promotion
+}
+
+// NewPromotionResolver creates a resolver for the promotions active at the given time
+func NewPromotionResolver(promotions []Promotion, at time.Time) (*PromotionResolver, error) {
+	active := make([]Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		if err := promotion.Validate(); err != nil {
+			return nil, err
+		}
+		if promotion.IsActiveAt(at) {
+			active = append(active, promotion)
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool { return active[i].ID < active[j].ID })
+
+	return &PromotionResolver{at: at, promotions: active}, nil
+}
+
+// At returns the time the resolver prices products at
+func (r *PromotionResolver) At() time.Time {
+	return r.at
+}
+
+// ActivePromotions returns the promotions running at the resolver's time
+func (r *PromotionResolver) ActivePromotions() []Promotion {
+	return append([]Promotion(nil), r.promotions...)
+}
+
+// Resolve returns the promotion that wins for the product, if any
+func (r *PromotionResolver) Resolve(product Product) (*Promotion, bool) {
+	var (
+		best         *Promotion
+		bestDiscount Money
+	)
+
+	for i := range r.promotions {
+		candidate := &r.promotions[i]
+		if !candidate.AppliesTo(product) {
+			continue
+		}
+
+		discount := candidate.Discount(product)
+		if best == nil || outranks(candidate, discount, best, bestDiscount, product) {
+			best, bestDiscount = candidate, discount
+		}
+	}
+
+	return best, best != nil
+}
+
+// outranks reports whether promotion a takes precedence over promotion b for the product
+func outranks(a *Promotion, discountA Money, b *Promotion, discountB Money, product Product) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if a.targetsProduct(product) != b.targetsProduct(product) {
+		return a.targetsProduct(product)
+	}
+	if discountA.Amount != discountB.Amount {
+		return discountA.Amount > discountB.Amount
+	}
+	return a.ID < b.ID
+}
+
+// EffectivePrice returns the product's price after the winning promotion
+func (r *PromotionResolver) EffectivePrice(product Product) Money {
+	promotion, ok := r.Resolve(product)
+	if !ok {
+		return product.Price
+	}
+
+	price := product.Price
+	price.Amount -= promotion.Discount(product).Amount
+	return price
+}
+
+// EffectivePrices returns the discounted price of every product whose
+// effective price differs from its list price
+func (r *PromotionResolver) EffectivePrices(products ProductCollection) map[ProductID]Money {
+	prices := make(map[ProductID]Money)
+	for _, product := range products {
+		if price := r.EffectivePrice(product); price != product.Price {
+			prices[product.ID] = price
+		}
+	}
+	return prices
+}
+
+// Fingerprint identifies the set of active promotions, so results priced
+// with the same promotions can be shared
+func (r *PromotionResolver) Fingerprint() string {
+	if len(r.promotions) == 0 {
+		return ""
+	}
+
+	// Encoding plain promotion values never fails
+	data, _ := json.Marshal(r.promotions)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
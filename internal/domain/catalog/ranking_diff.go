@@ -0,0 +1,271 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Defaults for ranking comparisons
+const (
+	DefaultDiffTopK   = 10
+	DefaultDiffMovers = 10
+)
+
+// RankingDiffOptions configures a ranking comparison
+type RankingDiffOptions struct {
+	// TopK is the cut-off for top-K entries, exits and overlap; zero uses DefaultDiffTopK
+	TopK int `json:"top_k,omitempty"`
+
+	// Movers is how many of the biggest movers to report; zero uses DefaultDiffMovers
+	Movers int `json:"movers,omitempty"`
+}
+
+// Validate ensures the options are well formed
+func (o RankingDiffOptions) Validate() error {
+	if o.TopK < 0 {
+		return fmt.Errorf("top K cannot be negative")
+	}
+	if o.Movers < 0 {
+		return fmt.Errorf("movers cannot be negative")
+	}
+	return nil
+}
+
+// PositionDelta is a product's 1-based position in two rankings. A zero
+// position means the product is missing from that ranking.
+type PositionDelta struct {
+	ProductID ProductID `json:"product_id"`
+	Before    int       `json:"before"`
+	After     int       `json:"after"`
+
+	// Delta is how many places the product rose, negative when it fell; zero
+	// unless the product is in both rankings
+	Delta int `json:"delta"`
+}
+
+// RankingDiff compares a base ranking with a candidate ranking
+type RankingDiff struct {
+	BaseStrategy      SortStrategy `json:"base_strategy"`
+	CandidateStrategy SortStrategy `json:"candidate_strategy"`
+	TopK              int          `json:"top_k"`
+
+	// Positions holds every product of either ranking, in candidate order
+	// followed by products only in the base ranking
+	Positions []PositionDelta `json:"positions"`
+
+	// BiggestMovers are the products in both rankings with the largest
+	// absolute deltas
+	BiggestMovers []PositionDelta `json:"biggest_movers"`
+
+	// EnteredTopK and ExitedTopK list products that joined or left the top K
+	EnteredTopK []ProductID `json:"entered_top_k"`
+	ExitedTopK  []ProductID `json:"exited_top_k"`
+
+	// OverlapAtK is the share of the top K found in both rankings; when a
+	// ranking is shorter than K the share is of its length instead
+	OverlapAtK float64 `json:"overlap_at_k"`
+
+	// CommonProducts is how many products are in both rankings; the rank
+	// correlations are computed over them and are 1 when fewer than two remain
+	CommonProducts int     `json:"common_products"`
+	KendallTau     float64 `json:"kendall_tau"`
+	SpearmanRho    float64 `json:"spearman_rho"`
+}
+
+// CompareRankings reports how the candidate ranking differs from the base
+func CompareRankings(base, candidate *SortResult, options RankingDiffOptions) (*RankingDiff, error) {
+	if base == nil || candidate == nil {
+		return nil, fmt.Errorf("both rankings are required")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	k := options.TopK
+	if k == 0 {
+		k = DefaultDiffTopK
+	}
+	movers := options.Movers
+	if movers == 0 {
+		movers = DefaultDiffMovers
+	}
+
+	before, err := rankPositions(base.Products)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base ranking: %w", err)
+	}
+	after, err := rankPositions(candidate.Products)
+	if err != nil {
+		return nil, fmt.Errorf("invalid candidate ranking: %w", err)
+	}
+
+	diff := &RankingDiff{
+		BaseStrategy:      base.Strategy,
+		CandidateStrategy: candidate.Strategy,
+		TopK:              k,
+		EnteredTopK:       []ProductID{},
+		ExitedTopK:        []ProductID{},
+	}
+
+	// Common products ordered by base position, for the rank correlations
+	var common []PositionDelta
+	for _, product := range candidate.Products {
+		delta := PositionDelta{ProductID: product.ID, Before: before[product.ID], After: after[product.ID]}
+		if delta.Before > 0 {
+			delta.Delta = delta.Before - delta.After
+			common = append(common, delta)
+		}
+		diff.Positions = append(diff.Positions, delta)
+
+		if delta.After <= k && (delta.Before == 0 || delta.Before > k) {
+			diff.EnteredTopK = append(diff.EnteredTopK, product.ID)
+		}
+	}
+
+	overlap := 0
+	for _, product := range base.Products {
+		position := before[product.ID]
+		if _, ranked := after[product.ID]; !ranked {
+			diff.Positions = append(diff.Positions, PositionDelta{ProductID: product.ID, Before: position})
+		}
+		if position <= k {
+			if candidatePosition, ranked := after[product.ID]; ranked && candidatePosition <= k {
+				overlap++
+			} else {
+				diff.ExitedTopK = append(diff.ExitedTopK, product.ID)
+			}
+		}
+	}
+	// Rankings shorter than K can only share as many products as the shorter one holds
+	size := k
+	if len(base.Products) < size {
+		size = len(base.Products)
+	}
+	if len(candidate.Products) < size {
+		size = len(candidate.Products)
+	}
+	switch {
+	case size > 0:
+		diff.OverlapAtK = float64(overlap) / float64(size)
+	case len(base.Products) == len(candidate.Products):
+		// Two empty rankings agree completely
+		diff.OverlapAtK = 1
+	}
+
+	sort.Slice(common, func(i, j int) bool { return common[i].Before < common[j].Before })
+	diff.CommonProducts = len(common)
+	diff.KendallTau, diff.SpearmanRho = rankCorrelations(common)
+
+	diff.BiggestMovers = biggestMovers(common, movers)
+
+	return diff, nil
+}
+
+// rankPositions maps each product to its 1-based position, rejecting rankings
+// that list a product twice
+func rankPositions(products ProductCollection) (map[ProductID]int, error) {
+	positions := make(map[ProductID]int, len(products))
+	for i, product := range products {
+		if _, duplicate := positions[product.ID]; duplicate {
+			return nil, fmt.Errorf("product %d is ranked twice", product.ID)
+		}
+		positions[product.ID] = i + 1
+	}
+	return positions, nil
+}
+
+// biggestMovers returns up to n products with the largest absolute deltas,
+// preferring the better candidate position on ties
+func biggestMovers(common []PositionDelta, n int) []PositionDelta {
+	var moved []PositionDelta
+	for _, delta := range common {
+		if delta.Delta != 0 {
+			moved = append(moved, delta)
+		}
+	}
+
+	sort.Slice(moved, func(i, j int) bool {
+		a, b := abs(moved[i].Delta), abs(moved[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return moved[i].After < moved[j].After
+	})
+
+	if len(moved) > n {
+		moved = moved[:n]
+	}
+	if moved == nil {
+		moved = []PositionDelta{}
+	}
+	return moved
+}
+
+// rankCorrelations returns Kendall's tau and Spearman's rho between the base
+// and candidate orders of the common products, given in base order. Products
+// are re-ranked 1..n among themselves so missing products do not skew rho.
+func rankCorrelations(common []PositionDelta) (float64, float64) {
+	n := len(common)
+	if n < 2 {
+		return 1, 1
+	}
+
+	// Candidate positions in base order; every inversion is a discordant pair
+	order := make([]int, n)
+	for i := range common {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return common[order[i]].After < common[order[j]].After })
+
+	candidateRank := make([]int, n)
+	for rank, index := range order {
+		candidateRank[index] = rank
+	}
+
+	pairs := float64(n) * float64(n-1) / 2
+	discordant := countInversions(append([]int(nil), candidateRank...))
+	tau := 1 - 2*float64(discordant)/pairs
+
+	sumSquares := 0.0
+	for baseRank, rank := range candidateRank {
+		d := float64(baseRank - rank)
+		sumSquares += d * d
+	}
+	rho := 1 - 6*sumSquares/(float64(n)*(float64(n)*float64(n)-1))
+
+	return tau, rho
+}
+
+// countInversions counts pairs i < j with values[i] > values[j] using a merge
+// sort, sorting values in place
+func countInversions(values []int) int64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	mid := len(values) / 2
+	left := append([]int(nil), values[:mid]...)
+	right := append([]int(nil), values[mid:]...)
+	inversions := countInversions(left) + countInversions(right)
+
+	i, j := 0, 0
+	for k := range values {
+		if j >= len(right) || (i < len(left) && left[i] <= right[j]) {
+			values[k] = left[i]
+			i++
+		} else {
+			values[k] = right[j]
+			j++
+			inversions += int64(len(left) - i)
+		}
+	}
+	return inversions
+}
+
+// abs returns the absolute value of an integer
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,68 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+)
+
+// Star ratings are whole numbers in this range
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// RatingPrior is the belief about a product's rating before it has reviews.
+// The Bayesian average treats every product as if it already had Weight
+// reviews averaging Mean, so a handful of reviews cannot outrank a large,
+// consistently good track record.
+type RatingPrior struct {
+	Mean   float64 `json:"mean"`
+	Weight float64 `json:"weight"`
+}
+
+// DefaultRatingPrior assumes an average 3-star product worth ten reviews
+var DefaultRatingPrior = RatingPrior{Mean: 3.0, Weight: 10}
+
+// Validate ensures the prior is usable
+func (r RatingPrior) Validate() error {
+	if math.IsNaN(r.Mean) || r.Mean < MinRating || r.Mean > MaxRating {
+		return fmt.Errorf("prior mean must be between %d and %d", MinRating, MaxRating)
+	}
+	if math.IsNaN(r.Weight) || math.IsInf(r.Weight, 0) || r.Weight < 0 {
+		return fmt.Errorf("prior weight cannot be negative")
+	}
+	return nil
+}
+
+// AverageRating returns the mean star rating, or zero without reviews
+func (p Product) AverageRating() float64 {
+	if p.RatingCount == 0 {
+		return 0.0
+	}
+	return float64(p.RatingSum) / float64(p.RatingCount)
+}
+
+// BayesianRating returns the rating shrunk toward the prior mean:
+// (Weight*Mean + RatingSum) / (Weight + RatingCount). Products without
+// reviews score the prior mean.
+func (p Product) BayesianRating(prior RatingPrior) float64 {
+	weight := prior.Weight + float64(p.RatingCount)
+	if weight == 0 {
+		return prior.Mean
+	}
+	return (prior.Weight*prior.Mean + float64(p.RatingSum)) / weight
+}
+
+// AverageRating returns the mean rating across all reviews in the
+// collection, a natural choice for the prior mean
+func (pc ProductCollection) AverageRating() float64 {
+	var sum, count int
+	for _, product := range pc {
+		sum += product.RatingSum
+		count += product.RatingCount
+	}
+	if count == 0 {
+		return 0.0
+	}
+	return float64(sum) / float64(count)
+}
@@ -2,9 +2,18 @@ package catalog
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 )
 
+// ErrProductNotFound is returned when a product does not exist in the repository
+var ErrProductNotFound = errors.New("product not found")
+
+// ErrIndexStale is returned when a sorted index cannot serve a strategy
+// because active promotions or exchange rates change its order
+var ErrIndexStale = errors.New("sorted index is stale")
+
 // Repository defines the contract for product data access
 // Following Repository pattern for clean architecture
 type Repository interface {
@@ -24,26 +33,56 @@ type Repository interface {
 	GetProductCount(ctx context.Context, filter ProductFilter) (int, error)
 }
 
+// IndexedRepository is a Repository that maintains a sorted index per strategy,
+// updated on every write, so sorted pages are read without re-sorting. Only
+// active products are indexed. Indexes rank by the stored list price, so
+// price-dependent strategies fail with ErrIndexStale while promotions are
+// active or exchange rates are configured; sort with the Service instead.
+// Merchandising rules are never applied.
+type IndexedRepository interface {
+	Repository
+
+	// GetSortedPage returns up to limit products ranked by strategy, skipping offset
+	GetSortedPage(ctx context.Context, strategy SortStrategy, offset, limit int) (ProductCollection, error)
+
+	// GetSortedPageAfter returns the page following the request cursor
+	GetSortedPageAfter(ctx context.Context, strategy SortStrategy, page PageRequest) (*PageResult, error)
+}
+
 // ProductFilter represents filtering criteria for product queries
 type ProductFilter struct {
-	IDs           []ProductID `json:"ids,omitempty"`
-	NameContains  string      `json:"name_contains,omitempty"`
-	MinPrice      *Price      `json:"min_price,omitempty"`
-	MaxPrice      *Price      `json:"max_price,omitempty"`
-	MinSales      *int        `json:"min_sales,omitempty"`
-	MaxSales      *int        `json:"max_sales,omitempty"`
-	MinViews      *int        `json:"min_views,omitempty"`
-	MaxViews      *int        `json:"max_views,omitempty"`
-	CreatedAfter  *time.Time  `json:"created_after,omitempty"`
-	CreatedBefore *time.Time  `json:"created_before,omitempty"`
-	Limit         int         `json:"limit,omitempty"`
-	Offset        int         `json:"offset,omitempty"`
-}
-
-// IsEmpty returns true if no filters are applied
+	IDs          []ProductID `json:"ids,omitempty"`
+	NameContains string      `json:"name_contains,omitempty"`
+	Categories   []string    `json:"categories,omitempty"`
+
+	// MinPrice and MaxPrice only match products priced in their currency
+	MinPrice      *Price     `json:"min_price,omitempty"`
+	MaxPrice      *Price     `json:"max_price,omitempty"`
+	MinSales      *int       `json:"min_sales,omitempty"`
+	MaxSales      *int       `json:"max_sales,omitempty"`
+	MinViews      *int       `json:"min_views,omitempty"`
+	MaxViews      *int       `json:"max_views,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	InStockOnly   bool       `json:"in_stock_only,omitempty"`
+
+	// Attributes lists predicates over custom attributes; all must match
+	Attributes []AttributePredicate `json:"attributes,omitempty"`
+
+	// IncludeInactive returns draft, discontinued and archived products too;
+	// by default only active products match
+	IncludeInactive bool `json:"include_inactive,omitempty"`
+
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+// IsEmpty returns true if no filters are applied beyond the default
+// exclusion of inactive products
 func (f ProductFilter) IsEmpty() bool {
 	return len(f.IDs) == 0 &&
 		f.NameContains == "" &&
+		len(f.Categories) == 0 &&
 		f.MinPrice == nil &&
 		f.MaxPrice == nil &&
 		f.MinSales == nil &&
@@ -51,5 +90,85 @@ func (f ProductFilter) IsEmpty() bool {
 		f.MinViews == nil &&
 		f.MaxViews == nil &&
 		f.CreatedAfter == nil &&
-		f.CreatedBefore == nil
+		f.CreatedBefore == nil &&
+		!f.InStockOnly &&
+		len(f.Attributes) == 0
+}
+
+// Matches reports whether a product satisfies every criterion in the filter.
+// Limit and Offset are pagination controls and are not considered.
+func (f ProductFilter) Matches(product Product) bool {
+	if !f.IncludeInactive && !product.IsActive() {
+		return false
+	}
+	if len(f.IDs) > 0 && !containsProductID(f.IDs, product.ID) {
+		return false
+	}
+	if f.NameContains != "" &&
+		!strings.Contains(strings.ToLower(product.Name), strings.ToLower(f.NameContains)) {
+		return false
+	}
+	if len(f.Categories) > 0 && !containsString(f.Categories, product.Category) {
+		return false
+	}
+	if f.MinPrice != nil && !priceAtLeast(product.Price, *f.MinPrice) {
+		return false
+	}
+	if f.MaxPrice != nil && !priceAtLeast(*f.MaxPrice, product.Price) {
+		return false
+	}
+	if f.MinSales != nil && product.SalesCount < *f.MinSales {
+		return false
+	}
+	if f.MaxSales != nil && product.SalesCount > *f.MaxSales {
+		return false
+	}
+	if f.MinViews != nil && product.ViewsCount < *f.MinViews {
+		return false
+	}
+	if f.MaxViews != nil && product.ViewsCount > *f.MaxViews {
+		return false
+	}
+	if f.CreatedAfter != nil && !product.CreatedAt.After(*f.CreatedAfter) {
+		return false
+	}
+	if f.CreatedBefore != nil && !product.CreatedAt.Before(*f.CreatedBefore) {
+		return false
+	}
+	if f.InStockOnly && !product.IsInStock() {
+		return false
+	}
+	for _, predicate := range f.Attributes {
+		if !predicate.Matches(product) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsProductID checks if an ID is present in the list
+func containsProductID(ids []ProductID, id ProductID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString checks if a value is present in the list
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// priceAtLeast reports whether price is at least bound. Prices in another
+// currency than the bound never satisfy it, since amounts in different
+// currencies cannot be compared without a conversion.
+func priceAtLeast(price, bound Money) bool {
+	return price.Currency.OrDefault() == bound.Currency.OrDefault() && price.Cmp(bound) >= 0
 }
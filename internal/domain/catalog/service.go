@@ -1,8 +1,13 @@
 package catalog
 
 import (
+	"container/heap"
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -12,9 +17,73 @@ import (
 type Service interface {
 	// SortProducts sorts a collection of products using the specified strategy
 	SortProducts(ctx context.Context, products ProductCollection, strategy SortStrategy) (*SortResult, error)
+
+	// SortProductsInCurrency sorts products comparing monetary amounts in the target currency
+	SortProductsInCurrency(ctx context.Context, products ProductCollection, strategy SortStrategy, currency Currency) (*SortResult, error)
+
+	// SortProductsWithOptions sorts products with optional currency conversion and stock demotion
+	SortProductsWithOptions(ctx context.Context, products ProductCollection, strategy SortStrategy, options SortOptions) (*SortResult, error)
 	
 	// BatchSort sorts products using multiple strategies simultaneously
 	BatchSort(ctx context.Context, products ProductCollection, strategies SortStrategySet) (*BatchSortResult, error)
+
+	// FuseStrategies sorts products with several strategies and merges the
+	// rankings into one consensus ranking
+	FuseStrategies(ctx context.Context, products ProductCollection, strategies SortStrategySet, options FusionOptions) (*SortResult, error)
+
+	// CompareStrategies sorts products with two strategies and reports how
+	// the candidate ranking differs from the base ranking
+	CompareStrategies(ctx context.Context, products ProductCollection, base, candidate SortStrategy, options RankingDiffOptions) (*RankingDiff, error)
+
+	// EvaluateStrategies scores each strategy's ranking against relevance
+	// judgments and returns a leaderboard
+	EvaluateStrategies(ctx context.Context, products ProductCollection, strategies SortStrategySet, judgments Judgments, k int) (*EvaluationReport, error)
+
+	// SimulateStrategies estimates the clicks, sales and revenue each
+	// strategy's ranking would produce under a position-based click model
+	SimulateStrategies(ctx context.Context, products ProductCollection, strategies SortStrategySet, options SimulationOptions) (*SimulationReport, error)
+
+	// SaveExperiment validates and stores an experiment, replacing one with the same name
+	SaveExperiment(ctx context.Context, experiment Experiment) error
+
+	// ListExperiments returns every experiment, running or not, ordered by name
+	ListExperiments(ctx context.Context) ([]Experiment, error)
+
+	// DeleteExperiment removes an experiment
+	DeleteExperiment(ctx context.Context, experiment string) error
+
+	// AssignExperiment returns the variant of an experiment a user or session is served
+	AssignExperiment(ctx context.Context, experiment, unitID string) (*ExperimentAssignment, error)
+
+	// SortForExperiment sorts products with the strategy of the unit's experiment variant
+	SortForExperiment(ctx context.Context, products ProductCollection, experiment, unitID string, options SortOptions) (*SortResult, error)
+
+	// RecordExperimentEvents ingests impression, click and purchase events of experiment variants
+	RecordExperimentEvents(ctx context.Context, events []ExperimentEvent) error
+
+	// ExperimentReport reports per-variant outcomes and significance tests for an experiment
+	ExperimentReport(ctx context.Context, experiment string, options ReportOptions) (*ExperimentReport, error)
+
+	// CreateBandit stores a new multi-armed bandit over sort strategies
+	CreateBandit(ctx context.Context, config BanditConfig) error
+
+	// ListBandits returns the state of every bandit ordered by name
+	ListBandits(ctx context.Context) ([]BanditState, error)
+
+	// SelectBandit asks a bandit for the strategy to serve the next request with
+	SelectBandit(ctx context.Context, bandit string) (*BanditSelection, error)
+
+	// SortWithBandit sorts products with the strategy a bandit picks for this request
+	SortWithBandit(ctx context.Context, products ProductCollection, bandit string, options SortOptions) (*SortResult, error)
+
+	// RecordBanditReward updates a bandit with the reward of a request it served
+	RecordBanditReward(ctx context.Context, reward BanditReward) error
+
+	// SortPage sorts products and returns the page following the request cursor
+	SortPage(ctx context.Context, products ProductCollection, strategy SortStrategy, page PageRequest) (*PageResult, error)
+
+	// StreamSort sorts products from an iterator and emits them one at a time
+	StreamSort(ctx context.Context, source ProductIterator, strategy SortStrategy, options StreamOptions, emit ProductEmitter) (*StreamSortResult, error)
 	
 	// GetSupportedStrategies returns all supported sorting strategies
 	GetSupportedStrategies() SortStrategySet
@@ -27,22 +96,232 @@ type Service interface {
 type DefaultService struct {
 	sorterFactory SorterFactory
 	logger        *zap.Logger
+	inflight      *sortCallGroup
+	exchangeRates ExchangeRateProvider
+	promotions    PromotionProvider
+	rules         MerchandisingRuleProvider
+	experiments   ExperimentStore
+	outcomes      *OutcomeTracker
+	eventLog      ExperimentEventLog
+	publisher     EventPublisher
+
+	// outcomesMu guards replaying the event log into outcomes once
+	outcomesMu     sync.Mutex
+	outcomesLoaded bool
+
+	// banditLocks serialise each bandit's updates with their persistence so
+	// its stored state never goes back in time; banditMu only guards the
+	// loaded bandits, so different bandits never wait on each other
+	banditLocks   [banditLockStripes]sync.Mutex
+	banditMu      sync.Mutex
+	bandits       BanditStore
+	loadedBandits map[string]*Bandit
+}
+
+// banditLockStripes is the number of locks bandit names are spread over
+const banditLockStripes = 64
+
+// ServiceOption configures optional DefaultService dependencies
+type ServiceOption func(*DefaultService)
+
+// WithExchangeRates sets the provider used for multi-currency sorting
+func WithExchangeRates(provider ExchangeRateProvider) ServiceOption {
+	return func(s *DefaultService) {
+		s.exchangeRates = provider
+	}
+}
+
+// WithPromotions sets the provider of promotions used to resolve effective
+// prices. Without one, products are always ranked by list price.
+func WithPromotions(provider PromotionProvider) ServiceOption {
+	return func(s *DefaultService) {
+		s.promotions = provider
+	}
+}
+
+// WithMerchandisingRules sets the provider of merchandising rules applied
+// after the sorter ranks products
+func WithMerchandisingRules(provider MerchandisingRuleProvider) ServiceOption {
+	return func(s *DefaultService) {
+		s.rules = provider
+	}
+}
+
+// WithExperiments sets the store of A/B experiments that assign strategies
+// to users and sessions
+func WithExperiments(store ExperimentStore) ServiceOption {
+	return func(s *DefaultService) {
+		s.experiments = store
+	}
+}
+
+// WithExperimentEventLog sets the log that persists experiment events. It is
+// replayed into the outcomes on first use, so reports survive restarts.
+func WithExperimentEventLog(log ExperimentEventLog) ServiceOption {
+	return func(s *DefaultService) {
+		s.eventLog = log
+	}
+}
+
+// WithEventPublisher sets the publisher that receives experiment alerts
+func WithEventPublisher(publisher EventPublisher) ServiceOption {
+	return func(s *DefaultService) {
+		s.publisher = publisher
+	}
+}
+
+// WithBandits sets the store that persists multi-armed bandit state
+func WithBandits(store BanditStore) ServiceOption {
+	return func(s *DefaultService) {
+		s.bandits = store
+	}
 }
 
 // NewService creates a new catalog service with dependencies
-func NewService(factory SorterFactory, logger *zap.Logger) Service {
-	return &DefaultService{
+func NewService(factory SorterFactory, logger *zap.Logger, options ...ServiceOption) Service {
+	service := &DefaultService{
 		sorterFactory: factory,
 		logger:        logger,
+		inflight:      newSortCallGroup(),
+		outcomes:      NewOutcomeTracker(),
+		loadedBandits: make(map[string]*Bandit),
+	}
+	for _, option := range options {
+		option(service)
 	}
+	return service
 }
 
-// SortProducts implements the core sorting business logic
+// SortProducts implements the core sorting business logic. Only active products
+// are ranked; use SortProductsWithOptions to include inactive ones. Concurrent
+// identical requests are coalesced so the catalog is validated and sorted only
+// once; every caller still receives its own copy of the result.
 func (s *DefaultService) SortProducts(ctx context.Context, products ProductCollection, strategy SortStrategy) (*SortResult, error) {
+	if products == nil {
+		return nil, fmt.Errorf("sort request validation failed: products collection cannot be nil")
+	}
+
+	modifiers, err := s.resolveModifiers(ctx, SortOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.coalescedSort(ctx, products, strategy, modifiers)
+}
+
+// SortProductsInCurrency sorts products with prices and revenue converted into
+// the target currency using the current exchange rate snapshot. The result
+// records the currency and the snapshot that were used.
+func (s *DefaultService) SortProductsInCurrency(ctx context.Context, products ProductCollection, strategy SortStrategy, currency Currency) (*SortResult, error) {
+	return s.SortProductsWithOptions(ctx, products, strategy, SortOptions{Currency: currency})
+}
+
+// SortProductsWithOptions sorts products by strategy and then applies the
+// requested modifiers. Requests with the same options are coalesced like
+// SortProducts.
+func (s *DefaultService) SortProductsWithOptions(ctx context.Context, products ProductCollection, strategy SortStrategy, options SortOptions) (*SortResult, error) {
+	if products == nil {
+		return nil, fmt.Errorf("sort request validation failed: products collection cannot be nil")
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("sort request validation failed: %w", err)
+	}
+
+	modifiers, err := s.resolveModifiers(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.coalescedSort(ctx, products, strategy, modifiers)
+}
+
+// resolveModifiers loads the exchange rates, promotions and merchandising
+// rules a sort needs
+func (s *DefaultService) resolveModifiers(ctx context.Context, options SortOptions) (sortModifiers, error) {
+	modifiers := sortModifiers{stock: options.Stock, diversity: options.Diversity, includeInactive: options.IncludeInactive,
+		variants: options.Variants, collation: options.Collation, explain: options.Explain}
+	if options.Currency != "" {
+		if s.exchangeRates == nil {
+			return sortModifiers{}, fmt.Errorf("no exchange rate provider configured")
+		}
+
+		snapshot, err := s.exchangeRates.Snapshot(ctx)
+		if err != nil {
+			return sortModifiers{}, fmt.Errorf("failed to load exchange rates: %w", err)
+		}
+
+		modifiers.conversion, err = NewCurrencyConversion(snapshot, options.Currency)
+		if err != nil {
+			return sortModifiers{}, fmt.Errorf("sort request validation failed: %w", err)
+		}
+	}
+
+	at := options.PricedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	if s.promotions != nil {
+		promotions, err := s.promotions.Promotions(ctx)
+		if err != nil {
+			return sortModifiers{}, fmt.Errorf("failed to load promotions: %w", err)
+		}
+
+		modifiers.prices, err = NewPromotionResolver(promotions, at)
+		if err != nil {
+			return sortModifiers{}, fmt.Errorf("invalid promotion: %w", err)
+		}
+	}
+
+	if s.rules != nil {
+		rules, err := s.rules.Rules(ctx)
+		if err != nil {
+			return sortModifiers{}, fmt.Errorf("failed to load merchandising rules: %w", err)
+		}
+
+		modifiers.rules, err = NewRuleSet(rules, at)
+		if err != nil {
+			return sortModifiers{}, fmt.Errorf("invalid merchandising rule: %w", err)
+		}
+	}
+
+	return modifiers, nil
+}
+
+// coalescedSort runs a sort once for all concurrent callers with the same key
+// and hands each caller its own copy of the result
+func (s *DefaultService) coalescedSort(ctx context.Context, products ProductCollection, strategy SortStrategy, modifiers sortModifiers) (*SortResult, error) {
+	key := modifiers.cacheKey(strategy)
+	result, shared, err := s.inflight.do(ctx, key, products, func() (*SortResult, error) {
+		return s.sortProducts(ctx, products, strategy, modifiers)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if shared {
+		s.logger.Debug("Sort request coalesced",
+			zap.String("strategy", string(strategy)),
+			zap.Int("product_count", len(products)),
+		)
+	}
+
+	return result.Clone(), nil
+}
+
+// sortProducts validates and sorts products without coalescing, then applies
+// any modifiers to the sorted collection
+func (s *DefaultService) sortProducts(ctx context.Context, products ProductCollection, strategy SortStrategy, modifiers sortModifiers) (*SortResult, error) {
 	// Validate inputs
 	if err := s.validateSortRequest(products, strategy); err != nil {
 		return nil, fmt.Errorf("sort request validation failed: %w", err)
 	}
+	if modifiers.conversion != nil {
+		if err := modifiers.conversion.CheckProducts(products); err != nil {
+			return nil, fmt.Errorf("sort request validation failed: %w", err)
+		}
+	}
 
 	// Record start time
 	start := time.Now()
@@ -57,18 +336,67 @@ func (s *DefaultService) SortProducts(ctx context.Context, products ProductColle
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sorter for strategy %s: %w", strategy, err)
 	}
+	sorter = modifiers.apply(sorter)
 
 	// Execute sorting
-	sortedProducts, err := sorter.Sort(ctx, products)
+	var sortedProducts ProductCollection
+	if modifiers.variants != VariantsFlat {
+		sortedProducts, err = s.sortParents(ctx, products, sorter, modifiers)
+	} else {
+		// Hide products that are not published unless explicitly requested
+		visible := products
+		if !modifiers.includeInactive {
+			visible = products.FilterActive()
+		}
+		sortedProducts, err = sorter.Sort(ctx, visible)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("sorting failed for strategy %s: %w", strategy, err)
 	}
 
+	// Record the order after each stage so explanations can show what moved
+	var stages []rankingStage
+	record := func(stage string) {
+		if modifiers.explain {
+			stages = append(stages, newRankingStage(stage, sortedProducts))
+		}
+	}
+	record(StageSorter)
+
+	if modifiers.stock != nil {
+		sortedProducts = modifiers.stock.Apply(sortedProducts)
+		record(StageStock)
+	}
+	if modifiers.diversity != nil {
+		sortedProducts = modifiers.diversity.Apply(sortedProducts)
+		record(StageDiversity)
+	}
+	var fired []FiredRule
+	if modifiers.rules != nil {
+		sortedProducts, fired = modifiers.rules.Apply(sortedProducts)
+		record(StageMerchandising)
+	}
+
 	// Calculate execution time
 	executionTime := time.Since(start)
 
 	// Create result
 	result := NewSortResult(sortedProducts, strategy, executionTime)
+	if modifiers.conversion != nil {
+		result.Currency = modifiers.conversion.Target
+		result.RateSnapshot = modifiers.conversion.Snapshot.ID
+	}
+	if modifiers.prices != nil {
+		pricedAt := modifiers.prices.At()
+		result.PricedAt = &pricedAt
+		if prices := modifiers.prices.EffectivePrices(sortedProducts); len(prices) > 0 {
+			result.EffectivePrices = prices
+		}
+	}
+	result.FiredRules = fired
+	if modifiers.explain {
+		result.Explanations = explainRanking(sortedProducts, sorter, stages, fired)
+	}
 
 	s.logger.Debug("Sort operation completed",
 		zap.String("strategy", string(strategy)),
@@ -79,6 +407,42 @@ func (s *DefaultService) SortProducts(ctx context.Context, products ProductColle
 	return result, nil
 }
 
+// sortParents ranks parent products by metrics aggregated over their
+// variants. In VariantsBest mode each parent is replaced by its best variant
+// under the same sorter.
+func (s *DefaultService) sortParents(ctx context.Context, products ProductCollection, sorter Sorter, modifiers sortModifiers) (ProductCollection, error) {
+	groups, err := GroupVariants(products)
+	if err != nil {
+		return nil, err
+	}
+	if !modifiers.includeInactive {
+		groups = groups.FilterActive()
+	}
+
+	parents, err := sorter.Sort(ctx, groups.Aggregates())
+	if err != nil {
+		return nil, err
+	}
+	if modifiers.variants != VariantsBest {
+		return parents, nil
+	}
+
+	byParent := make(map[ProductID]VariantGroup, len(groups))
+	for _, group := range groups {
+		byParent[group.Parent.ID] = group
+	}
+
+	best := make(ProductCollection, len(parents))
+	for i, parent := range parents {
+		members, err := sorter.Sort(ctx, byParent[parent.ID].Members())
+		if err != nil {
+			return nil, err
+		}
+		best[i] = members[0]
+	}
+	return best, nil
+}
+
 // BatchSort sorts products using multiple strategies
 func (s *DefaultService) BatchSort(ctx context.Context, products ProductCollection, strategies SortStrategySet) (*BatchSortResult, error) {
 	// Validate inputs
@@ -114,6 +478,622 @@ func (s *DefaultService) BatchSort(ctx context.Context, products ProductCollecti
 	return batchResult, nil
 }
 
+// FuseStrategies batch sorts products and fuses the rankings with FuseRankings
+func (s *DefaultService) FuseStrategies(ctx context.Context, products ProductCollection, strategies SortStrategySet, options FusionOptions) (*SortResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("fusion request validation failed: %w", err)
+	}
+
+	batch, err := s.BatchSort(ctx, products, strategies)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := FuseRankings(batch, options)
+	if err != nil {
+		return nil, fmt.Errorf("fusion failed: %w", err)
+	}
+
+	s.logger.Debug("Fused rankings",
+		zap.String("method", string(options.Method)),
+		zap.Int("strategy_count", len(strategies)),
+		zap.Int("product_count", result.ProductCount),
+	)
+
+	return result, nil
+}
+
+// CompareStrategies sorts products with both strategies and compares the
+// rankings with CompareRankings
+func (s *DefaultService) CompareStrategies(ctx context.Context, products ProductCollection, base, candidate SortStrategy, options RankingDiffOptions) (*RankingDiff, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("comparison request validation failed: %w", err)
+	}
+
+	baseResult, err := s.SortProducts(ctx, products, base)
+	if err != nil {
+		return nil, err
+	}
+
+	candidateResult, err := s.SortProducts(ctx, products, candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompareRankings(baseResult, candidateResult, options)
+}
+
+// EvaluateStrategies batch sorts products and evaluates the rankings with EvaluateBatch
+func (s *DefaultService) EvaluateStrategies(ctx context.Context, products ProductCollection, strategies SortStrategySet, judgments Judgments, k int) (*EvaluationReport, error) {
+	if err := judgments.Validate(); err != nil {
+		return nil, fmt.Errorf("evaluation request validation failed: %w", err)
+	}
+
+	batch, err := s.BatchSort(ctx, products, strategies)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := EvaluateBatch(batch, products, judgments, k)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation failed: %w", err)
+	}
+
+	s.logger.Debug("Evaluated strategies",
+		zap.Int("strategy_count", len(strategies)),
+		zap.Int("query_count", report.Queries),
+		zap.Int("k", report.K),
+	)
+
+	return report, nil
+}
+
+// SimulateStrategies batch sorts products and simulates the rankings with
+// SimulateRankings, comparing them with the first strategy unless a baseline
+// is set
+func (s *DefaultService) SimulateStrategies(ctx context.Context, products ProductCollection, strategies SortStrategySet, options SimulationOptions) (*SimulationReport, error) {
+	if len(strategies) > 0 && options.Baseline == "" {
+		options.Baseline = strategies[0]
+	}
+
+	batch, err := s.BatchSort(ctx, products, strategies)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := SimulateRankings(batch, options)
+	if err != nil {
+		return nil, fmt.Errorf("simulation failed: %w", err)
+	}
+
+	s.logger.Debug("Simulated strategies",
+		zap.Int("strategy_count", len(strategies)),
+		zap.Int("k", report.K),
+		zap.Int("runs", report.Runs),
+		zap.Int("sessions", report.Sessions),
+	)
+
+	return report, nil
+}
+
+// SaveExperiment stores an experiment, replacing one with the same name
+func (s *DefaultService) SaveExperiment(ctx context.Context, experiment Experiment) error {
+	if s.experiments == nil {
+		return fmt.Errorf("no experiment store configured")
+	}
+
+	if err := s.experiments.Save(ctx, experiment); err != nil {
+		return fmt.Errorf("failed to save experiment %s: %w", experiment.Name, err)
+	}
+	return nil
+}
+
+// ListExperiments returns every stored experiment ordered by name
+func (s *DefaultService) ListExperiments(ctx context.Context) ([]Experiment, error) {
+	if s.experiments == nil {
+		return nil, fmt.Errorf("no experiment store configured")
+	}
+
+	return s.experiments.FindAll(ctx)
+}
+
+// DeleteExperiment removes an experiment from the store
+func (s *DefaultService) DeleteExperiment(ctx context.Context, experiment string) error {
+	if s.experiments == nil {
+		return fmt.Errorf("no experiment store configured")
+	}
+
+	if err := s.experiments.Delete(ctx, experiment); err != nil {
+		return fmt.Errorf("failed to delete experiment %s: %w", experiment, err)
+	}
+	return nil
+}
+
+// AssignExperiment looks up an experiment and assigns the unit to a variant
+func (s *DefaultService) AssignExperiment(ctx context.Context, experiment, unitID string) (*ExperimentAssignment, error) {
+	if s.experiments == nil {
+		return nil, fmt.Errorf("no experiment store configured")
+	}
+
+	definition, err := s.experiments.Find(ctx, experiment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load experiment %s: %w", experiment, err)
+	}
+
+	assignment, err := definition.Assign(unitID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("experiment assignment failed: %w", err)
+	}
+	return assignment, nil
+}
+
+// SortForExperiment assigns the unit to a variant and sorts with its
+// strategy. The result records the assignment so outcomes can be attributed
+// to the variant.
+func (s *DefaultService) SortForExperiment(ctx context.Context, products ProductCollection, experiment, unitID string, options SortOptions) (*SortResult, error) {
+	assignment, err := s.AssignExperiment(ctx, experiment, unitID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.SortProductsWithOptions(ctx, products, assignment.Strategy, options)
+	if err != nil {
+		return nil, err
+	}
+	result.Experiment = assignment
+
+	s.logger.Debug("Sorted for experiment",
+		zap.String("experiment", assignment.Experiment),
+		zap.String("variant", assignment.Variant),
+		zap.String("strategy", string(assignment.Strategy)),
+		zap.Bool("active", assignment.Active),
+	)
+
+	return result, nil
+}
+
+// RecordExperimentEvents validates events and aggregates them per variant.
+// When an experiment store is configured, events must name a known
+// experiment and variant. Nothing is recorded if any event is rejected.
+func (s *DefaultService) RecordExperimentEvents(ctx context.Context, events []ExperimentEvent) error {
+	if s.experiments != nil {
+		variants := make(map[string]map[string]bool)
+		for i, event := range events {
+			known, loaded := variants[event.Experiment]
+			if !loaded {
+				definition, err := s.experiments.Find(ctx, event.Experiment)
+				if err != nil {
+					return fmt.Errorf("event %d: %w", i, err)
+				}
+				known = make(map[string]bool, len(definition.Variants))
+				for _, variant := range definition.Variants {
+					known[variant.Name] = true
+				}
+				variants[event.Experiment] = known
+			}
+			if !known[event.Variant] {
+				return fmt.Errorf("event %d: experiment %s has no variant %s", i, event.Experiment, event.Variant)
+			}
+		}
+	}
+
+	if err := s.loadOutcomes(ctx); err != nil {
+		return err
+	}
+
+	// Events are logged only once they are valid, and counted only once logged
+	var persist func() error
+	if s.eventLog != nil {
+		persist = func() error { return s.eventLog.Append(ctx, events) }
+	}
+	if err := s.outcomes.record(events, persist); err != nil {
+		return fmt.Errorf("failed to record experiment events: %w", err)
+	}
+
+	s.logger.Debug("Recorded experiment events", zap.Int("event_count", len(events)))
+	return nil
+}
+
+// ExperimentReport builds a report from the recorded events and publishes an
+// alert the first time a variant significantly beats or trails the control
+func (s *DefaultService) ExperimentReport(ctx context.Context, experiment string, options ReportOptions) (*ExperimentReport, error) {
+	if s.experiments == nil {
+		return nil, fmt.Errorf("no experiment store configured")
+	}
+
+	definition, err := s.experiments.Find(ctx, experiment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load experiment %s: %w", experiment, err)
+	}
+
+	if err := s.loadOutcomes(ctx); err != nil {
+		return nil, err
+	}
+
+	report, err := BuildExperimentReport(*definition, s.outcomes.Outcomes(experiment), options)
+	if err != nil {
+		return nil, fmt.Errorf("experiment report failed: %w", err)
+	}
+
+	if s.publisher != nil {
+		for _, alert := range report.Alerts() {
+			key := fmt.Sprintf("%s/%s/%s", report.Experiment, alert.Metadata["variant"], alert.AlertType)
+			if !s.outcomes.markAlerted(key) {
+				continue
+			}
+			if err := s.publisher.PublishPerformanceAlert(ctx, alert); err != nil {
+				// Forget the alert so the next report retries it
+				s.outcomes.clearAlerted(key)
+				s.logger.Warn("Failed to publish experiment alert",
+					zap.String("experiment", report.Experiment),
+					zap.String("alert_type", alert.AlertType),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// loadOutcomes replays the event log into the outcomes the first time they
+// are needed. A failed replay is retried on the next call.
+func (s *DefaultService) loadOutcomes(ctx context.Context) error {
+	if s.eventLog == nil {
+		return nil
+	}
+
+	s.outcomesMu.Lock()
+	defer s.outcomesMu.Unlock()
+
+	if s.outcomesLoaded {
+		return nil
+	}
+	events, err := s.eventLog.Events(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load experiment events: %w", err)
+	}
+	if err := s.outcomes.Record(events...); err != nil {
+		return fmt.Errorf("failed to replay experiment events: %w", err)
+	}
+	s.outcomesLoaded = true
+
+	s.logger.Debug("Replayed experiment events", zap.Int("event_count", len(events)))
+	return nil
+}
+
+// CreateBandit validates a bandit configuration and stores its initial state.
+// Names are unique; an existing bandit is never reset.
+func (s *DefaultService) CreateBandit(ctx context.Context, config BanditConfig) error {
+	if s.bandits == nil {
+		return fmt.Errorf("no bandit store configured")
+	}
+
+	bandit, err := NewBandit(config)
+	if err != nil {
+		return fmt.Errorf("bandit validation failed: %w", err)
+	}
+
+	unlock := s.lockBandit(config.Name)
+	defer unlock()
+
+	if _, err := s.loadBanditLocked(ctx, config.Name); err == nil {
+		return fmt.Errorf("bandit %s already exists", config.Name)
+	} else if !errors.Is(err, ErrBanditNotFound) {
+		return err
+	}
+
+	if err := s.bandits.Save(ctx, bandit.State()); err != nil {
+		return fmt.Errorf("failed to save bandit %s: %w", config.Name, err)
+	}
+	s.keepBandit(bandit)
+	return nil
+}
+
+// ListBandits returns the stored state of every bandit
+func (s *DefaultService) ListBandits(ctx context.Context) ([]BanditState, error) {
+	if s.bandits == nil {
+		return nil, fmt.Errorf("no bandit store configured")
+	}
+
+	return s.bandits.FindAll(ctx)
+}
+
+// SelectBandit asks the bandit for a strategy and persists the advanced
+// bandit state before returning it. The selection is made on a copy that
+// replaces the loaded bandit only once it is saved, so a failed save leaves
+// memory and store at the same point of the random sequence.
+//
+// Selections of one bandit wait for each other's store writes; selections
+// of different bandits only share whatever the store serialises itself.
+func (s *DefaultService) SelectBandit(ctx context.Context, bandit string) (*BanditSelection, error) {
+	unlock := s.lockBandit(bandit)
+	defer unlock()
+
+	loaded, err := s.loadBanditLocked(ctx, bandit)
+	if err != nil {
+		return nil, err
+	}
+
+	next := loaded.clone()
+	selection := next.Select()
+	if err := s.bandits.Save(ctx, next.State()); err != nil {
+		return nil, fmt.Errorf("failed to save bandit %s: %w", bandit, err)
+	}
+	s.keepBandit(next)
+	return &selection, nil
+}
+
+// SortWithBandit sorts with the strategy SelectBandit picks. The result
+// records the selection so the reward can be reported against it.
+func (s *DefaultService) SortWithBandit(ctx context.Context, products ProductCollection, bandit string, options SortOptions) (*SortResult, error) {
+	selection, err := s.SelectBandit(ctx, bandit)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.SortProductsWithOptions(ctx, products, selection.Strategy, options)
+	if err != nil {
+		return nil, err
+	}
+	result.Bandit = selection
+
+	s.logger.Debug("Sorted with bandit",
+		zap.String("bandit", selection.Bandit),
+		zap.String("strategy", string(selection.Strategy)),
+		zap.Bool("explored", selection.Explored),
+	)
+
+	return result, nil
+}
+
+// RecordBanditReward updates the bandit's posterior and persists it. Like
+// SelectBandit, the update only takes effect once it is saved.
+func (s *DefaultService) RecordBanditReward(ctx context.Context, reward BanditReward) error {
+	unlock := s.lockBandit(reward.Bandit)
+	defer unlock()
+
+	loaded, err := s.loadBanditLocked(ctx, reward.Bandit)
+	if err != nil {
+		return err
+	}
+
+	next := loaded.clone()
+	if err := next.Update(reward.Strategy, reward.Reward); err != nil {
+		return err
+	}
+	if err := s.bandits.Save(ctx, next.State()); err != nil {
+		return fmt.Errorf("failed to save bandit %s: %w", reward.Bandit, err)
+	}
+	s.keepBandit(next)
+	return nil
+}
+
+// lockBandit takes the lock serialising updates of the named bandit and
+// returns its unlock function
+func (s *DefaultService) lockBandit(name string) func() {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	lock := &s.banditLocks[hash.Sum32()%banditLockStripes]
+	lock.Lock()
+	return lock.Unlock
+}
+
+// keepBandit makes a saved bandit the one served from memory
+func (s *DefaultService) keepBandit(bandit *Bandit) {
+	s.banditMu.Lock()
+	defer s.banditMu.Unlock()
+
+	s.loadedBandits[bandit.Name()] = bandit
+}
+
+// loadBanditLocked returns a bandit, restoring it from the store the first
+// time it is used. The caller must hold the bandit's lock.
+func (s *DefaultService) loadBanditLocked(ctx context.Context, name string) (*Bandit, error) {
+	if s.bandits == nil {
+		return nil, fmt.Errorf("no bandit store configured")
+	}
+	s.banditMu.Lock()
+	bandit, loaded := s.loadedBandits[name]
+	s.banditMu.Unlock()
+	if loaded {
+		return bandit, nil
+	}
+
+	state, err := s.bandits.Find(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bandit %s: %w", name, err)
+	}
+	bandit, err = RestoreBandit(*state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore bandit %s: %w", name, err)
+	}
+	s.keepBandit(bandit)
+	return bandit, nil
+}
+
+// SortPage sorts products and returns one page using keyset pagination.
+// The page starts strictly after the cursor's sort key, so products added or
+// removed between requests never cause duplicates or gaps.
+func (s *DefaultService) SortPage(ctx context.Context, products ProductCollection, strategy SortStrategy, page PageRequest) (*PageResult, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("page request validation failed: %w", err)
+	}
+
+	after, err := page.ResolveCursor(strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	if products == nil {
+		return nil, fmt.Errorf("sort request validation failed: products collection cannot be nil")
+	}
+
+	// The page and the cursor comparison must see the same effective prices.
+	// Merchandising rules are not applied: a pinned or boosted product would
+	// break the ordering the cursor seeks by.
+	modifiers, err := s.resolveModifiers(ctx, SortOptions{})
+	if err != nil {
+		return nil, err
+	}
+	modifiers.rules = nil
+
+	sorter, err := s.sorterFactory.CreateSorter(strategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sorter for strategy %s: %w", strategy, err)
+	}
+	comparator, ok := modifiers.apply(sorter).(Comparator)
+	if !ok {
+		return nil, fmt.Errorf("strategy %s does not support cursor pagination", strategy)
+	}
+
+	result, err := s.coalescedSort(ctx, products, strategy, modifiers)
+	if err != nil {
+		return nil, err
+	}
+	sorted := result.Products
+
+	// Binary search for the first product ordered after the cursor
+	start := 0
+	if after != nil {
+		start = sort.Search(len(sorted), func(i int) bool {
+			return comparator.Less(after.Key, sorted[i])
+		})
+	}
+
+	end := start + page.Limit
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+
+	pageResult := NewPageResult(sorted[start:end].Copy(), strategy, end < len(sorted))
+	if modifiers.prices != nil {
+		if prices := modifiers.prices.EffectivePrices(pageResult.Products); len(prices) > 0 {
+			pageResult.EffectivePrices = prices
+		}
+	}
+
+	return pageResult, nil
+}
+
+// StreamSort sorts products read from an iterator, validating each one as it
+// arrives. Only one copy of the input is held, and with TopK set only the best
+// K products are retained. Inactive products are skipped unless requested.
+// Merchandising rules need the full ranking and are not applied.
+func (s *DefaultService) StreamSort(ctx context.Context, source ProductIterator, strategy SortStrategy, options StreamOptions, emit ProductEmitter) (*StreamSortResult, error) {
+	// Validate inputs
+	if err := s.validateStreamSortRequest(source, strategy, options, emit); err != nil {
+		return nil, fmt.Errorf("stream sort request validation failed: %w", err)
+	}
+
+	start := time.Now()
+
+	modifiers, err := s.resolveModifiers(ctx, SortOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	sorter, err := s.sorterFactory.CreateSorter(strategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sorter for strategy %s: %w", strategy, err)
+	}
+	sorter = modifiers.apply(sorter)
+	checker, _ := sorter.(ProductChecker)
+
+	// Bounded selection requires a pairwise comparison
+	comparator, canCompare := sorter.(Comparator)
+	var best *topKHeap
+	if canCompare && options.TopK > 0 {
+		best = &topKHeap{less: comparator.Less}
+	}
+
+	var collected ProductCollection
+	productCount := 0
+	for source.Next() {
+		if productCount%streamCancellationInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		product := source.Product()
+		if err := product.Validate(); err != nil {
+			return nil, fmt.Errorf("product at index %d (ID: %d): %w", productCount, product.ID, err)
+		}
+		productCount++
+
+		if !options.IncludeInactive && !product.IsActive() {
+			continue
+		}
+
+		if checker != nil {
+			if err := checker.CheckProduct(product); err != nil {
+				return nil, fmt.Errorf("sorting failed for strategy %s: %w", strategy, err)
+			}
+		}
+
+		if best == nil {
+			collected = append(collected, product)
+			continue
+		}
+
+		heap.Push(best, product)
+		if best.Len() > options.TopK {
+			heap.Pop(best)
+		}
+	}
+	if err := source.Err(); err != nil {
+		return nil, fmt.Errorf("reading products failed: %w", err)
+	}
+
+	// Sort in place when possible so the input is never copied
+	var sorted ProductCollection
+	switch {
+	case best != nil:
+		sorted = best.products
+		sort.Slice(sorted, func(i, j int) bool { return comparator.Less(sorted[i], sorted[j]) })
+	case canCompare:
+		sorted = collected
+		sort.Slice(sorted, func(i, j int) bool { return comparator.Less(sorted[i], sorted[j]) })
+	default:
+		sorted, err = sorter.Sort(ctx, collected)
+		if err != nil {
+			return nil, fmt.Errorf("sorting failed for strategy %s: %w", strategy, err)
+		}
+		if options.TopK > 0 && len(sorted) > options.TopK {
+			sorted = sorted[:options.TopK]
+		}
+	}
+
+	for i, product := range sorted {
+		if i%streamCancellationInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if err := emit(product); err != nil {
+			return nil, fmt.Errorf("emitting product %d failed: %w", product.ID, err)
+		}
+	}
+
+	result := &StreamSortResult{
+		Strategy:      strategy,
+		ProductCount:  productCount,
+		EmittedCount:  len(sorted),
+		ExecutionTime: time.Since(start),
+		SortedAt:      time.Now(),
+	}
+
+	s.logger.Debug("Stream sort operation completed",
+		zap.String("strategy", string(strategy)),
+		zap.Int("product_count", result.ProductCount),
+		zap.Int("emitted_count", result.EmittedCount),
+		zap.Duration("execution_time", result.ExecutionTime),
+	)
+
+	return result, nil
+}
+
 // GetSupportedStrategies returns all supported sorting strategies
 func (s *DefaultService) GetSupportedStrategies() SortStrategySet {
 	return s.sorterFactory.GetSupportedStrategies()
@@ -165,3 +1145,20 @@ func (s *DefaultService) validateBatchSortRequest(products ProductCollection, st
 
 	return nil
 }
+
+// validateStreamSortRequest validates the stream sort request parameters
+func (s *DefaultService) validateStreamSortRequest(source ProductIterator, strategy SortStrategy, options StreamOptions, emit ProductEmitter) error {
+	if source == nil {
+		return fmt.Errorf("product source cannot be nil")
+	}
+
+	if emit == nil {
+		return fmt.Errorf("product emitter cannot be nil")
+	}
+
+	if !strategy.IsValid() {
+		return fmt.Errorf("invalid sort strategy: %s", strategy)
+	}
+
+	return options.Validate()
+}
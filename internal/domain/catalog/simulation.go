@@ -0,0 +1,420 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Defaults for revenue simulations
+const (
+	DefaultSimulationK          = 10
+	DefaultSimulationRuns       = 200
+	DefaultSimulationSessions   = 1000
+	DefaultClickProbability     = 0.2
+	DefaultExaminationDecay     = 1.0
+	DefaultConversionPriorViews = 100
+	DefaultSimulationConfidence = 0.95
+)
+
+// ExaminationModel is a position-based click model: a shopper examines the
+// product in slot i with probability Probabilities[i-1], or 1/i^Decay when
+// no probabilities are set, and clicks an examined product with probability
+// ClickProbability. Nil Decay and ClickProbability use the defaults, so an
+// explicit zero is kept: decay 0 examines every slot.
+type ExaminationModel struct {
+	Probabilities    []float64 `json:"probabilities,omitempty"`
+	Decay            *float64  `json:"decay,omitempty"`
+	ClickProbability *float64  `json:"click_probability,omitempty"`
+}
+
+// decay returns the examination decay, or the default when unset
+func (m ExaminationModel) decay() float64 {
+	if m.Decay == nil {
+		return DefaultExaminationDecay
+	}
+	return *m.Decay
+}
+
+// clickProbability returns the click probability, or the default when unset
+func (m ExaminationModel) clickProbability() float64 {
+	if m.ClickProbability == nil {
+		return DefaultClickProbability
+	}
+	return *m.ClickProbability
+}
+
+// Examination returns the probability a 1-based slot is examined. Slots past
+// the configured probabilities are never examined.
+func (m ExaminationModel) Examination(position int) float64 {
+	if len(m.Probabilities) > 0 {
+		if position > len(m.Probabilities) {
+			return 0
+		}
+		return m.Probabilities[position-1]
+	}
+	return 1 / math.Pow(float64(position), m.decay())
+}
+
+// SimulationOptions configures a counterfactual revenue simulation. Zero
+// values, and nil pointers, use the defaults.
+type SimulationOptions struct {
+	// K is the number of slots shoppers can examine
+	K int `json:"k,omitempty"`
+
+	// Runs is the number of Monte Carlo runs of Sessions shopping sessions;
+	// the confidence intervals come from the spread of the run totals
+	Runs     int `json:"runs,omitempty"`
+	Sessions int `json:"sessions,omitempty"`
+
+	// Seed makes simulations repeatable. Every strategy replays the same
+	// random numbers, so differences between strategies come from their
+	// rankings rather than from noise.
+	Seed int64 `json:"seed"`
+
+	Examination ExaminationModel `json:"examination"`
+
+	// ConversionPriorViews smooths each product's SalesConversionRatio
+	// towards the catalog average, as if it had this many extra views at the
+	// average rate. Zero uses the raw ratios.
+	ConversionPriorViews *float64 `json:"conversion_prior_views,omitempty"`
+
+	ConfidenceLevel float64 `json:"confidence_level,omitempty"`
+
+	// Baseline is the strategy the others are compared with; defaults to the
+	// first simulated strategy by name
+	Baseline SortStrategy `json:"baseline,omitempty"`
+}
+
+// priorViews returns the conversion prior views, or the default when unset
+func (o SimulationOptions) priorViews() float64 {
+	if o.ConversionPriorViews == nil {
+		return DefaultConversionPriorViews
+	}
+	return *o.ConversionPriorViews
+}
+
+// withDefaults validates the options and fills in defaults
+func (o SimulationOptions) withDefaults() (SimulationOptions, error) {
+	if o.K == 0 {
+		o.K = DefaultSimulationK
+	}
+	if o.Runs == 0 {
+		o.Runs = DefaultSimulationRuns
+	}
+	if o.Sessions == 0 {
+		o.Sessions = DefaultSimulationSessions
+	}
+	if o.ConfidenceLevel == 0 {
+		o.ConfidenceLevel = DefaultSimulationConfidence
+	}
+
+	if o.K < 0 || o.Runs < 0 || o.Sessions < 0 {
+		return o, fmt.Errorf("simulation k, runs and sessions cannot be negative")
+	}
+	for i, probability := range o.Examination.Probabilities {
+		if !validProbability(probability) {
+			return o, fmt.Errorf("examination probability of slot %d must be between 0 and 1", i+1)
+		}
+	}
+	if decay := o.Examination.decay(); decay < 0 || math.IsNaN(decay) || math.IsInf(decay, 0) {
+		return o, fmt.Errorf("examination decay must be a non-negative number")
+	}
+	if !validProbability(o.Examination.clickProbability()) {
+		return o, fmt.Errorf("click probability must be between 0 and 1")
+	}
+	if prior := o.priorViews(); prior < 0 || math.IsNaN(prior) || math.IsInf(prior, 0) {
+		return o, fmt.Errorf("conversion prior views must be a non-negative number")
+	}
+	if !(o.ConfidenceLevel > 0 && o.ConfidenceLevel < 1) {
+		return o, fmt.Errorf("confidence level must be between 0 and 1")
+	}
+	return o, nil
+}
+
+// validProbability reports whether a value is a probability
+func validProbability(value float64) bool {
+	return value >= 0 && value <= 1
+}
+
+// SimulationEstimate is a simulated total over one run of sessions: the
+// analytic expectation, the Monte Carlo mean and its confidence interval
+type SimulationEstimate struct {
+	Expected float64  `json:"expected"`
+	Mean     float64  `json:"mean"`
+	Interval Interval `json:"interval"`
+}
+
+// SlotEstimate is the expected outcome of one slot per session
+type SlotEstimate struct {
+	Position    int       `json:"position"`
+	ProductID   ProductID `json:"product_id"`
+	Examination float64   `json:"examination"`
+	Conversion  float64   `json:"conversion"`
+	Clicks      float64   `json:"clicks"`
+	Sales       float64   `json:"sales"`
+	Revenue     float64   `json:"revenue"`
+}
+
+// StrategySimulation is the simulated outcome of one strategy's ranking.
+// Totals are per run of Sessions sessions; revenue is in major units of
+// Currency.
+type StrategySimulation struct {
+	Rank     int          `json:"rank"`
+	Strategy SortStrategy `json:"strategy"`
+	Currency Currency     `json:"currency"`
+
+	Clicks  SimulationEstimate `json:"clicks"`
+	Sales   SimulationEstimate `json:"sales"`
+	Revenue SimulationEstimate `json:"revenue"`
+
+	// RevenueDifference is the revenue per run minus the baseline's, with an
+	// interval from the paired runs
+	RevenueDifference SimulationEstimate `json:"revenue_difference"`
+
+	Slots []SlotEstimate `json:"slots"`
+}
+
+// SimulationReport compares strategies by simulated revenue, highest first
+type SimulationReport struct {
+	K               int                  `json:"k"`
+	Runs            int                  `json:"runs"`
+	Sessions        int                  `json:"sessions"`
+	Seed            int64                `json:"seed"`
+	ConfidenceLevel float64              `json:"confidence_level"`
+	Baseline        SortStrategy         `json:"baseline"`
+	Strategies      []StrategySimulation `json:"strategies"`
+}
+
+// Strategy returns the simulation of a strategy, if it was simulated
+func (r *SimulationReport) Strategy(strategy SortStrategy) (StrategySimulation, bool) {
+	for _, simulation := range r.Strategies {
+		if simulation.Strategy == strategy {
+			return simulation, true
+		}
+	}
+	return StrategySimulation{}, false
+}
+
+// simulationSlot is one slot's probabilities and revenue per sale
+type simulationSlot struct {
+	estimate SlotEstimate
+	price    float64
+}
+
+// SimulateRankings estimates the clicks, sales and revenue each ranking of a
+// batch sort would produce in its first K slots. Each session walks the
+// slots: an examined slot is clicked with the click probability, and a click
+// converts with the product's smoothed SalesConversionRatio at its effective
+// price. All prices must share one currency.
+func SimulateRankings(batch *BatchSortResult, options SimulationOptions) (*SimulationReport, error) {
+	if err := batch.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid batch sort result: %w", err)
+	}
+	options, err := options.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := make(SortStrategySet, 0, len(batch.Results))
+	for strategy := range batch.Results {
+		strategies = append(strategies, strategy)
+	}
+	sort.Slice(strategies, func(i, j int) bool { return strategies[i] < strategies[j] })
+
+	baseline := options.Baseline
+	if baseline == "" {
+		baseline = strategies[0]
+	}
+	if _, ok := batch.Results[baseline]; !ok {
+		return nil, fmt.Errorf("baseline strategy %s was not simulated", baseline)
+	}
+
+	averageConversion, currency, err := catalogConversion(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SimulationReport{
+		K:               options.K,
+		Runs:            options.Runs,
+		Sessions:        options.Sessions,
+		Seed:            options.Seed,
+		ConfidenceLevel: options.ConfidenceLevel,
+		Baseline:        baseline,
+	}
+
+	revenueRuns := make(map[SortStrategy][]float64, len(strategies))
+	for _, strategy := range strategies {
+		result := batch.Results[strategy]
+		slots := simulationSlots(result, options, averageConversion)
+
+		simulation := StrategySimulation{Strategy: strategy, Currency: currency}
+		for _, slot := range slots {
+			simulation.Slots = append(simulation.Slots, slot.estimate)
+			simulation.Clicks.Expected += slot.estimate.Clicks * float64(options.Sessions)
+			simulation.Sales.Expected += slot.estimate.Sales * float64(options.Sessions)
+			simulation.Revenue.Expected += slot.estimate.Revenue * float64(options.Sessions)
+		}
+
+		clicks, sales, revenue := runSimulation(slots, options)
+		summarizeRuns(&simulation.Clicks, clicks, options.ConfidenceLevel)
+		summarizeRuns(&simulation.Sales, sales, options.ConfidenceLevel)
+		summarizeRuns(&simulation.Revenue, revenue, options.ConfidenceLevel)
+		revenueRuns[strategy] = revenue
+
+		report.Strategies = append(report.Strategies, simulation)
+	}
+
+	base, _ := report.Strategy(baseline)
+	for i := range report.Strategies {
+		simulation := &report.Strategies[i]
+		differences := make([]float64, options.Runs)
+		for run := range differences {
+			differences[run] = revenueRuns[simulation.Strategy][run] - revenueRuns[baseline][run]
+		}
+		simulation.RevenueDifference.Expected = simulation.Revenue.Expected - base.Revenue.Expected
+		summarizeRuns(&simulation.RevenueDifference, differences, options.ConfidenceLevel)
+	}
+
+	sort.SliceStable(report.Strategies, func(i, j int) bool {
+		return report.Strategies[i].Revenue.Expected > report.Strategies[j].Revenue.Expected
+	})
+	for i := range report.Strategies {
+		report.Strategies[i].Rank = i + 1
+	}
+
+	return report, nil
+}
+
+// catalogConversion returns the average conversion of every ranked product,
+// used as the smoothing prior, and the currency all prices share
+func catalogConversion(batch *BatchSortResult) (float64, Currency, error) {
+	var currency Currency
+	sales, views := 0, 0
+	seen := make(map[ProductID]bool)
+	for _, result := range batch.Results {
+		for _, product := range result.Products {
+			price := result.EffectivePrice(product)
+			if currency == "" {
+				currency = price.Currency.OrDefault()
+			} else if price.Currency.OrDefault() != currency {
+				return 0, "", fmt.Errorf("%w: simulation needs one currency, got %s and %s",
+					ErrCurrencyMismatch, currency, price.Currency.OrDefault())
+			}
+
+			if !seen[product.ID] {
+				seen[product.ID] = true
+				sales += product.SalesCount
+				views += product.ViewsCount
+			}
+		}
+	}
+
+	if views == 0 {
+		return 0, currency.OrDefault(), nil
+	}
+	return math.Min(float64(sales)/float64(views), 1), currency.OrDefault(), nil
+}
+
+// simulationSlots computes the per-session expectations of a ranking's first
+// K slots
+func simulationSlots(result *SortResult, options SimulationOptions, averageConversion float64) []simulationSlot {
+	count := options.K
+	if count > len(result.Products) {
+		count = len(result.Products)
+	}
+
+	priorViews := options.priorViews()
+	clickProbability := options.Examination.clickProbability()
+	slots := make([]simulationSlot, count)
+	for i := 0; i < count; i++ {
+		product := result.Products[i]
+		conversion := (float64(product.SalesCount) + priorViews*averageConversion) /
+			(float64(product.ViewsCount) + priorViews)
+		if math.IsNaN(conversion) {
+			conversion = 0
+		}
+		conversion = math.Min(conversion, 1)
+
+		examination := options.Examination.Examination(i + 1)
+		clicks := examination * clickProbability
+		price := result.EffectivePrice(product).ToFloat64()
+		slots[i] = simulationSlot{
+			price: price,
+			estimate: SlotEstimate{
+				Position:    i + 1,
+				ProductID:   product.ID,
+				Examination: examination,
+				Conversion:  conversion,
+				Clicks:      clicks,
+				Sales:       clicks * conversion,
+				Revenue:     clicks * conversion * price,
+			},
+		}
+	}
+	return slots
+}
+
+// runSimulation plays options.Runs runs of options.Sessions sessions and
+// returns the clicks, sales and revenue of each run. Every slot draws the
+// same three random numbers whatever happens, so strategies simulated with
+// one seed see identical shoppers.
+func runSimulation(slots []simulationSlot, options SimulationOptions) ([]float64, []float64, []float64) {
+	rng := rand.New(rand.NewSource(options.Seed))
+	clickProbability := options.Examination.clickProbability()
+
+	clicks := make([]float64, options.Runs)
+	sales := make([]float64, options.Runs)
+	revenue := make([]float64, options.Runs)
+	for run := 0; run < options.Runs; run++ {
+		for session := 0; session < options.Sessions; session++ {
+			for k := 0; k < options.K; k++ {
+				examined, clicked, converted := rng.Float64(), rng.Float64(), rng.Float64()
+				if k >= len(slots) {
+					continue
+				}
+
+				slot := slots[k]
+				if examined >= slot.estimate.Examination || clicked >= clickProbability {
+					continue
+				}
+				clicks[run]++
+				if converted < slot.estimate.Conversion {
+					sales[run]++
+					revenue[run] += slot.price
+				}
+			}
+		}
+	}
+	return clicks, sales, revenue
+}
+
+// summarizeRuns sets the Monte Carlo mean and percentile interval of an estimate
+func summarizeRuns(estimate *SimulationEstimate, runs []float64, level float64) {
+	if len(runs) == 0 {
+		return
+	}
+
+	sorted := append([]float64(nil), runs...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, value := range sorted {
+		total += value
+	}
+	estimate.Mean = total / float64(len(sorted))
+
+	tail := (1 - level) / 2
+	estimate.Interval = Interval{Lower: quantile(sorted, tail), Upper: quantile(sorted, 1-tail)}
+}
+
+// quantile returns the linearly interpolated quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	fraction := position - float64(lower)
+	return sorted[lower]*(1-fraction) + sorted[upper]*fraction
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"fmt"
+	"time"
+)
+
+// SortOptions adjusts how products are ranked on top of a SortStrategy
+type SortOptions struct {
+	// Currency compares prices and revenue in this currency when set
+	Currency Currency `json:"currency,omitempty"`
+
+	// Stock demotes out-of-stock and low-stock products when set
+	Stock *StockPolicy `json:"stock,omitempty"`
+
+	// Diversity limits how many products of one group appear close together
+	Diversity *DiversityPolicy `json:"diversity,omitempty"`
+
+	// Explain attaches a RankExplanation for every ranked product
+	Explain bool `json:"explain,omitempty"`
+
+	// IncludeInactive keeps draft, discontinued and archived products, for admin views
+	IncludeInactive bool `json:"include_inactive,omitempty"`
+
+	// PricedAt resolves promotions and merchandising rules at this time
+	// instead of now
+	PricedAt time.Time `json:"priced_at,omitempty"`
+
+	// Variants ranks parent products by metrics aggregated over their variants
+	Variants VariantMode `json:"variants,omitempty"`
+
+	// Collation compares localized names with a locale's rules when set
+	Collation *Collation `json:"collation,omitempty"`
+}
+
+// Validate ensures the options are well formed
+func (o SortOptions) Validate() error {
+	if o.Currency != "" && !o.Currency.IsValid() {
+		return fmt.Errorf("invalid target currency: %s", o.Currency)
+	}
+
+	if !o.Variants.IsValid() {
+		return fmt.Errorf("invalid variant mode: %s", o.Variants)
+	}
+
+	if o.Collation != nil {
+		if err := o.Collation.Validate(); err != nil {
+			return fmt.Errorf("invalid collation: %w", err)
+		}
+	}
+
+	if o.Stock != nil {
+		if err := o.Stock.Validate(); err != nil {
+			return fmt.Errorf("invalid stock policy: %w", err)
+		}
+	}
+
+	if o.Diversity != nil {
+		if err := o.Diversity.Validate(); err != nil {
+			return fmt.Errorf("invalid diversity policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sortModifiers are the resolved options applied to a single sort
+type sortModifiers struct {
+	conversion      *CurrencyConversion
+	stock           *StockPolicy
+	diversity       *DiversityPolicy
+	includeInactive bool
+	prices          *PromotionResolver
+	variants        VariantMode
+	collation       *Collation
+	rules           *RuleSet
+	explain         bool
+}
+
+// cacheKey builds the key identifying a sort by strategy with these
+// modifiers, so only requests with identical modifiers are coalesced. The
+// product hash is left empty; the call group compares products itself.
+func (m sortModifiers) cacheKey(strategy SortStrategy) CacheKey {
+	key := CacheKey{Strategy: strategy, Version: cacheKeyVersion}
+	if m.conversion != nil {
+		key.Currency = m.conversion.Target
+		key.RateSnapshot = m.conversion.Snapshot.ID
+	}
+	if m.stock != nil {
+		key.StockPolicy = m.stock.String()
+	}
+	if m.diversity != nil {
+		key.Diversity = m.diversity.String()
+	}
+	key.IncludeInactive = m.includeInactive
+	key.Explain = m.explain
+	key.Variants = m.variants
+	if m.collation != nil {
+		key.Collation = m.collation.String()
+	}
+	if m.prices != nil {
+		key.Promotions = m.prices.Fingerprint()
+	}
+	if m.rules != nil {
+		key.Rules = m.rules.Fingerprint()
+	}
+	return key
+}
+
+// apply configures a sorter to compare converted and effective prices
+// when it supports them
+func (m sortModifiers) apply(sorter Sorter) Sorter {
+	if aware, ok := sorter.(CurrencyAwareSorter); ok && m.conversion != nil {
+		sorter = aware.WithCurrency(m.conversion)
+	}
+	if resolving, ok := sorter.(PriceResolvingSorter); ok && m.prices != nil {
+		sorter = resolving.WithPriceResolver(m.prices)
+	}
+	if collating, ok := sorter.(CollatingSorter); ok && m.collation != nil {
+		sorter = collating.WithCollation(*m.collation)
+	}
+	return sorter
+}
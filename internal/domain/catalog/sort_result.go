@@ -13,6 +13,31 @@ type SortResult struct {
 	ExecutionTime  time.Duration     `json:"execution_time"`
 	ProductCount   int               `json:"product_count"`
 	SortedAt       time.Time         `json:"sorted_at"`
+
+	// Currency and RateSnapshot are set when monetary amounts were compared
+	// in a target currency, identifying the exchange rates that were used
+	Currency     Currency `json:"currency,omitempty"`
+	RateSnapshot string   `json:"rate_snapshot,omitempty"`
+
+	// PricedAt is set when promotions were resolved; EffectivePrices holds
+	// the discounted price of every product on promotion at that time
+	PricedAt        *time.Time          `json:"priced_at,omitempty"`
+	EffectivePrices map[ProductID]Money `json:"effective_prices,omitempty"`
+
+	// FiredRules lists the merchandising rules that changed the ranking
+	FiredRules []FiredRule `json:"fired_rules,omitempty"`
+
+	// Fusion is set on consensus rankings merged from several strategies
+	Fusion *FusionDetails `json:"fusion,omitempty"`
+
+	// Explanations is set in explain mode, one per product in result order
+	Explanations []RankExplanation `json:"explanations,omitempty"`
+
+	// Experiment is set when the strategy was picked by an A/B experiment
+	Experiment *ExperimentAssignment `json:"experiment,omitempty"`
+
+	// Bandit is set when the strategy was picked by a multi-armed bandit
+	Bandit *BanditSelection `json:"bandit,omitempty"`
 }
 
 // NewSortResult creates a new sort result with the given parameters
@@ -32,7 +57,7 @@ func (sr *SortResult) Validate() error {
 		return fmt.Errorf("sort result cannot be nil")
 	}
 
-	if !sr.Strategy.IsValid() {
+	if !sr.Strategy.IsValid() && (sr.Fusion == nil || sr.Strategy != sr.Fusion.Method.Strategy()) {
 		return fmt.Errorf("invalid sort strategy: %s", sr.Strategy)
 	}
 
@@ -52,6 +77,57 @@ func (sr *SortResult) Validate() error {
 	return nil
 }
 
+// Clone returns an independent copy of the result
+func (sr *SortResult) Clone() *SortResult {
+	if sr == nil {
+		return nil
+	}
+
+	clone := *sr
+	clone.Products = sr.Products.Clone()
+	clone.PricedAt = cloneTime(sr.PricedAt)
+	if sr.EffectivePrices != nil {
+		clone.EffectivePrices = make(map[ProductID]Money, len(sr.EffectivePrices))
+		for id, price := range sr.EffectivePrices {
+			clone.EffectivePrices[id] = price
+		}
+	}
+	if sr.FiredRules != nil {
+		clone.FiredRules = make([]FiredRule, len(sr.FiredRules))
+		for i, rule := range sr.FiredRules {
+			rule.ProductIDs = append([]ProductID(nil), rule.ProductIDs...)
+			clone.FiredRules[i] = rule
+		}
+	}
+	if sr.Fusion != nil {
+		clone.Fusion = sr.Fusion.clone()
+	}
+	if sr.Experiment != nil {
+		assignment := *sr.Experiment
+		clone.Experiment = &assignment
+	}
+	if sr.Bandit != nil {
+		selection := *sr.Bandit
+		clone.Bandit = &selection
+	}
+	if sr.Explanations != nil {
+		clone.Explanations = make([]RankExplanation, len(sr.Explanations))
+		for i, explanation := range sr.Explanations {
+			clone.Explanations[i] = explanation.clone()
+		}
+	}
+	return &clone
+}
+
+// EffectivePrice returns the price the product sold for when the result was
+// priced, falling back to its list price
+func (sr *SortResult) EffectivePrice(product Product) Money {
+	if price, ok := sr.EffectivePrices[product.ID]; ok {
+		return price
+	}
+	return product.Price
+}
+
 // GetTopProducts returns the top N products from the sorted result
 func (sr *SortResult) GetTopProducts(n int) ProductCollection {
 	if n <= 0 || len(sr.Products) == 0 {
@@ -19,6 +19,10 @@ const (
 	SortByPopularity            SortStrategy = "popularity"
 	SortByRevenue               SortStrategy = "revenue"
 	SortByName                  SortStrategy = "name"
+	SortByMargin                SortStrategy = "margin"
+	SortByProfit                SortStrategy = "profit"
+	SortByProfitPerView         SortStrategy = "profit_per_view"
+	SortByRating                SortStrategy = "top_rated"
 )
 
 // AllSortStrategies returns all available sort strategies
@@ -32,6 +36,10 @@ func AllSortStrategies() []SortStrategy {
 		SortByPopularity,
 		SortByRevenue,
 		SortByName,
+		SortByMargin,
+		SortByProfit,
+		SortByProfitPerView,
+		SortByRating,
 	}
 }
 
@@ -47,7 +55,8 @@ func (s SortStrategy) IsValid() bool {
 			return true
 		}
 	}
-	return false
+	_, isAttribute := s.AttributeSpec()
+	return isAttribute
 }
 
 // Description returns a human-readable description of the sort strategy
@@ -69,7 +78,18 @@ func (s SortStrategy) Description() string {
 		return "Revenue Generated (Highest First)"
 	case SortByName:
 		return "Name (Alphabetical)"
+	case SortByMargin:
+		return "Gross Margin (Highest First)"
+	case SortByProfit:
+		return "Total Profit (Highest First)"
+	case SortByProfitPerView:
+		return "Profit per View (Highest First)"
+	case SortByRating:
+		return "Top Rated (Bayesian Average)"
 	default:
+		if spec, ok := s.AttributeSpec(); ok {
+			return spec.Description()
+		}
 		return fmt.Sprintf("Unknown Strategy (%s)", s)
 	}
 }
@@ -80,9 +100,9 @@ func (s SortStrategy) Priority() int {
 	switch s {
 	case SortBySalesConversionRatio:
 		return 10 // Highest priority - directly impacts revenue
-	case SortByRevenue:
+	case SortByRevenue, SortByProfit:
 		return 9
-	case SortByPopularity:
+	case SortByPopularity, SortByMargin, SortByProfitPerView, SortByRating:
 		return 8
 	case SortByPriceAsc, SortByPriceDesc:
 		return 7
@@ -97,6 +117,53 @@ func (s SortStrategy) Priority() int {
 	}
 }
 
+// SortKey reduces a product to the fields this strategy's ordering reads,
+// including every tie-breaker down to the ID. Unknown strategies keep the
+// whole product so comparisons remain correct.
+func (s SortStrategy) SortKey(product Product) Product {
+	key := Product{ID: product.ID}
+
+	switch s {
+	case SortByPriceAsc, SortByPriceDesc:
+		key.Price = product.Price
+		key.Category = product.Category
+	case SortBySalesConversionRatio:
+		key.SalesCount = product.SalesCount
+		key.ViewsCount = product.ViewsCount
+	case SortByCreatedAtDesc, SortByCreatedAtAsc:
+		key.CreatedAt = product.CreatedAt
+	case SortByPopularity:
+		key.ViewsCount = product.ViewsCount
+		key.SalesCount = product.SalesCount
+	case SortByRevenue:
+		key.Price = product.Price
+		key.Category = product.Category
+		key.SalesCount = product.SalesCount
+	case SortByName:
+		key.Name = product.Name
+		key.LocalizedNames = product.LocalizedNames
+	case SortByMargin, SortByProfit, SortByProfitPerView:
+		key.Price = product.Price
+		key.CostPrice = product.CostPrice
+		key.ShippingCost = product.ShippingCost
+		key.SalesCount = product.SalesCount
+		key.ViewsCount = product.ViewsCount
+	case SortByRating:
+		key.RatingCount = product.RatingCount
+		key.RatingSum = product.RatingSum
+	default:
+		spec, ok := s.AttributeSpec()
+		if !ok {
+			return product
+		}
+		if value, exists := product.Attributes.Get(spec.Name); exists {
+			key.Attributes = Attributes{spec.Name: value}
+		}
+	}
+
+	return key
+}
+
 // SortStrategySet represents a collection of sort strategies with utility methods
 type SortStrategySet []SortStrategy
 
@@ -27,3 +27,18 @@ type SorterFactory interface {
 	// IsSupported checks if a strategy is supported
 	IsSupported(strategy SortStrategy) bool
 }
+
+// ProductChecker is implemented by sorters that cannot rank some valid
+// products, such as attribute sorts that require the attribute. Streaming
+// sorts check each product as it arrives.
+type ProductChecker interface {
+	// CheckProduct returns an error if the sorter cannot rank the product
+	CheckProduct(product Product) error
+}
+
+// Comparator is implemented by sorters whose ordering can be expressed as a
+// pairwise comparison. It allows sorted runs to be merged without re-sorting.
+type Comparator interface {
+	// Less reports whether product a should be ordered before product b
+	Less(a, b Product) bool
+}
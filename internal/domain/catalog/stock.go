@@ -0,0 +1,164 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Availability is the stock status of a product
+type Availability string
+
+// Availability statuses
+const (
+	// AvailabilityUntracked means stock is not tracked; the product is treated as available
+	AvailabilityUntracked Availability = ""
+	InStock               Availability = "in_stock"
+	OutOfStock            Availability = "out_of_stock"
+	Backorder             Availability = "backorder"
+)
+
+// IsValid checks if the availability status is known
+func (a Availability) IsValid() bool {
+	switch a {
+	case AvailabilityUntracked, InStock, OutOfStock, Backorder:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsInStock reports whether the product can currently be shipped
+func (p Product) IsInStock() bool {
+	return p.Availability != OutOfStock && p.Availability != Backorder
+}
+
+// IsLowStock reports whether a product with tracked stock has at most
+// threshold units left
+func (p Product) IsLowStock(threshold int) bool {
+	return p.Availability == InStock && p.StockQuantity <= threshold
+}
+
+// DemotionMode selects how a StockPolicy demotes unavailable products
+type DemotionMode string
+
+// Demotion modes
+const (
+	// DemoteBury moves demoted products below every available product
+	DemoteBury DemotionMode = "bury"
+	// DemoteDrop moves each demoted product down a fixed number of positions
+	DemoteDrop DemotionMode = "drop"
+	// DemoteExclude removes demoted products from the result
+	DemoteExclude DemotionMode = "exclude"
+)
+
+// StockPolicy demotes out-of-stock and, optionally, low-stock products in an
+// already sorted collection. It applies on top of any SortStrategy and keeps
+// the strategy's relative order within available and demoted products.
+type StockPolicy struct {
+	Mode DemotionMode `json:"mode"`
+
+	// Positions is how far DemoteDrop moves each demoted product
+	Positions int `json:"positions,omitempty"`
+
+	// LowStockThreshold also demotes tracked products with at most this many
+	// units in stock; zero demotes only products that are not in stock
+	LowStockThreshold int `json:"low_stock_threshold,omitempty"`
+}
+
+// Validate ensures the policy is well formed
+func (sp StockPolicy) Validate() error {
+	if sp.Positions < 0 {
+		return fmt.Errorf("positions cannot be negative")
+	}
+
+	switch sp.Mode {
+	case DemoteBury, DemoteExclude:
+	case DemoteDrop:
+		if sp.Positions <= 0 {
+			return fmt.Errorf("drop positions must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid demotion mode: %s", sp.Mode)
+	}
+
+	if sp.LowStockThreshold < 0 {
+		return fmt.Errorf("low stock threshold cannot be negative")
+	}
+
+	return nil
+}
+
+// String returns a compact description of the policy, e.g. "drop:5:low<=3"
+func (sp StockPolicy) String() string {
+	description := string(sp.Mode)
+	if sp.Mode == DemoteDrop {
+		description += fmt.Sprintf(":%d", sp.Positions)
+	}
+	if sp.LowStockThreshold > 0 {
+		description += fmt.Sprintf(":low<=%d", sp.LowStockThreshold)
+	}
+	return description
+}
+
+// Demotes reports whether the policy demotes the product
+func (sp StockPolicy) Demotes(product Product) bool {
+	if !product.IsInStock() {
+		return true
+	}
+	return sp.LowStockThreshold > 0 && product.IsLowStock(sp.LowStockThreshold)
+}
+
+// Apply demotes products in a sorted collection, returning a new collection
+func (sp StockPolicy) Apply(sorted ProductCollection) ProductCollection {
+	switch sp.Mode {
+	case DemoteExclude:
+		kept := make(ProductCollection, 0, len(sorted))
+		for _, product := range sorted {
+			if !sp.Demotes(product) {
+				kept = append(kept, product)
+			}
+		}
+		return kept
+
+	case DemoteDrop:
+		// Each demoted product is ranked as if it sat Positions further down,
+		// yielding ties to available products
+		type ranked struct {
+			product Product
+			rank    int
+			demoted bool
+		}
+		entries := make([]ranked, len(sorted))
+		for i, product := range sorted {
+			entries[i] = ranked{product: product, rank: i}
+			if sp.Demotes(product) {
+				entries[i].rank += sp.Positions
+				entries[i].demoted = true
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].rank != entries[j].rank {
+				return entries[i].rank < entries[j].rank
+			}
+			return !entries[i].demoted && entries[j].demoted
+		})
+
+		dropped := make(ProductCollection, len(entries))
+		for i, entry := range entries {
+			dropped[i] = entry.product
+		}
+		return dropped
+
+	default:
+		available := make(ProductCollection, 0, len(sorted))
+		var demoted ProductCollection
+		for _, product := range sorted {
+			if sp.Demotes(product) {
+				demoted = append(demoted, product)
+			} else {
+				available = append(available, product)
+			}
+		}
+		return append(available, demoted...)
+	}
+}
@@ -0,0 +1,225 @@
+package catalog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// streamReadBufferSize is the initial read buffer for JSONL sources
+	streamReadBufferSize = 32 << 10
+
+	// maxStreamRecordSize bounds a single JSONL product record
+	maxStreamRecordSize = 1 << 20
+
+	// streamCancellationInterval controls how often streaming loops poll the context
+	streamCancellationInterval = 1024
+)
+
+// ProductIterator yields products one at a time without materializing them
+type ProductIterator interface {
+	// Next advances to the next product, returning false when exhausted or failed
+	Next() bool
+
+	// Product returns the current product
+	Product() Product
+
+	// Err returns the first error encountered while iterating
+	Err() error
+}
+
+// ProductEmitter receives sorted products one at a time
+type ProductEmitter func(product Product) error
+
+// StreamOptions controls a streaming sort
+type StreamOptions struct {
+	// TopK limits the output to the first K products; zero emits everything
+	TopK int `json:"top_k,omitempty"`
+
+	// IncludeInactive keeps draft, discontinued and archived products, for admin views
+	IncludeInactive bool `json:"include_inactive,omitempty"`
+}
+
+// Validate ensures the stream options are usable
+func (o StreamOptions) Validate() error {
+	if o.TopK < 0 {
+		return fmt.Errorf("top-k cannot be negative")
+	}
+	return nil
+}
+
+// StreamSortResult describes a completed streaming sort
+type StreamSortResult struct {
+	Strategy      SortStrategy  `json:"strategy"`
+	ProductCount  int           `json:"product_count"`
+	EmittedCount  int           `json:"emitted_count"`
+	ExecutionTime time.Duration `json:"execution_time"`
+	SortedAt      time.Time     `json:"sorted_at"`
+}
+
+// JSONProductIterator decodes newline-delimited product JSON from a reader
+type JSONProductIterator struct {
+	scanner *bufio.Scanner
+	current Product
+	line    int
+	err     error
+}
+
+// NewJSONProductIterator creates an iterator over JSONL products
+func NewJSONProductIterator(r io.Reader) *JSONProductIterator {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, streamReadBufferSize), maxStreamRecordSize)
+
+	return &JSONProductIterator{scanner: scanner}
+}
+
+// Next implements the ProductIterator interface
+func (it *JSONProductIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.scanner.Scan() {
+		it.line++
+
+		record := it.scanner.Bytes()
+		if len(record) == 0 {
+			continue
+		}
+
+		it.current = Product{}
+		if err := json.Unmarshal(record, &it.current); err != nil {
+			it.err = fmt.Errorf("invalid product on line %d: %w", it.line, err)
+			return false
+		}
+		return true
+	}
+
+	if err := it.scanner.Err(); err != nil {
+		it.err = fmt.Errorf("failed to read products: %w", err)
+	}
+	return false
+}
+
+// Product implements the ProductIterator interface
+func (it *JSONProductIterator) Product() Product {
+	return it.current
+}
+
+// Err implements the ProductIterator interface
+func (it *JSONProductIterator) Err() error {
+	return it.err
+}
+
+// Line returns the line number of the current product
+func (it *JSONProductIterator) Line() int {
+	return it.line
+}
+
+// ChannelProductIterator reads products from a channel until it is closed
+type ChannelProductIterator struct {
+	ctx      context.Context
+	products <-chan Product
+	current  Product
+	err      error
+}
+
+// NewChannelProductIterator creates an iterator over a product channel.
+// Iteration stops early with the context's error if it is cancelled.
+func NewChannelProductIterator(ctx context.Context, products <-chan Product) *ChannelProductIterator {
+	return &ChannelProductIterator{ctx: ctx, products: products}
+}
+
+// Next implements the ProductIterator interface
+func (it *ChannelProductIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	select {
+	case product, ok := <-it.products:
+		if !ok {
+			return false
+		}
+		it.current = product
+		return true
+	case <-it.ctx.Done():
+		it.err = it.ctx.Err()
+		return false
+	}
+}
+
+// Product implements the ProductIterator interface
+func (it *ChannelProductIterator) Product() Product {
+	return it.current
+}
+
+// Err implements the ProductIterator interface
+func (it *ChannelProductIterator) Err() error {
+	return it.err
+}
+
+// CollectionIterator iterates over an already materialized collection
+type CollectionIterator struct {
+	products ProductCollection
+	index    int
+}
+
+// NewCollectionIterator creates an iterator over a product collection
+func NewCollectionIterator(products ProductCollection) *CollectionIterator {
+	return &CollectionIterator{products: products, index: -1}
+}
+
+// Next implements the ProductIterator interface
+func (it *CollectionIterator) Next() bool {
+	if it.index+1 >= len(it.products) {
+		return false
+	}
+	it.index++
+	return true
+}
+
+// Product implements the ProductIterator interface
+func (it *CollectionIterator) Product() Product {
+	return it.products[it.index]
+}
+
+// Err implements the ProductIterator interface
+func (it *CollectionIterator) Err() error {
+	return nil
+}
+
+// NewNDJSONEmitter returns an emitter that writes each product as a JSON line
+func NewNDJSONEmitter(w io.Writer) ProductEmitter {
+	encoder := json.NewEncoder(w)
+	return func(product Product) error {
+		if err := encoder.Encode(product); err != nil {
+			return fmt.Errorf("failed to write product: %w", err)
+		}
+		return nil
+	}
+}
+
+// topKHeap keeps the best K products seen so far, with the worst at the root
+type topKHeap struct {
+	products ProductCollection
+	less     func(a, b Product) bool
+}
+
+func (h *topKHeap) Len() int { return len(h.products) }
+
+func (h *topKHeap) Less(i, j int) bool { return h.less(h.products[j], h.products[i]) }
+
+func (h *topKHeap) Swap(i, j int) { h.products[i], h.products[j] = h.products[j], h.products[i] }
+
+func (h *topKHeap) Push(x interface{}) { h.products = append(h.products, x.(Product)) }
+
+func (h *topKHeap) Pop() interface{} {
+	last := h.products[len(h.products)-1]
+	h.products = h.products[:len(h.products)-1]
+	return last
+}
@@ -0,0 +1,213 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrNestedVariant is returned when a variant's parent is itself a variant
+var ErrNestedVariant = errors.New("nested variant")
+
+// VariantMode selects how products with variants are ranked
+type VariantMode string
+
+// Variant modes
+const (
+	// VariantsFlat ranks every product individually, ignoring parents
+	VariantsFlat VariantMode = ""
+	// VariantsParents ranks parents by aggregated metrics and returns the
+	// aggregated parent products
+	VariantsParents VariantMode = "parents"
+	// VariantsBest ranks parents by aggregated metrics and returns the best
+	// variant of each parent under the same strategy
+	VariantsBest VariantMode = "best_variant"
+)
+
+// IsValid checks if the variant mode is known
+func (m VariantMode) IsValid() bool {
+	switch m {
+	case VariantsFlat, VariantsParents, VariantsBest:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsVariant reports whether the product is a variant of a parent product
+func (p Product) IsVariant() bool {
+	return p.ParentID != 0
+}
+
+// VariantGroup is a parent product with its variants. Parent is the parent's
+// own record when the collection contains it; otherwise it is derived from
+// the lowest-ID variant. Standalone products form a group with no variants.
+type VariantGroup struct {
+	Parent   Product
+	Variants ProductCollection
+}
+
+// Members returns the products ranked for the group: its variants, or the
+// parent itself when it has none
+func (g VariantGroup) Members() ProductCollection {
+	if len(g.Variants) == 0 {
+		return ProductCollection{g.Parent}
+	}
+	return g.Variants
+}
+
+// Aggregate returns the parent with metrics rolled up from its variants:
+//
+//   - Price is the "from" price, the minimum variant price; CostPrice and
+//     ShippingCost come from that cheapest variant so margins stay consistent
+//   - SalesCount, ViewsCount, StockQuantity, RatingCount and RatingSum are summed
+//   - CreatedAt is the earliest variant creation time
+//   - Availability is in stock when any variant is, else backorder when any
+//     variant is, else out of stock; untracked variants count as in stock
+//
+// Identity fields (ID, Name, Category, Attributes, Status) come from the parent.
+// Groups without variants return the parent unchanged.
+func (g VariantGroup) Aggregate() Product {
+	if len(g.Variants) == 0 {
+		return g.Parent
+	}
+
+	aggregate := g.Parent
+	aggregate.ParentID = 0
+	aggregate.SalesCount = 0
+	aggregate.ViewsCount = 0
+	aggregate.StockQuantity = 0
+	aggregate.RatingCount = 0
+	aggregate.RatingSum = 0
+	aggregate.CreatedAt = time.Time{}
+
+	cheapest := g.Variants[0]
+	inStock, backorder := false, false
+	for _, variant := range g.Variants {
+		if variant.Price.Cmp(cheapest.Price) < 0 {
+			cheapest = variant
+		}
+
+		aggregate.SalesCount += variant.SalesCount
+		aggregate.ViewsCount += variant.ViewsCount
+		aggregate.StockQuantity += variant.StockQuantity
+		aggregate.RatingCount += variant.RatingCount
+		aggregate.RatingSum += variant.RatingSum
+
+		if aggregate.CreatedAt.IsZero() || variant.CreatedAt.Before(aggregate.CreatedAt) {
+			aggregate.CreatedAt = variant.CreatedAt
+		}
+
+		switch {
+		case variant.IsInStock():
+			inStock = true
+		case variant.Availability == Backorder:
+			backorder = true
+		}
+	}
+
+	aggregate.Price = cheapest.Price
+	aggregate.CostPrice = cheapest.CostPrice
+	aggregate.ShippingCost = cheapest.ShippingCost
+	aggregate.LossLeader = cheapest.LossLeader
+
+	switch {
+	case inStock:
+		aggregate.Availability = InStock
+	case backorder:
+		aggregate.Availability = Backorder
+	default:
+		aggregate.Availability = OutOfStock
+	}
+
+	return aggregate
+}
+
+// VariantGroups is a collection of variant groups
+type VariantGroups []VariantGroup
+
+// GroupVariants groups products under their parents, ordered by parent ID.
+// Variants of one parent must share a price currency so the "from" price is
+// well defined. Variants are one level deep: a variant whose parent is
+// itself a variant is an ErrNestedVariant.
+func GroupVariants(products ProductCollection) (VariantGroups, error) {
+	parents := make(map[ProductID]Product)
+	variants := make(map[ProductID]ProductCollection)
+	variantIDs := make(map[ProductID]bool)
+	for _, product := range products {
+		if product.IsVariant() {
+			variants[product.ParentID] = append(variants[product.ParentID], product)
+			variantIDs[product.ID] = true
+		} else {
+			parents[product.ID] = product
+		}
+	}
+
+	for _, product := range products {
+		if product.IsVariant() && variantIDs[product.ParentID] {
+			return nil, fmt.Errorf("%w: product %d has parent %d, which is a variant itself",
+				ErrNestedVariant, product.ID, product.ParentID)
+		}
+	}
+
+	groups := make(VariantGroups, 0, len(parents)+len(variants))
+	for id, parent := range parents {
+		if _, hasVariants := variants[id]; !hasVariants {
+			groups = append(groups, VariantGroup{Parent: parent})
+		}
+	}
+
+	for id, members := range variants {
+		members = members.Copy()
+		sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+
+		currency := members[0].Price.Currency.OrDefault()
+		for _, variant := range members[1:] {
+			if variant.Price.Currency.OrDefault() != currency {
+				return nil, fmt.Errorf("%w: variants of product %d are priced in %s and %s",
+					ErrCurrencyMismatch, id, currency, variant.Price.Currency.OrDefault())
+			}
+		}
+
+		parent, exists := parents[id]
+		if !exists {
+			parent = members[0]
+			parent.ID = id
+			parent.ParentID = 0
+			parent.Status = StatusUnset
+		}
+		groups = append(groups, VariantGroup{Parent: parent, Variants: members})
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Parent.ID < groups[j].Parent.ID })
+	return groups, nil
+}
+
+// FilterActive keeps groups whose parent is active, with only their active
+// variants. Parents whose variants are all inactive are dropped.
+func (groups VariantGroups) FilterActive() VariantGroups {
+	var active VariantGroups
+	for _, group := range groups {
+		if !group.Parent.IsActive() {
+			continue
+		}
+		if len(group.Variants) == 0 {
+			active = append(active, group)
+			continue
+		}
+		if variants := group.Variants.FilterActive(); len(variants) > 0 {
+			active = append(active, VariantGroup{Parent: group.Parent, Variants: variants})
+		}
+	}
+	return active
+}
+
+// Aggregates returns the aggregated parent of every group
+func (groups VariantGroups) Aggregates() ProductCollection {
+	aggregates := make(ProductCollection, len(groups))
+	for i, group := range groups {
+		aggregates[i] = group.Aggregate()
+	}
+	return aggregates
+}
@@ -0,0 +1,227 @@
+package bandit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// FileStore keeps bandit states in a JSON array on disk, so bandits resume
+// with what they learned, and where their random sequence stopped, after a
+// restart. Every save rewrites the file through a temporary file and a
+// rename, so the file always holds a complete set of states.
+//
+// Saves that arrive while the file is being written are batched into the
+// next write, so concurrent saves of different bandits share one file write
+// instead of queueing for one each. Save still returns only once its state
+// is on disk.
+//
+// The file is read once when the store opens, so only one process may own
+// it: a second process writing the same file would have its changes
+// overwritten by the owner's next save. Stop the server before changing its
+// bandits from the command line.
+type FileStore struct {
+	path string
+
+	mu     sync.RWMutex
+	states map[string]versionedState
+
+	// persisted holds the states last written to the file; version counts
+	// saves, written is the last version on disk, and batchVersion and
+	// batchErr record the outcome of the latest write
+	persisted    map[string]catalog.BanditState
+	version      uint64
+	written      uint64
+	batchVersion uint64
+	batchErr     error
+
+	// writeMu serialises file writes
+	writeMu sync.Mutex
+}
+
+// versionedState is a saved state with the save that produced it
+type versionedState struct {
+	state   catalog.BanditState
+	version uint64
+}
+
+// NewFileStore opens the store at path. A missing file is an empty store and
+// is created on the first save.
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("bandit state file path cannot be empty")
+	}
+
+	persisted, err := loadStates(path)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]versionedState, len(persisted))
+	for name, state := range persisted {
+		states[name] = versionedState{state: state}
+	}
+	return &FileStore{path: path, states: states, persisted: persisted}, nil
+}
+
+// Find returns the state of the named bandit
+func (s *FileStore) Find(ctx context.Context, name string) (*catalog.BanditState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	saved, exists := s.states[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrBanditNotFound, name)
+	}
+	state := copyState(saved.state)
+	return &state, nil
+}
+
+// FindAll returns every bandit state ordered by name
+func (s *FileStore) FindAll(ctx context.Context) ([]catalog.BanditState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedLocked(), nil
+}
+
+// Save stores a bandit state and writes it to the file, together with any
+// other states saved meanwhile
+func (s *FileStore) Save(ctx context.Context, state catalog.BanditState) error {
+	if err := state.Config.Validate(); err != nil {
+		return err
+	}
+	state = copyState(state)
+
+	s.mu.Lock()
+	s.version++
+	version := s.version
+	s.states[state.Config.Name] = versionedState{state: state, version: version}
+	s.mu.Unlock()
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	// A write that started after this save already reported its outcome
+	s.mu.RLock()
+	if s.batchVersion >= version {
+		err := s.batchErr
+		s.mu.RUnlock()
+		return err
+	}
+	batch := s.version
+	states := s.sortedLocked()
+	s.mu.RUnlock()
+
+	err := s.write(states)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batchVersion, s.batchErr = batch, err
+	if err == nil {
+		s.written = batch
+		s.persisted = make(map[string]catalog.BanditState, len(states))
+		for _, written := range states {
+			s.persisted[written.Config.Name] = written
+		}
+		return nil
+	}
+
+	// Undo the batch's saves; later saves are written by their own batch
+	for name, saved := range s.states {
+		if saved.version <= s.written || saved.version > batch {
+			continue
+		}
+		if previous, existed := s.persisted[name]; existed {
+			s.states[name] = versionedState{state: previous, version: s.written}
+		} else {
+			delete(s.states, name)
+		}
+	}
+	return err
+}
+
+// sortedLocked returns copies of the states ordered by bandit name
+func (s *FileStore) sortedLocked() []catalog.BanditState {
+	states := make([]catalog.BanditState, 0, len(s.states))
+	for _, saved := range s.states {
+		states = append(states, copyState(saved.state))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Config.Name < states[j].Config.Name })
+	return states
+}
+
+// write replaces the file with states
+func (s *FileStore) write(states []catalog.BanditState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bandit states: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write bandit state file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(append(data, '\n')); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write bandit state file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write bandit state file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace bandit state file: %w", err)
+	}
+	return nil
+}
+
+// copyState returns a state that shares no slices or pointers with the
+// original
+func copyState(state catalog.BanditState) catalog.BanditState {
+	state.Config.Strategies = append(catalog.SortStrategySet(nil), state.Config.Strategies...)
+	if state.Config.Epsilon != nil {
+		epsilon := *state.Config.Epsilon
+		state.Config.Epsilon = &epsilon
+	}
+	state.Arms = append([]catalog.ArmState(nil), state.Arms...)
+	return state
+}
+
+// loadStates reads a bandit state file, checking every state can be restored
+func loadStates(path string) (map[string]catalog.BanditState, error) {
+	states := make(map[string]catalog.BanditState)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bandit state file: %w", err)
+	}
+
+	var list []catalog.BanditState
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse bandit state file %s: %w", path, err)
+	}
+
+	for _, state := range list {
+		if _, err := catalog.RestoreBandit(state); err != nil {
+			return nil, fmt.Errorf("invalid bandit state file %s: %w", path, err)
+		}
+		if _, duplicate := states[state.Config.Name]; duplicate {
+			return nil, fmt.Errorf("invalid bandit state file %s: duplicate bandit %s", path, state.Config.Name)
+		}
+		states[state.Config.Name] = state
+	}
+	return states, nil
+}
@@ -0,0 +1,61 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// LoadJudgments reads relevance judgments from a JSON file:
+//
+//	{"queries": [{"id": "q1", "gains": {"12": 3, "40": 1}}],
+//	 "sessions": [{"id": "s1", "purchases": [12, 15]}]}
+//
+// The judgments are validated when loaded.
+func LoadJudgments(path string) (catalog.Judgments, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return catalog.Judgments{}, fmt.Errorf("failed to read judgments file: %w", err)
+	}
+
+	var judgments catalog.Judgments
+	if err := json.Unmarshal(data, &judgments); err != nil {
+		return catalog.Judgments{}, fmt.Errorf("failed to parse judgments file %s: %w", path, err)
+	}
+
+	if err := judgments.Validate(); err != nil {
+		return catalog.Judgments{}, fmt.Errorf("invalid judgments file %s: %w", path, err)
+	}
+
+	return judgments, nil
+}
+
+// SaveReport writes an evaluation report as indented JSON so runs can be
+// compared later
+func SaveReport(path string, report *catalog.EvaluationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode evaluation report: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write evaluation report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads an evaluation report written by SaveReport
+func LoadReport(path string) (*catalog.EvaluationReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evaluation report: %w", err)
+	}
+
+	var report catalog.EvaluationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse evaluation report %s: %w", path, err)
+	}
+	return &report, nil
+}
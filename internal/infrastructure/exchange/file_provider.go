@@ -0,0 +1,98 @@
+package exchange
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// snapshotIDLength is the number of hash characters in a derived snapshot ID
+const snapshotIDLength = 12
+
+// FileRateProvider serves exchange rates loaded from a JSON rates table:
+//
+//	{"id": "2024-06-01", "base": "USD", "as_of": "2024-06-01T00:00:00Z",
+//	 "rates": {"EUR": 0.92, "GBP": 0.79}}
+//
+// The id and as_of fields are optional; when missing, the ID is derived from
+// the file contents and the timestamp from the file's modification time.
+type FileRateProvider struct {
+	path string
+
+	mu       sync.RWMutex
+	snapshot *catalog.RateSnapshot
+}
+
+// NewFileRateProvider creates a provider and loads the rates table at path
+func NewFileRateProvider(path string) (*FileRateProvider, error) {
+	if path == "" {
+		return nil, fmt.Errorf("rates file path cannot be empty")
+	}
+
+	provider := &FileRateProvider{path: path}
+	if err := provider.Reload(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Snapshot returns the most recently loaded rates
+func (p *FileRateProvider) Snapshot(ctx context.Context) (*catalog.RateSnapshot, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.snapshot, nil
+}
+
+// Reload re-reads the rates table. The previous snapshot stays in use if the
+// file cannot be loaded.
+func (p *FileRateProvider) Reload() error {
+	snapshot, err := loadRateSnapshot(p.path)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.snapshot = snapshot
+	p.mu.Unlock()
+
+	return nil
+}
+
+// loadRateSnapshot reads and validates a rates table
+func loadRateSnapshot(path string) (*catalog.RateSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %w", err)
+	}
+
+	var snapshot catalog.RateSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file %s: %w", path, err)
+	}
+
+	if snapshot.ID == "" {
+		sum := sha256.Sum256(data)
+		snapshot.ID = "sha256:" + hex.EncodeToString(sum[:])[:snapshotIDLength]
+	}
+
+	if snapshot.AsOf.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat rates file: %w", err)
+		}
+		snapshot.AsOf = info.ModTime().UTC()
+	}
+
+	if err := snapshot.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rates file %s: %w", path, err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,150 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// EventLog appends experiment events to an NDJSON file, in the format
+// ReadEvents reads, so experiment outcomes can be rebuilt after a restart.
+// Each batch is written with one append and synced before Append returns.
+//
+// A failed append is truncated away. A torn final line left by a crash is
+// dropped with a warning the next time the log is read or appended to, so
+// one interrupted write never makes the whole log unreadable.
+type EventLog struct {
+	path   string
+	logger *zap.Logger
+	mu     sync.Mutex
+}
+
+// NewEventLog opens the log at path. A missing file is an empty log and is
+// created on the first append.
+func NewEventLog(path string, logger *zap.Logger) (*EventLog, error) {
+	if path == "" {
+		return nil, fmt.Errorf("experiment event log path cannot be empty")
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &EventLog{path: path, logger: logger}, nil
+}
+
+// Append writes events to the end of the log
+func (l *EventLog) Append(ctx context.Context, events []catalog.ExperimentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode experiment event: %w", err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open experiment event log: %w", err)
+	}
+	defer file.Close()
+
+	offset, err := l.repairTail(file)
+	if err != nil {
+		return err
+	}
+
+	// Leave the log as it was if any part of the batch fails to land
+	if _, err := file.WriteAt(buffer.Bytes(), offset); err != nil {
+		return l.rollback(file, offset, err)
+	}
+	if err := file.Sync(); err != nil {
+		return l.rollback(file, offset, err)
+	}
+	return nil
+}
+
+// rollback truncates a failed append back to offset
+func (l *EventLog) rollback(file *os.File, offset int64, cause error) error {
+	if err := file.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to write experiment event log: %w (truncating the partial batch also failed: %v)", cause, err)
+	}
+	return fmt.Errorf("failed to write experiment event log: %w", cause)
+}
+
+// repairTail drops a torn final line, one without a trailing newline, and
+// returns the offset the next batch is written at
+func (l *EventLog) repairTail(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read experiment event log: %w", err)
+	}
+	size := info.Size()
+	if size == 0 {
+		return 0, nil
+	}
+
+	// Only a log that was cut short needs reading in full
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, size-1); err != nil {
+		return 0, fmt.Errorf("failed to read experiment event log: %w", err)
+	}
+	if last[0] == '\n' {
+		return size, nil
+	}
+
+	data := make([]byte, size)
+	if _, err := file.ReadAt(data, 0); err != nil {
+		return 0, fmt.Errorf("failed to read experiment event log: %w", err)
+	}
+	end := int64(bytes.LastIndexByte(data, '\n') + 1)
+
+	l.logger.Warn("Dropping torn final line of experiment event log",
+		zap.String("path", l.path),
+		zap.Int64("bytes", size-end),
+	)
+	if err := file.Truncate(end); err != nil {
+		return 0, fmt.Errorf("failed to repair experiment event log: %w", err)
+	}
+	return end, nil
+}
+
+// Events reads every event in the log, dropping a torn final line
+func (l *EventLog) Events(ctx context.Context) ([]catalog.ExperimentEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiment event log: %w", err)
+	}
+	defer file.Close()
+
+	end, err := l.repairTail(file)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := ReadEvents(io.NewSectionReader(file, 0, end))
+	if err != nil {
+		return nil, fmt.Errorf("invalid experiment event log %s: %w", l.path, err)
+	}
+	return events, nil
+}
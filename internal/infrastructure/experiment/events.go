@@ -0,0 +1,37 @@
+package experiment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// ReadEvents decodes an NDJSON stream of experiment events, one per line:
+//
+//	{"experiment": "ranking-q3", "variant": "control", "type": "impression", "unit_id": "user-42"}
+//	{"experiment": "ranking-q3", "variant": "control", "type": "purchase", "unit_id": "user-42",
+//	 "product_id": 12, "revenue": {"minor_units": 2599, "currency": "USD"}}
+//
+// Every event is validated as it is read.
+func ReadEvents(r io.Reader) ([]catalog.ExperimentEvent, error) {
+	decoder := json.NewDecoder(r)
+
+	var events []catalog.ExperimentEvent
+	for {
+		var event catalog.ExperimentEvent
+		err := decoder.Decode(&event)
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode experiment event %d: %w", len(events)+1, err)
+		}
+		if err := event.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid experiment event %d: %w", len(events)+1, err)
+		}
+		events = append(events, event)
+	}
+}
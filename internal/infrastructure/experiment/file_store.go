@@ -0,0 +1,172 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// FileStore keeps experiment definitions in a JSON array on disk:
+//
+//	[{"name": "ranking-q3", "salt": "7f3a",
+//	  "variants": [{"name": "control", "strategy": "popularity", "traffic": 50},
+//	               {"name": "conversion", "strategy": "sales_conversion_ratio", "traffic": 50}],
+//	  "starts_at": "2024-07-01T00:00:00Z", "ends_at": "2024-08-01T00:00:00Z"}]
+//
+// Every change is written to a temporary file and renamed into place, so
+// the file always holds a complete set of experiments.
+type FileStore struct {
+	path string
+
+	mu          sync.RWMutex
+	experiments map[string]catalog.Experiment
+}
+
+// NewFileStore opens the store at path. A missing file is an empty store and
+// is created on the first save.
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("experiments file path cannot be empty")
+	}
+
+	experiments, err := loadExperiments(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{path: path, experiments: experiments}, nil
+}
+
+// Find returns the experiment with the given name
+func (s *FileStore) Find(ctx context.Context, name string) (*catalog.Experiment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	experiment, exists := s.experiments[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrExperimentNotFound, name)
+	}
+	experiment.Variants = append([]catalog.ExperimentVariant(nil), experiment.Variants...)
+	return &experiment, nil
+}
+
+// FindAll returns every experiment ordered by name
+func (s *FileStore) FindAll(ctx context.Context) ([]catalog.Experiment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedLocked(), nil
+}
+
+// Save validates an experiment, stores it and rewrites the file
+func (s *FileStore) Save(ctx context.Context, experiment catalog.Experiment) error {
+	if err := experiment.Validate(); err != nil {
+		return err
+	}
+	experiment.Variants = append([]catalog.ExperimentVariant(nil), experiment.Variants...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.experiments[experiment.Name]
+	s.experiments[experiment.Name] = experiment
+	if err := s.writeLocked(); err != nil {
+		if existed {
+			s.experiments[experiment.Name] = previous
+		} else {
+			delete(s.experiments, experiment.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes an experiment and rewrites the file
+func (s *FileStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, exists := s.experiments[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", catalog.ErrExperimentNotFound, name)
+	}
+
+	delete(s.experiments, name)
+	if err := s.writeLocked(); err != nil {
+		s.experiments[name] = previous
+		return err
+	}
+	return nil
+}
+
+// sortedLocked returns copies of the experiments ordered by name
+func (s *FileStore) sortedLocked() []catalog.Experiment {
+	experiments := make([]catalog.Experiment, 0, len(s.experiments))
+	for _, experiment := range s.experiments {
+		experiment.Variants = append([]catalog.ExperimentVariant(nil), experiment.Variants...)
+		experiments = append(experiments, experiment)
+	}
+	sort.Slice(experiments, func(i, j int) bool { return experiments[i].Name < experiments[j].Name })
+	return experiments
+}
+
+// writeLocked replaces the file with the current experiments
+func (s *FileStore) writeLocked() error {
+	data, err := json.MarshalIndent(s.sortedLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode experiments: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write experiments file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(append(data, '\n')); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write experiments file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write experiments file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace experiments file: %w", err)
+	}
+	return nil
+}
+
+// loadExperiments reads and validates an experiments file
+func loadExperiments(path string) (map[string]catalog.Experiment, error) {
+	experiments := make(map[string]catalog.Experiment)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return experiments, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiments file: %w", err)
+	}
+
+	var list []catalog.Experiment
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse experiments file %s: %w", path, err)
+	}
+
+	for _, experiment := range list {
+		if err := experiment.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid experiments file %s: %w", path, err)
+		}
+		if _, duplicate := experiments[experiment.Name]; duplicate {
+			return nil, fmt.Errorf("invalid experiments file %s: duplicate experiment %s", path, experiment.Name)
+		}
+		experiments[experiment.Name] = experiment
+	}
+	return experiments, nil
+}
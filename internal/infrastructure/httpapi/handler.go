@@ -0,0 +1,433 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+const (
+	// ndjsonContentType is used for streamed product bodies
+	ndjsonContentType = "application/x-ndjson"
+
+	// flushInterval controls how many products are written between flushes
+	flushInterval = 256
+
+	// defaultPageLimit is the page size used when none is requested
+	defaultPageLimit = 20
+)
+
+// Handler exposes catalog operations over HTTP
+type Handler struct {
+	service catalog.Service
+	logger  *zap.Logger
+	mux     *http.ServeMux
+}
+
+// NewHandler creates an HTTP handler backed by the catalog service
+func NewHandler(service catalog.Service, logger *zap.Logger) *Handler {
+	h := &Handler{
+		service: service,
+		logger:  logger,
+		mux:     http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("/v1/sort", h.handleSortPage)
+	h.mux.HandleFunc("/v1/sort/stream", h.handleStreamSort)
+	h.mux.HandleFunc("/v1/explain", h.handleExplain)
+	h.mux.HandleFunc("/v1/compare", h.handleCompare)
+	h.mux.HandleFunc("/v1/experiments/sort", h.handleExperimentSort)
+	h.mux.HandleFunc("/v1/experiments/events", h.handleExperimentEvents)
+	h.mux.HandleFunc("/v1/experiments/report", h.handleExperimentReport)
+	h.mux.HandleFunc("/v1/bandits/sort", h.handleBanditSort)
+	h.mux.HandleFunc("/v1/bandits/reward", h.handleBanditReward)
+
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// handleSortPage sorts an NDJSON request body and returns one page as JSON.
+// Query parameters: strategy (required), limit (default 20) and after (cursor).
+func (h *Handler) handleSortPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	strategy := catalog.SortStrategy(query.Get("strategy"))
+
+	page := catalog.PageRequest{
+		After: query.Get("after"),
+		Limit: defaultPageLimit,
+	}
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit parameter: %w", err))
+			return
+		}
+		page.Limit = parsed
+	}
+
+	products, err := readProducts(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.SortPage(r.Context(), products, strategy, page)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// handleStreamSort sorts an NDJSON request body and streams NDJSON back.
+// Query parameters: strategy (required) and top (optional top-K limit).
+func (h *Handler) handleStreamSort(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	strategy := catalog.SortStrategy(query.Get("strategy"))
+
+	options := catalog.StreamOptions{}
+	if top := query.Get("top"); top != "" {
+		topK, err := strconv.Atoi(top)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid top parameter: %w", err))
+			return
+		}
+		options.TopK = topK
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encode := catalog.NewNDJSONEmitter(w)
+	written := 0
+
+	emit := func(product catalog.Product) error {
+		if written == 0 {
+			w.Header().Set("Content-Type", ndjsonContentType)
+			w.WriteHeader(http.StatusOK)
+		}
+		if err := encode(product); err != nil {
+			return err
+		}
+		written++
+		if flusher != nil && written%flushInterval == 0 {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	source := catalog.NewJSONProductIterator(r.Body)
+	result, err := h.service.StreamSort(r.Context(), source, strategy, options, emit)
+	if err != nil {
+		// Once products have been streamed the status can no longer change
+		if written > 0 {
+			h.logger.Error("Stream sort aborted mid-response",
+				zap.String("strategy", string(strategy)),
+				zap.Int("written", written),
+				zap.Error(err),
+			)
+			return
+		}
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if written == 0 {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	h.logger.Debug("Stream sort request completed",
+		zap.String("strategy", string(strategy)),
+		zap.Int("product_count", result.ProductCount),
+		zap.Int("emitted_count", result.EmittedCount),
+	)
+}
+
+// handleExplain sorts an NDJSON request body and returns the full result
+// with a ranking explanation for every product.
+// Query parameters: strategy (required).
+func (h *Handler) handleExplain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	strategy := catalog.SortStrategy(r.URL.Query().Get("strategy"))
+
+	products, err := readProducts(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.SortProductsWithOptions(r.Context(), products, strategy, catalog.SortOptions{Explain: true})
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// handleCompare sorts an NDJSON request body with two strategies and returns
+// the ranking diff as JSON.
+// Query parameters: base and candidate (required), k (top-K cut-off) and movers.
+func (h *Handler) handleCompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	base := catalog.SortStrategy(query.Get("base"))
+	candidate := catalog.SortStrategy(query.Get("candidate"))
+
+	options := catalog.RankingDiffOptions{}
+	for name, target := range map[string]*int{"k": &options.TopK, "movers": &options.Movers} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s parameter: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	products, err := readProducts(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	diff, err := h.service.CompareStrategies(r.Context(), products, base, candidate, options)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, diff)
+}
+
+// handleExperimentSort sorts an NDJSON request body with the strategy of the
+// caller's experiment variant and returns the full result with the assignment.
+// Query parameters: experiment and unit (user or session ID), both required.
+func (h *Handler) handleExperimentSort(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+
+	products, err := readProducts(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.SortForExperiment(r.Context(), products, query.Get("experiment"), query.Get("unit"), catalog.SortOptions{})
+	if errors.Is(err, catalog.ErrExperimentNotFound) {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// handleExperimentEvents records an NDJSON body of experiment events. Outcomes
+// survive a restart only when the server has an experiment event log.
+func (h *Handler) handleExperimentEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	var events []catalog.ExperimentEvent
+	decoder := json.NewDecoder(r.Body)
+	for decoder.More() {
+		var event catalog.ExperimentEvent
+		if err := decoder.Decode(&event); err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid experiment event: %w", err))
+			return
+		}
+		events = append(events, event)
+	}
+
+	err := h.service.RecordExperimentEvents(r.Context(), events)
+	if errors.Is(err, catalog.ErrExperimentNotFound) {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, recordedResponse{Recorded: len(events)})
+}
+
+// handleExperimentReport returns per-variant outcomes and significance tests.
+// Query parameters: experiment (required), confidence, mde (minimum
+// detectable effect) and power.
+func (h *Handler) handleExperimentReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query := r.URL.Query()
+	options := catalog.ReportOptions{}
+	targets := map[string]*float64{
+		"confidence": &options.ConfidenceLevel,
+		"mde":        &options.MinimumDetectableEffect,
+		"power":      &options.Power,
+	}
+	for name, target := range targets {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s parameter: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	report, err := h.service.ExperimentReport(r.Context(), query.Get("experiment"), options)
+	if errors.Is(err, catalog.ErrExperimentNotFound) {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// handleBanditSort sorts an NDJSON request body with the strategy a bandit
+// picks and returns the full result with the selection.
+// Query parameters: bandit (required).
+func (h *Handler) handleBanditSort(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	products, err := readProducts(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.SortWithBandit(r.Context(), products, r.URL.Query().Get("bandit"), catalog.SortOptions{})
+	if errors.Is(err, catalog.ErrBanditNotFound) {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// handleBanditReward updates a bandit from a JSON reward body:
+// {"bandit": "home", "strategy": "revenue", "reward": 1}
+func (h *Handler) handleBanditReward(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	var reward catalog.BanditReward
+	if err := json.NewDecoder(r.Body).Decode(&reward); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid bandit reward: %w", err))
+		return
+	}
+
+	err := h.service.RecordBanditReward(r.Context(), reward)
+	if errors.Is(err, catalog.ErrBanditNotFound) {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, recordedResponse{Recorded: 1})
+}
+
+// recordedResponse is the JSON body returned for ingested events
+type recordedResponse struct {
+	Recorded int `json:"recorded"`
+}
+
+// errorResponse is the JSON body returned for failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes a JSON error response
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	h.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON writes a JSON response body
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Warn("Failed to write response", zap.Error(err))
+	}
+}
+
+// readProducts decodes an NDJSON request body into a collection
+func readProducts(r *http.Request) (catalog.ProductCollection, error) {
+	products := catalog.ProductCollection{}
+	source := catalog.NewJSONProductIterator(r.Body)
+	for source.Next() {
+		products = append(products, source.Product())
+	}
+	if err := source.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
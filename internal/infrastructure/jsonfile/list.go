@@ -0,0 +1,89 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Item is an entry of a JSON list file
+type Item interface {
+	Validate() error
+}
+
+// List serves the entries of a JSON array file. Every entry is validated and
+// IDs must be unique when the file is loaded; a failed reload keeps the
+// previous entries.
+type List[T Item] struct {
+	path string
+	kind string
+	id   func(T) string
+
+	mu    sync.RWMutex
+	items []T
+}
+
+// NewList creates a list and loads the file at path. kind names the entries
+// in errors, e.g. "merchandising rules", and id returns an entry's unique ID.
+func NewList[T Item](path, kind string, id func(T) string) (*List[T], error) {
+	if path == "" {
+		return nil, fmt.Errorf("%s file path cannot be empty", kind)
+	}
+
+	list := &List[T]{path: path, kind: kind, id: id}
+	if err := list.Reload(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Items returns a copy of the most recently loaded entries
+func (l *List[T]) Items() []T {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]T(nil), l.items...)
+}
+
+// Reload re-reads the file. The previous entries stay in use if the file
+// cannot be loaded.
+func (l *List[T]) Reload() error {
+	items, err := l.load()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.items = items
+	l.mu.Unlock()
+
+	return nil
+}
+
+// load reads and validates the file
+func (l *List[T]) load() ([]T, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file: %w", l.kind, err)
+	}
+
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse %s file %s: %w", l.kind, l.path, err)
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s file %s: %w", l.kind, l.path, err)
+		}
+		id := l.id(item)
+		if seen[id] {
+			return nil, fmt.Errorf("invalid %s file %s: duplicate ID %s", l.kind, l.path, id)
+		}
+		seen[id] = true
+	}
+
+	return items, nil
+}
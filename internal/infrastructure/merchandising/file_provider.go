@@ -0,0 +1,40 @@
+package merchandising
+
+import (
+	"context"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/jsonfile"
+)
+
+// FileProvider serves merchandising rules loaded from a JSON array:
+//
+//	[{"id": "hero", "action": "pin", "position": 1,
+//	  "condition": {"ids": [42]}, "ends_at": "2024-12-26T00:00:00Z"},
+//	 {"id": "clearance", "action": "bury", "positions": 10,
+//	  "condition": {"categories": ["clearance"]}}]
+//
+// Every rule is validated when the file is loaded.
+type FileProvider struct {
+	rules *jsonfile.List[catalog.MerchandisingRule]
+}
+
+// NewFileProvider creates a provider and loads the rules file at path
+func NewFileProvider(path string) (*FileProvider, error) {
+	rules, err := jsonfile.NewList(path, "merchandising rules", func(r catalog.MerchandisingRule) string { return r.ID })
+	if err != nil {
+		return nil, err
+	}
+	return &FileProvider{rules: rules}, nil
+}
+
+// Rules returns the most recently loaded rules
+func (p *FileProvider) Rules(ctx context.Context) ([]catalog.MerchandisingRule, error) {
+	return p.rules.Items(), nil
+}
+
+// Reload re-reads the rules file. The previous rules stay in use if the file
+// cannot be loaded.
+func (p *FileProvider) Reload() error {
+	return p.rules.Reload()
+}
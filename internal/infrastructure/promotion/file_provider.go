@@ -0,0 +1,87 @@
+package promotion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// FileProvider serves promotions loaded from a JSON array:
+//
+//	[{"id": "spring-sale", "type": "percentage", "percentage": 20,
+//	  "starts_at": "2024-03-01T00:00:00Z", "ends_at": "2024-03-15T00:00:00Z",
+//	  "categories": ["garden"]}]
+//
+// Every promotion is validated when the file is loaded.
+type FileProvider struct {
+	path string
+
+	mu         sync.RWMutex
+	promotions []catalog.Promotion
+}
+
+// NewFileProvider creates a provider and loads the promotions file at path
+func NewFileProvider(path string) (*FileProvider, error) {
+	if path == "" {
+		return nil, fmt.Errorf("promotions file path cannot be empty")
+	}
+
+	provider := &FileProvider{path: path}
+	if err := provider.Reload(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Promotions returns the most recently loaded promotions
+func (p *FileProvider) Promotions(ctx context.Context) ([]catalog.Promotion, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]catalog.Promotion(nil), p.promotions...), nil
+}
+
+// Reload re-reads the promotions file. The previous promotions stay in use if
+// the file cannot be loaded.
+func (p *FileProvider) Reload() error {
+	promotions, err := loadPromotions(p.path)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.promotions = promotions
+	p.mu.Unlock()
+
+	return nil
+}
+
+// loadPromotions reads and validates a promotions file
+func loadPromotions(path string) ([]catalog.Promotion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read promotions file: %w", err)
+	}
+
+	var promotions []catalog.Promotion
+	if err := json.Unmarshal(data, &promotions); err != nil {
+		return nil, fmt.Errorf("failed to parse promotions file %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(promotions))
+	for _, promotion := range promotions {
+		if err := promotion.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid promotions file %s: %w", path, err)
+		}
+		if seen[promotion.ID] {
+			return nil, fmt.Errorf("invalid promotions file %s: duplicate promotion ID %s", path, promotion.ID)
+		}
+		seen[promotion.ID] = true
+	}
+
+	return promotions, nil
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// MemoryRepository is an in-memory product repository that keeps a sorted
+// index of active products per strategy. Writes update every index in
+// O(log n), so reading a sorted page costs O(log n + page size) instead of a
+// full re-sort.
+//
+// The indexes use the factory's sorters on the stored products, so price
+// strategies rank by list price. Promotions change effective prices without a
+// write and would invalidate every price index at once, so price-dependent
+// pages are refused while a configured promotion is active or exchange rates
+// are configured.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	products map[catalog.ProductID]catalog.Product
+	indexes  map[catalog.SortStrategy]*skipList
+
+	// pricedBy and convertedBy hold the strategies ranked by selling price
+	// and by monetary amounts
+	pricedBy    map[catalog.SortStrategy]bool
+	convertedBy map[catalog.SortStrategy]bool
+
+	promotions    catalog.PromotionProvider
+	exchangeRates catalog.ExchangeRateProvider
+}
+
+// Option configures optional repository dependencies
+type Option func(*MemoryRepository)
+
+// WithPromotions sets the promotions whose activity invalidates price indexes
+func WithPromotions(provider catalog.PromotionProvider) Option {
+	return func(r *MemoryRepository) {
+		r.promotions = provider
+	}
+}
+
+// WithExchangeRates marks the catalog as multi-currency, which invalidates
+// indexes that compare monetary amounts
+func WithExchangeRates(provider catalog.ExchangeRateProvider) Option {
+	return func(r *MemoryRepository) {
+		r.exchangeRates = provider
+	}
+}
+
+// NewMemoryRepository creates a repository indexed by every supported strategy
+func NewMemoryRepository(factory catalog.SorterFactory, options ...Option) (*MemoryRepository, error) {
+	if factory == nil {
+		return nil, fmt.Errorf("sorter factory cannot be nil")
+	}
+
+	repo := &MemoryRepository{
+		products:    make(map[catalog.ProductID]catalog.Product),
+		indexes:     make(map[catalog.SortStrategy]*skipList),
+		pricedBy:    make(map[catalog.SortStrategy]bool),
+		convertedBy: make(map[catalog.SortStrategy]bool),
+	}
+	for _, option := range options {
+		option(repo)
+	}
+
+	for _, strategy := range factory.GetSupportedStrategies() {
+		sorter, err := factory.CreateSorter(strategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sorter for strategy %s: %w", strategy, err)
+		}
+
+		comparator, ok := sorter.(catalog.Comparator)
+		if !ok {
+			return nil, fmt.Errorf("sorter for strategy %s cannot be indexed", strategy)
+		}
+		repo.indexes[strategy] = newSkipList(comparator.Less)
+
+		_, repo.pricedBy[strategy] = sorter.(catalog.PriceResolvingSorter)
+		_, repo.convertedBy[strategy] = sorter.(catalog.CurrencyAwareSorter)
+	}
+
+	return repo, nil
+}
+
+// GetProducts returns products matching the filter ordered by ID
+func (r *MemoryRepository) GetProducts(ctx context.Context, filter catalog.ProductFilter) (catalog.ProductCollection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := r.filterLocked(filter)
+	sort.Sort(matched)
+
+	return paginate(matched, filter.Offset, filter.Limit), nil
+}
+
+// GetProductByID retrieves a single product by ID
+func (r *MemoryRepository) GetProductByID(ctx context.Context, id catalog.ProductID) (*catalog.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, exists := r.products[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", catalog.ErrProductNotFound, id)
+	}
+	return &product, nil
+}
+
+// SaveProduct validates and stores a product, updating every sorted index
+func (r *MemoryRepository) SaveProduct(ctx context.Context, product *catalog.Product) error {
+	if product == nil {
+		return fmt.Errorf("product cannot be nil")
+	}
+	if err := product.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Remove the previous version using its old sort keys before re-inserting
+	if existing, exists := r.products[product.ID]; exists {
+		for _, index := range r.indexes {
+			index.Delete(existing)
+		}
+	}
+
+	r.products[product.ID] = *product
+	if product.IsActive() {
+		for _, index := range r.indexes {
+			index.Insert(*product)
+		}
+	}
+
+	return nil
+}
+
+// DeleteProduct removes a product and its index entries
+func (r *MemoryRepository) DeleteProduct(ctx context.Context, id catalog.ProductID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.products[id]
+	if !exists {
+		return fmt.Errorf("%w: %d", catalog.ErrProductNotFound, id)
+	}
+
+	for _, index := range r.indexes {
+		index.Delete(existing)
+	}
+	delete(r.products, id)
+
+	return nil
+}
+
+// GetProductCount returns the number of products matching the filter
+func (r *MemoryRepository) GetProductCount(ctx context.Context, filter catalog.ProductFilter) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if filter.IsEmpty() && filter.IncludeInactive {
+		return len(r.products), nil
+	}
+	return len(r.filterLocked(filter)), nil
+}
+
+// GetSortedPage reads a page straight from the strategy's sorted index
+func (r *MemoryRepository) GetSortedPage(ctx context.Context, strategy catalog.SortStrategy, offset, limit int) (catalog.ProductCollection, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset cannot be negative")
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("limit cannot be negative")
+	}
+
+	if err := r.checkIndex(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.indexes[strategy].Range(offset, limit), nil
+}
+
+// GetSortedPageAfter reads the page following the request cursor from the index
+func (r *MemoryRepository) GetSortedPageAfter(ctx context.Context, strategy catalog.SortStrategy, page catalog.PageRequest) (*catalog.PageResult, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("page request validation failed: %w", err)
+	}
+
+	after, err := page.ResolveCursor(strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.checkIndex(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index := r.indexes[strategy]
+	var (
+		products catalog.ProductCollection
+		hasMore  bool
+	)
+	if after == nil {
+		products, hasMore = index.First(page.Limit)
+	} else {
+		products, hasMore = index.After(after.Key, page.Limit)
+	}
+
+	return catalog.NewPageResult(products, strategy, hasMore), nil
+}
+
+// checkIndex ensures the strategy has an index whose order is still correct
+func (r *MemoryRepository) checkIndex(ctx context.Context, strategy catalog.SortStrategy) error {
+	if _, exists := r.indexes[strategy]; !exists {
+		return fmt.Errorf("no sorted index for strategy: %s", strategy)
+	}
+
+	if r.exchangeRates != nil && r.convertedBy[strategy] {
+		return fmt.Errorf("%w: %s compares amounts across currencies", catalog.ErrIndexStale, strategy)
+	}
+
+	if r.promotions != nil && r.pricedBy[strategy] {
+		promotions, err := r.promotions.Promotions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load promotions: %w", err)
+		}
+
+		now := time.Now()
+		for _, promotion := range promotions {
+			if promotion.IsActiveAt(now) {
+				return fmt.Errorf("%w: promotion %s changes %s prices", catalog.ErrIndexStale, promotion.ID, strategy)
+			}
+		}
+	}
+
+	return nil
+}
+
+// filterLocked collects matching products; the caller must hold the lock
+func (r *MemoryRepository) filterLocked(filter catalog.ProductFilter) catalog.ProductCollection {
+	matched := catalog.ProductCollection{}
+	for _, product := range r.products {
+		if filter.Matches(product) {
+			matched = append(matched, product)
+		}
+	}
+	return matched
+}
+
+// paginate applies offset and limit to an ordered collection
+func paginate(products catalog.ProductCollection, offset, limit int) catalog.ProductCollection {
+	if offset >= len(products) {
+		return catalog.ProductCollection{}
+	}
+	if offset > 0 {
+		products = products[offset:]
+	}
+	if limit > 0 && limit < len(products) {
+		products = products[:limit]
+	}
+	return products
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"math/rand"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+const (
+	// maxSkipListLevel supports well over a billion entries at p = 1/4
+	maxSkipListLevel = 16
+
+	// skipListBranching is the inverse probability of promoting a node a level
+	skipListBranching = 4
+)
+
+// skipNode is a single entry in the skip list
+type skipNode struct {
+	product catalog.Product
+	next    []*skipNode
+	span    []int // number of entries skipped by following next[i]
+}
+
+// skipList is an order-statistic skip list. Every link records how many
+// entries it spans, so the entry at a given rank is found in O(log n).
+type skipList struct {
+	head   *skipNode
+	level  int
+	length int
+	less   func(a, b catalog.Product) bool
+	rng    *rand.Rand
+}
+
+// newSkipList creates an empty skip list ordered by less, which must be a
+// strict total order (every built-in comparator breaks ties by product ID)
+func newSkipList(less func(a, b catalog.Product) bool) *skipList {
+	return &skipList{
+		head: &skipNode{
+			next: make([]*skipNode, maxSkipListLevel),
+			span: make([]int, maxSkipListLevel),
+		},
+		level: 1,
+		less:  less,
+		// A fixed seed keeps the index layout reproducible between runs
+		rng: rand.New(rand.NewSource(1)),
+	}
+}
+
+// Len returns the number of entries in the list
+func (l *skipList) Len() int {
+	return l.length
+}
+
+// randomLevel picks the height of a new node
+func (l *skipList) randomLevel() int {
+	level := 1
+	for level < maxSkipListLevel && l.rng.Intn(skipListBranching) == 0 {
+		level++
+	}
+	return level
+}
+
+// Insert adds a product in sorted position in O(log n)
+func (l *skipList) Insert(product catalog.Product) {
+	var (
+		update [maxSkipListLevel]*skipNode
+		rank   [maxSkipListLevel]int
+	)
+
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		if i < l.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for node.next[i] != nil && l.less(node.next[i].product, product) {
+			rank[i] += node.span[i]
+			node = node.next[i]
+		}
+		update[i] = node
+	}
+
+	level := l.randomLevel()
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = l.head
+			update[i].span[i] = l.length
+		}
+		l.level = level
+	}
+
+	inserted := &skipNode{
+		product: product,
+		next:    make([]*skipNode, level),
+		span:    make([]int, level),
+	}
+	for i := 0; i < level; i++ {
+		inserted.next[i] = update[i].next[i]
+		update[i].next[i] = inserted
+
+		inserted.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+
+	// Links above the new node's height now skip one more entry
+	for i := level; i < l.level; i++ {
+		update[i].span[i]++
+	}
+
+	l.length++
+}
+
+// Delete removes the entry equal to product in O(log n), reporting whether it existed.
+// The product must carry the same sort key values it was inserted with.
+func (l *skipList) Delete(product catalog.Product) bool {
+	var update [maxSkipListLevel]*skipNode
+
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && l.less(node.next[i].product, product) {
+			node = node.next[i]
+		}
+		update[i] = node
+	}
+
+	target := node.next[0]
+	if target == nil || target.product.ID != product.ID {
+		return false
+	}
+
+	for i := 0; i < l.level; i++ {
+		if update[i].next[i] == target {
+			update[i].span[i] += target.span[i] - 1
+			update[i].next[i] = target.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+
+	for l.level > 1 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+
+	l.length--
+	return true
+}
+
+// Range returns up to limit products starting at the given zero-based rank
+// in O(log n + limit)
+func (l *skipList) Range(offset, limit int) catalog.ProductCollection {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= l.length || limit <= 0 {
+		return catalog.ProductCollection{}
+	}
+	if remaining := l.length - offset; limit > remaining {
+		limit = remaining
+	}
+
+	// Walk the spans down to the entry just before the requested rank
+	node := l.head
+	traversed := 0
+	for i := l.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && traversed+node.span[i] <= offset {
+			traversed += node.span[i]
+			node = node.next[i]
+		}
+	}
+
+	page := make(catalog.ProductCollection, 0, limit)
+	for node = node.next[0]; node != nil && len(page) < limit; node = node.next[0] {
+		page = append(page, node.product)
+	}
+	return page
+}
+
+// After returns up to limit products ordered strictly after key in
+// O(log n + limit), reporting whether further products follow
+func (l *skipList) After(key catalog.Product, limit int) (catalog.ProductCollection, bool) {
+	node := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && !l.less(key, node.next[i].product) {
+			node = node.next[i]
+		}
+	}
+
+	page := catalog.ProductCollection{}
+	for node = node.next[0]; node != nil && len(page) < limit; node = node.next[0] {
+		page = append(page, node.product)
+	}
+	return page, node != nil
+}
+
+// First returns up to limit products from the start of the list
+func (l *skipList) First(limit int) (catalog.ProductCollection, bool) {
+	page := l.Range(0, limit)
+	return page, len(page) < l.length
+}
@@ -0,0 +1,114 @@
+package sorting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// AttributeSorter sorts products by a custom attribute
+type AttributeSorter struct {
+	spec catalog.AttributeSortSpec
+}
+
+// NewAttributeSorter creates a new sorter for the attribute sort spec
+func NewAttributeSorter(spec catalog.AttributeSortSpec) catalog.Sorter {
+	return &AttributeSorter{spec: spec}
+}
+
+// Sort implements the Sorter interface
+func (s *AttributeSorter) Sort(ctx context.Context, products catalog.ProductCollection) (catalog.ProductCollection, error) {
+	if len(products) == 0 {
+		return catalog.ProductCollection{}, nil
+	}
+
+	for _, product := range products {
+		if err := s.CheckProduct(product); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a copy to avoid mutating the original
+	sorted := products.Copy()
+
+	// Sort by attribute value
+	sort.Slice(sorted, func(i, j int) bool {
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
+
+// CheckProduct rejects products without the attribute when it is required
+func (s *AttributeSorter) CheckProduct(product catalog.Product) error {
+	if s.spec.Missing != catalog.MissingError {
+		return nil
+	}
+	if _, ok := product.Attributes.Get(s.spec.Name); !ok {
+		return fmt.Errorf("%w: product %d has no %s", catalog.ErrMissingAttribute, product.ID, s.spec.Name)
+	}
+	return nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *AttributeSorter) Less(a, b catalog.Product) bool {
+	valueA, hasA := a.Attributes.Get(s.spec.Name)
+	valueB, hasB := b.Attributes.Get(s.spec.Name)
+
+	// Products without the attribute go first or last regardless of direction
+	if hasA != hasB {
+		if s.spec.Missing == catalog.MissingFirst {
+			return hasB
+		}
+		return hasA
+	}
+
+	// Primary sort: attribute value
+	if hasA {
+		if cmp := valueA.Compare(valueB); cmp != 0 {
+			if s.spec.Ascending {
+				return cmp < 0
+			}
+			return cmp > 0
+		}
+	}
+
+	// Tie-breaker: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// Criteria reports the values Less compares, in order
+func (s *AttributeSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	value, ok := product.Attributes.Get(s.spec.Name)
+	if !ok {
+		return []catalog.RankingCriterion{
+			{Name: "has_" + s.spec.Name, Value: "false", Descending: s.spec.Missing != catalog.MissingFirst},
+			idCriterion(product),
+		}
+	}
+
+	// Encoding a valid attribute value never fails
+	encoded, _ := json.Marshal(value)
+	return []catalog.RankingCriterion{
+		{Name: "has_" + s.spec.Name, Value: "true", Descending: s.spec.Missing != catalog.MissingFirst},
+		{Name: s.spec.Name, Value: string(encoded), Descending: !s.spec.Ascending},
+		idCriterion(product),
+	}
+}
+
+// GetStrategy returns the sort strategy
+func (s *AttributeSorter) GetStrategy() catalog.SortStrategy {
+	return catalog.AttributeSortStrategy(s.spec.Name, s.spec.Ascending, s.spec.Missing)
+}
+
+// GetDescription returns a human-readable description
+func (s *AttributeSorter) GetDescription() string {
+	direction := "highest to lowest"
+	if s.spec.Ascending {
+		direction = "lowest to highest"
+	}
+	return fmt.Sprintf("Sorts products by the %s attribute from %s", s.spec.Name, direction)
+}
@@ -3,6 +3,7 @@ package sorting
 import (
 	"context"
 	"sort"
+	"time"
 
 	"product-catalog-sorting/internal/domain/catalog"
 )
@@ -30,22 +31,35 @@ func (s *CreatedAtSorter) Sort(ctx context.Context, products catalog.ProductColl
 
 	// Sort by creation date with consistent tie-breaking
 	sort.Slice(sorted, func(i, j int) bool {
-		timeI := sorted[i].CreatedAt
-		timeJ := sorted[j].CreatedAt
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *CreatedAtSorter) Less(a, b catalog.Product) bool {
+	timeA := a.CreatedAt
+	timeB := b.CreatedAt
 
-		// Primary sort: creation date
-		if !timeI.Equal(timeJ) {
-			if s.ascending {
-				return timeI.Before(timeJ)
-			}
-			return timeI.After(timeJ)
+	// Primary sort: creation date
+	if !timeA.Equal(timeB) {
+		if s.ascending {
+			return timeA.Before(timeB)
 		}
+		return timeA.After(timeB)
+	}
 
-		// Tie-breaker: ID for consistent ordering
-		return sorted[i].ID < sorted[j].ID
-	})
+	// Tie-breaker: ID for consistent ordering
+	return a.ID < b.ID
+}
 
-	return sorted, nil
+// Criteria reports the values Less compares, in order
+func (s *CreatedAtSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		{Name: "created_at", Value: product.CreatedAt.UTC().Format(time.RFC3339Nano), Descending: !s.ascending},
+		idCriterion(product),
+	}
 }
 
 // GetStrategy returns the sort strategy
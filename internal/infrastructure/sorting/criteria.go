@@ -0,0 +1,33 @@
+package sorting
+
+import (
+	"strconv"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// intCriterion reports an integer sort key
+func intCriterion(name string, value int, descending bool) catalog.RankingCriterion {
+	return catalog.RankingCriterion{Name: name, Value: strconv.Itoa(value), Descending: descending}
+}
+
+// floatCriterion reports a floating-point sort key exactly, so products
+// differing in the key never report the same value
+func floatCriterion(name string, value float64, descending bool) catalog.RankingCriterion {
+	return catalog.RankingCriterion{Name: name, Value: strconv.FormatFloat(value, 'g', -1, 64), Descending: descending}
+}
+
+// moneyCriterion reports a monetary sort key
+func moneyCriterion(name string, value catalog.Money, descending bool) catalog.RankingCriterion {
+	return catalog.RankingCriterion{Name: name, Value: value.String(), Descending: descending}
+}
+
+// boolCriterion reports a sort key that ranks true values first
+func boolCriterion(name string, value bool) catalog.RankingCriterion {
+	return catalog.RankingCriterion{Name: name, Value: strconv.FormatBool(value), Descending: true}
+}
+
+// idCriterion reports the ID tie-breaker every sorter ends with
+func idCriterion(product catalog.Product) catalog.RankingCriterion {
+	return catalog.RankingCriterion{Name: "id", Value: strconv.FormatInt(int64(product.ID), 10)}
+}
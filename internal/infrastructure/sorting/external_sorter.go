@@ -0,0 +1,422 @@
+package sorting
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+	"unsafe"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+const (
+	// DefaultExternalSortMemoryBudget is used when no budget is configured
+	DefaultExternalSortMemoryBudget int64 = 64 << 20
+
+	// runReadBufferSize is the initial read buffer held for every run during a merge
+	runReadBufferSize = 32 << 10
+
+	// maxRecordSize bounds a single JSONL product record
+	maxRecordSize = 1 << 20
+
+	// cancellationCheckInterval controls how often long loops poll the context
+	cancellationCheckInterval = 1024
+)
+
+// ExternalSortConfig configures an out-of-core sort
+type ExternalSortConfig struct {
+	// MemoryBudget caps the approximate number of bytes held in memory
+	MemoryBudget int64
+
+	// TempDir is where sorted runs are spilled (defaults to the OS temp dir)
+	TempDir string
+}
+
+// ExternalSortStats describes the work performed by an external sort
+type ExternalSortStats struct {
+	ProductCount int `json:"product_count"`
+	RunCount     int `json:"run_count"`
+	MergePasses  int `json:"merge_passes"`
+}
+
+// ExternalSorter sorts JSONL product streams that may not fit in memory.
+// Bounded runs are sorted with a regular Sorter, spilled to temporary files
+// and k-way merged using the sorter's pairwise comparison.
+type ExternalSorter struct {
+	sorter     catalog.Sorter
+	comparator catalog.Comparator
+	config     ExternalSortConfig
+}
+
+// NewExternalSorter creates an external sorter on top of an in-memory sorter
+func NewExternalSorter(sorter catalog.Sorter, config ExternalSortConfig) (*ExternalSorter, error) {
+	if sorter == nil {
+		return nil, fmt.Errorf("sorter cannot be nil")
+	}
+
+	comparator, ok := sorter.(catalog.Comparator)
+	if !ok {
+		return nil, fmt.Errorf("sorter for strategy %s does not support merging", sorter.GetStrategy())
+	}
+
+	if config.MemoryBudget < 0 {
+		return nil, fmt.Errorf("memory budget cannot be negative")
+	}
+	if config.MemoryBudget == 0 {
+		config.MemoryBudget = DefaultExternalSortMemoryBudget
+	}
+
+	return &ExternalSorter{
+		sorter:     sorter,
+		comparator: comparator,
+		config:     config,
+	}, nil
+}
+
+// Sort reads JSONL products from r and writes them to w in sorted order
+func (s *ExternalSorter) Sort(ctx context.Context, r io.Reader, w io.Writer) (*ExternalSortStats, error) {
+	tempDir, err := os.MkdirTemp(s.config.TempDir, "catalog-extsort-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	stats := &ExternalSortStats{}
+
+	runs, buffered, err := s.createRuns(ctx, r, tempDir, stats)
+	if err != nil {
+		return nil, err
+	}
+
+	output := bufio.NewWriter(w)
+
+	// Everything fit within the budget, so no spilling was required
+	if len(runs) == 0 {
+		sorted, err := s.sorter.Sort(ctx, buffered)
+		if err != nil {
+			return nil, fmt.Errorf("sorting failed: %w", err)
+		}
+		if err := writeProducts(output, sorted); err != nil {
+			return nil, err
+		}
+		return stats, output.Flush()
+	}
+
+	// Each open run holds a read buffer that can grow to the largest record,
+	// so the fan-in is bounded by the budget at that size
+	fanIn := int(s.config.MemoryBudget / maxRecordSize)
+	if fanIn < 2 {
+		fanIn = 2
+	}
+
+	for len(runs) > fanIn {
+		stats.MergePasses++
+		var next []string
+		for start := 0; start < len(runs); start += fanIn {
+			end := start + fanIn
+			if end > len(runs) {
+				end = len(runs)
+			}
+			merged, err := s.mergeToRun(ctx, runs[start:end], tempDir, len(next), stats.MergePasses)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, merged)
+		}
+		runs = next
+	}
+
+	stats.MergePasses++
+	if err := s.merge(ctx, runs, output); err != nil {
+		return nil, err
+	}
+
+	return stats, output.Flush()
+}
+
+// createRuns splits the input into sorted runs that fit the memory budget.
+// When the whole input fits, nothing is spilled and the products are returned.
+func (s *ExternalSorter) createRuns(ctx context.Context, r io.Reader, tempDir string, stats *ExternalSortStats) ([]string, catalog.ProductCollection, error) {
+	// Sorting copies the run, so only half the budget is available for buffering
+	runBudget := s.config.MemoryBudget / 2
+
+	var (
+		runs     []string
+		buffer   catalog.ProductCollection
+		buffered int64
+	)
+
+	err := decodeProducts(ctx, r, func(product catalog.Product) error {
+		buffer = append(buffer, product)
+		buffered += estimateProductSize(product)
+		stats.ProductCount++
+
+		if buffered < runBudget {
+			return nil
+		}
+
+		run, err := s.spillRun(ctx, buffer, tempDir, len(runs))
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		stats.RunCount++
+		buffer = nil
+		buffered = 0
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(runs) > 0 && len(buffer) > 0 {
+		run, err := s.spillRun(ctx, buffer, tempDir, len(runs))
+		if err != nil {
+			return nil, nil, err
+		}
+		runs = append(runs, run)
+		stats.RunCount++
+		buffer = nil
+	}
+
+	if buffer == nil {
+		buffer = catalog.ProductCollection{}
+	}
+
+	return runs, buffer, nil
+}
+
+// spillRun sorts a buffered run and writes it to a temporary file
+func (s *ExternalSorter) spillRun(ctx context.Context, products catalog.ProductCollection, tempDir string, index int) (string, error) {
+	sorted, err := s.sorter.Sort(ctx, products)
+	if err != nil {
+		return "", fmt.Errorf("sorting run %d failed: %w", index, err)
+	}
+
+	path := filepath.Join(tempDir, fmt.Sprintf("run-0-%06d.jsonl", index))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create run file: %w", err)
+	}
+	defer file.Close()
+
+	output := bufio.NewWriter(file)
+	if err := writeProducts(output, sorted); err != nil {
+		return "", err
+	}
+	if err := output.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write run file: %w", err)
+	}
+
+	return path, file.Close()
+}
+
+// mergeToRun merges a group of runs into a new run file for the next pass
+func (s *ExternalSorter) mergeToRun(ctx context.Context, runs []string, tempDir string, index, pass int) (string, error) {
+	path := filepath.Join(tempDir, fmt.Sprintf("run-%d-%06d.jsonl", pass, index))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create run file: %w", err)
+	}
+	defer file.Close()
+
+	output := bufio.NewWriter(file)
+	if err := s.merge(ctx, runs, output); err != nil {
+		return "", err
+	}
+	if err := output.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write run file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close run file: %w", err)
+	}
+
+	// Merged inputs are no longer needed, free the disk space early
+	for _, run := range runs {
+		os.Remove(run)
+	}
+
+	return path, nil
+}
+
+// merge performs a k-way merge of sorted run files into w
+func (s *ExternalSorter) merge(ctx context.Context, runs []string, w io.Writer) error {
+	queue := &runQueue{less: s.comparator.Less}
+
+	defer func() {
+		for _, reader := range queue.readers {
+			reader.file.Close()
+		}
+	}()
+
+	for _, path := range runs {
+		reader, err := openRunReader(path)
+		if err != nil {
+			return err
+		}
+		ok, err := reader.next()
+		if err != nil {
+			reader.file.Close()
+			return err
+		}
+		if !ok {
+			reader.file.Close()
+			continue
+		}
+		queue.readers = append(queue.readers, reader)
+	}
+	heap.Init(queue)
+
+	encoder := json.NewEncoder(w)
+	for written := 0; queue.Len() > 0; written++ {
+		if written%cancellationCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		reader := queue.readers[0]
+		if err := encoder.Encode(reader.current); err != nil {
+			return fmt.Errorf("failed to write product: %w", err)
+		}
+
+		ok, err := reader.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(queue, 0)
+		} else {
+			reader.file.Close()
+			heap.Pop(queue)
+		}
+	}
+
+	return nil
+}
+
+// runReader streams products back from a sorted run file
+type runReader struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	current catalog.Product
+}
+
+// openRunReader opens a run file for merging
+func openRunReader(path string) (*runReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, runReadBufferSize), maxRecordSize)
+
+	return &runReader{file: file, scanner: scanner}, nil
+}
+
+// next advances to the following product, reporting false at end of run
+func (r *runReader) next() (bool, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return false, fmt.Errorf("failed to read run file: %w", err)
+		}
+		return false, nil
+	}
+
+	r.current = catalog.Product{}
+	if err := json.Unmarshal(r.scanner.Bytes(), &r.current); err != nil {
+		return false, fmt.Errorf("corrupt run file %s: %w", r.file.Name(), err)
+	}
+	return true, nil
+}
+
+// runQueue is a min-heap of run readers ordered by their current product
+type runQueue struct {
+	readers []*runReader
+	less    func(a, b catalog.Product) bool
+}
+
+func (q *runQueue) Len() int { return len(q.readers) }
+
+func (q *runQueue) Less(i, j int) bool {
+	return q.less(q.readers[i].current, q.readers[j].current)
+}
+
+func (q *runQueue) Swap(i, j int) { q.readers[i], q.readers[j] = q.readers[j], q.readers[i] }
+
+func (q *runQueue) Push(x interface{}) { q.readers = append(q.readers, x.(*runReader)) }
+
+func (q *runQueue) Pop() interface{} {
+	last := q.readers[len(q.readers)-1]
+	q.readers = q.readers[:len(q.readers)-1]
+	return last
+}
+
+// decodeProducts reads JSONL products, validating each one before handing it on
+func decodeProducts(ctx context.Context, r io.Reader, fn func(catalog.Product) error) error {
+	products := catalog.NewJSONProductIterator(r)
+
+	for count := 0; products.Next(); count++ {
+		if count%cancellationCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		product := products.Product()
+		if err := product.Validate(); err != nil {
+			return fmt.Errorf("invalid product on line %d: %w", products.Line(), err)
+		}
+
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+
+	return products.Err()
+}
+
+// writeProducts encodes products as JSONL
+func writeProducts(w io.Writer, products catalog.ProductCollection) error {
+	encoder := json.NewEncoder(w)
+	for _, product := range products {
+		if err := encoder.Encode(product); err != nil {
+			return fmt.Errorf("failed to write product: %w", err)
+		}
+	}
+	return nil
+}
+
+// estimateProductSize approximates the in-memory footprint of a product,
+// including the strings, maps and optional fields it references
+func estimateProductSize(product catalog.Product) int64 {
+	size := int64(unsafe.Sizeof(product)) +
+		int64(len(product.Name)+len(product.Category))
+
+	for tag, name := range product.LocalizedNames {
+		size += 2*int64(unsafe.Sizeof(name)) + int64(len(tag)+len(name))
+	}
+	for name, value := range product.Attributes {
+		size += int64(unsafe.Sizeof(name)+unsafe.Sizeof(value)) + int64(len(name)+len(value.String))
+	}
+
+	for _, money := range []*catalog.Money{product.CostPrice, product.ShippingCost} {
+		if money != nil {
+			size += int64(unsafe.Sizeof(*money))
+		}
+	}
+	for _, at := range []*time.Time{product.ActivatedAt, product.DiscontinuedAt, product.ArchivedAt} {
+		if at != nil {
+			size += int64(unsafe.Sizeof(*at))
+		}
+	}
+
+	return size
+}
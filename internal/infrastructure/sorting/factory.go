@@ -7,11 +7,27 @@ import (
 )
 
 // DefaultSorterFactory implements the SorterFactory interface
-type DefaultSorterFactory struct{}
+type DefaultSorterFactory struct {
+	ratingPrior catalog.RatingPrior
+}
+
+// FactoryOption configures optional sorter parameters
+type FactoryOption func(*DefaultSorterFactory)
+
+// WithRatingPrior sets the prior used by the top rated strategy
+func WithRatingPrior(prior catalog.RatingPrior) FactoryOption {
+	return func(f *DefaultSorterFactory) {
+		f.ratingPrior = prior
+	}
+}
 
 // NewSorterFactory creates a new default sorter factory
-func NewSorterFactory() catalog.SorterFactory {
-	return &DefaultSorterFactory{}
+func NewSorterFactory(options ...FactoryOption) catalog.SorterFactory {
+	factory := &DefaultSorterFactory{ratingPrior: catalog.DefaultRatingPrior}
+	for _, option := range options {
+		option(factory)
+	}
+	return factory
 }
 
 // CreateSorter creates a sorter for the given strategy
@@ -33,7 +49,21 @@ func (f *DefaultSorterFactory) CreateSorter(strategy catalog.SortStrategy) (cata
 		return NewRevenueSorter(), nil
 	case catalog.SortByName:
 		return NewNameSorter(), nil
+	case catalog.SortByMargin:
+		return NewMarginSorter(), nil
+	case catalog.SortByProfit:
+		return NewProfitSorter(), nil
+	case catalog.SortByProfitPerView:
+		return NewProfitPerViewSorter(), nil
+	case catalog.SortByRating:
+		if err := f.ratingPrior.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rating prior: %w", err)
+		}
+		return NewRatingSorter(f.ratingPrior), nil
 	default:
+		if spec, ok := strategy.AttributeSpec(); ok {
+			return NewAttributeSorter(spec), nil
+		}
 		return nil, fmt.Errorf("unsupported sort strategy: %s", strategy)
 	}
 }
@@ -0,0 +1,76 @@
+package sorting
+
+import (
+	"context"
+	"sort"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// MarginSorter sorts products by gross margin percentage
+type MarginSorter struct{}
+
+// NewMarginSorter creates a new margin sorter
+func NewMarginSorter() catalog.Sorter {
+	return &MarginSorter{}
+}
+
+// Sort implements the Sorter interface
+func (s *MarginSorter) Sort(ctx context.Context, products catalog.ProductCollection) (catalog.ProductCollection, error) {
+	if len(products) == 0 {
+		return catalog.ProductCollection{}, nil
+	}
+
+	// Create a copy to avoid mutating the original
+	sorted := products.Copy()
+
+	// Sort by gross margin (descending)
+	sort.Slice(sorted, func(i, j int) bool {
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *MarginSorter) Less(a, b catalog.Product) bool {
+	// Products without cost data have no meaningful margin
+	if a.HasCost() != b.HasCost() {
+		return a.HasCost()
+	}
+
+	// Primary sort: gross margin (higher is better)
+	marginA := a.GrossMargin()
+	marginB := b.GrossMargin()
+	if marginA != marginB {
+		return marginA > marginB
+	}
+
+	// Secondary sort: sales count (higher is better)
+	if a.SalesCount != b.SalesCount {
+		return a.SalesCount > b.SalesCount
+	}
+
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// Criteria reports the values Less compares, in order
+func (s *MarginSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		boolCriterion("has_cost", product.HasCost()),
+		floatCriterion("gross_margin", product.GrossMargin(), true),
+		intCriterion("sales_count", product.SalesCount, true),
+		idCriterion(product),
+	}
+}
+
+// GetStrategy returns the sort strategy
+func (s *MarginSorter) GetStrategy() catalog.SortStrategy {
+	return catalog.SortByMargin
+}
+
+// GetDescription returns a human-readable description
+func (s *MarginSorter) GetDescription() string {
+	return "Sorts products by gross margin percentage from highest to lowest"
+}
@@ -3,17 +3,46 @@ package sorting
 import (
 	"context"
 	"sort"
-	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 
 	"product-catalog-sorting/internal/domain/catalog"
 )
 
-// NameSorter sorts products alphabetically by name
-type NameSorter struct{}
+// NameSorter sorts products alphabetically by name using Unicode collation,
+// so accented and locale-specific letters are ordered as readers expect
+type NameSorter struct {
+	collation catalog.Collation
+
+	// mu guards collator, which keeps internal buffers between comparisons
+	mu       sync.Mutex
+	collator *collate.Collator
+}
 
-// NewNameSorter creates a new name sorter
+// NewNameSorter creates a new name sorter using the root collation
 func NewNameSorter() catalog.Sorter {
-	return &NameSorter{}
+	return newNameSorter(catalog.Collation{})
+}
+
+// newNameSorter creates a name sorter for the collation. Locales are
+// validated before sorting, so unknown tags fall back to the root collation.
+func newNameSorter(collation catalog.Collation) *NameSorter {
+	tag := language.Und
+	if collation.Locale != "" {
+		tag = language.Make(collation.Locale)
+	}
+
+	options := []collate.Option{collate.IgnoreCase}
+	if collation.Numeric {
+		options = append(options, collate.Numeric)
+	}
+
+	return &NameSorter{
+		collation: collation,
+		collator:  collate.New(tag, options...),
+	}
 }
 
 // Sort implements the Sorter interface
@@ -27,19 +56,43 @@ func (s *NameSorter) Sort(ctx context.Context, products catalog.ProductCollectio
 
 	// Sort alphabetically (case-insensitive)
 	sort.Slice(sorted, func(i, j int) bool {
-		nameI := strings.ToLower(sorted[i].Name)
-		nameJ := strings.ToLower(sorted[j].Name)
+		return s.Less(sorted[i], sorted[j])
+	})
 
-		// Primary sort: name (alphabetical)
-		if nameI != nameJ {
-			return nameI < nameJ
+	return sorted, nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *NameSorter) Less(a, b catalog.Product) bool {
+	nameA := a.NameIn(s.collation.Locale)
+	nameB := b.NameIn(s.collation.Locale)
+
+	// Primary sort: name (collation order)
+	if nameA != nameB {
+		s.mu.Lock()
+		cmp := s.collator.CompareString(nameA, nameB)
+		s.mu.Unlock()
+		if cmp != 0 {
+			return cmp < 0
 		}
+	}
 
-		// Tie-breaker: ID for consistent ordering
-		return sorted[i].ID < sorted[j].ID
-	})
+	// Tie-breaker: ID for consistent ordering
+	return a.ID < b.ID
+}
 
-	return sorted, nil
+// WithCollation returns a name sorter comparing localized names with the
+// collation's locale rules
+func (s *NameSorter) WithCollation(collation catalog.Collation) catalog.Sorter {
+	return newNameSorter(collation)
+}
+
+// Criteria reports the values Less compares, in order
+func (s *NameSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		{Name: "name", Value: product.NameIn(s.collation.Locale)},
+		idCriterion(product),
+	}
 }
 
 // GetStrategy returns the sort strategy
@@ -49,5 +102,5 @@ func (s *NameSorter) GetStrategy() catalog.SortStrategy {
 
 // GetDescription returns a human-readable description
 func (s *NameSorter) GetDescription() string {
-	return "Sorts products alphabetically by name (case-insensitive)"
+	return "Sorts products alphabetically by name (case-insensitive, Unicode collation)"
 }
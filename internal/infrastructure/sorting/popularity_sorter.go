@@ -26,23 +26,37 @@ func (s *PopularitySorter) Sort(ctx context.Context, products catalog.ProductCol
 
 	// Sort by popularity (views) with tie-breaking logic
 	sort.Slice(sorted, func(i, j int) bool {
-		// Primary sort: view count (higher is better)
-		if sorted[i].ViewsCount != sorted[j].ViewsCount {
-			return sorted[i].ViewsCount > sorted[j].ViewsCount
-		}
-
-		// Secondary sort: sales count (higher is better)
-		if sorted[i].SalesCount != sorted[j].SalesCount {
-			return sorted[i].SalesCount > sorted[j].SalesCount
-		}
-
-		// Tertiary sort: ID for consistent ordering
-		return sorted[i].ID < sorted[j].ID
+		return s.Less(sorted[i], sorted[j])
 	})
 
 	return sorted, nil
 }
 
+// Less reports whether product a should be ordered before product b
+func (s *PopularitySorter) Less(a, b catalog.Product) bool {
+	// Primary sort: view count (higher is better)
+	if a.ViewsCount != b.ViewsCount {
+		return a.ViewsCount > b.ViewsCount
+	}
+
+	// Secondary sort: sales count (higher is better)
+	if a.SalesCount != b.SalesCount {
+		return a.SalesCount > b.SalesCount
+	}
+
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// Criteria reports the values Less compares, in order
+func (s *PopularitySorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		intCriterion("views_count", product.ViewsCount, true),
+		intCriterion("sales_count", product.SalesCount, true),
+		idCriterion(product),
+	}
+}
+
 // GetStrategy returns the sort strategy
 func (s *PopularitySorter) GetStrategy() catalog.SortStrategy {
 	return catalog.SortByPopularity
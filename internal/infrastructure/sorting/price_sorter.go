@@ -9,7 +9,9 @@ import (
 
 // PriceSorter sorts products by price
 type PriceSorter struct {
-	ascending bool
+	ascending  bool
+	conversion *catalog.CurrencyConversion
+	prices     catalog.PriceResolver
 }
 
 // NewPriceSorter creates a new price sorter
@@ -30,15 +32,66 @@ func (s *PriceSorter) Sort(ctx context.Context, products catalog.ProductCollecti
 
 	// Sort using Go's built-in sort package
 	sort.Slice(sorted, func(i, j int) bool {
-		if s.ascending {
-			return sorted[i].Price < sorted[j].Price
-		}
-		return sorted[i].Price > sorted[j].Price
+		return s.Less(sorted[i], sorted[j])
 	})
 
 	return sorted, nil
 }
 
+// Less reports whether product a should be ordered before product b
+func (s *PriceSorter) Less(a, b catalog.Product) bool {
+	// Primary sort: price
+	if cmp := s.price(a).Cmp(s.price(b)); cmp != 0 {
+		if s.ascending {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+
+	// Tie-breaker: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// WithCurrency returns a price sorter that compares prices converted into
+// the conversion's target currency
+func (s *PriceSorter) WithCurrency(conversion *catalog.CurrencyConversion) catalog.Sorter {
+	return &PriceSorter{
+		ascending:  s.ascending,
+		conversion: conversion,
+		prices:     s.prices,
+	}
+}
+
+// WithPriceResolver returns a price sorter that compares effective prices
+// instead of list prices
+func (s *PriceSorter) WithPriceResolver(resolver catalog.PriceResolver) catalog.Sorter {
+	return &PriceSorter{
+		ascending:  s.ascending,
+		conversion: s.conversion,
+		prices:     resolver,
+	}
+}
+
+// price returns the product's effective price in the sorter's comparison currency
+func (s *PriceSorter) price(product catalog.Product) catalog.Money {
+	price := product.Price
+	if s.prices != nil {
+		price = s.prices.EffectivePrice(product)
+	}
+	if s.conversion == nil {
+		return price
+	}
+	return s.conversion.ConvertOrKeep(price)
+}
+
+// Criteria reports the values Less compares, in order
+func (s *PriceSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		moneyCriterion("price", s.price(product), !s.ascending),
+		idCriterion(product),
+	}
+}
+
 // GetStrategy returns the sort strategy
 func (s *PriceSorter) GetStrategy() catalog.SortStrategy {
 	if s.ascending {
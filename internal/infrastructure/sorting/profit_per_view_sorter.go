@@ -0,0 +1,93 @@
+package sorting
+
+import (
+	"context"
+	"sort"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// ProfitPerViewSorter sorts products by total profit earned per product view
+type ProfitPerViewSorter struct {
+	conversion *catalog.CurrencyConversion
+}
+
+// NewProfitPerViewSorter creates a new profit per view sorter
+func NewProfitPerViewSorter() catalog.Sorter {
+	return &ProfitPerViewSorter{}
+}
+
+// Sort implements the Sorter interface
+func (s *ProfitPerViewSorter) Sort(ctx context.Context, products catalog.ProductCollection) (catalog.ProductCollection, error) {
+	if len(products) == 0 {
+		return catalog.ProductCollection{}, nil
+	}
+
+	// Create a copy to avoid mutating the original
+	sorted := products.Copy()
+
+	// Sort by profit per view (descending)
+	sort.Slice(sorted, func(i, j int) bool {
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *ProfitPerViewSorter) Less(a, b catalog.Product) bool {
+	// Products without cost data have no meaningful profit
+	if a.HasCost() != b.HasCost() {
+		return a.HasCost()
+	}
+
+	// Primary sort: profit per view (higher is better)
+	perViewA := s.profitPerView(a)
+	perViewB := s.profitPerView(b)
+	if perViewA != perViewB {
+		return perViewA > perViewB
+	}
+
+	// Secondary sort: views count (higher is better)
+	if a.ViewsCount != b.ViewsCount {
+		return a.ViewsCount > b.ViewsCount
+	}
+
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// WithCurrency returns a sorter that compares profit per view converted into
+// the conversion's target currency
+func (s *ProfitPerViewSorter) WithCurrency(conversion *catalog.CurrencyConversion) catalog.Sorter {
+	return &ProfitPerViewSorter{conversion: conversion}
+}
+
+// profitPerView returns profit per view in the sorter's comparison currency
+func (s *ProfitPerViewSorter) profitPerView(product catalog.Product) float64 {
+	if s.conversion == nil || product.ViewsCount == 0 {
+		return product.ProfitPerView()
+	}
+	profit := s.conversion.ConvertOrKeep(product.TotalProfit())
+	return profit.ToFloat64() / float64(product.ViewsCount)
+}
+
+// Criteria reports the values Less compares, in order
+func (s *ProfitPerViewSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		boolCriterion("has_cost", product.HasCost()),
+		floatCriterion("profit_per_view", s.profitPerView(product), true),
+		intCriterion("views_count", product.ViewsCount, true),
+		idCriterion(product),
+	}
+}
+
+// GetStrategy returns the sort strategy
+func (s *ProfitPerViewSorter) GetStrategy() catalog.SortStrategy {
+	return catalog.SortByProfitPerView
+}
+
+// GetDescription returns a human-readable description
+func (s *ProfitPerViewSorter) GetDescription() string {
+	return "Sorts products by total profit per product view from highest to lowest"
+}
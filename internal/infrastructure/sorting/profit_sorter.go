@@ -0,0 +1,90 @@
+package sorting
+
+import (
+	"context"
+	"sort"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// ProfitSorter sorts products by total profit generated
+type ProfitSorter struct {
+	conversion *catalog.CurrencyConversion
+}
+
+// NewProfitSorter creates a new profit sorter
+func NewProfitSorter() catalog.Sorter {
+	return &ProfitSorter{}
+}
+
+// Sort implements the Sorter interface
+func (s *ProfitSorter) Sort(ctx context.Context, products catalog.ProductCollection) (catalog.ProductCollection, error) {
+	if len(products) == 0 {
+		return catalog.ProductCollection{}, nil
+	}
+
+	// Create a copy to avoid mutating the original
+	sorted := products.Copy()
+
+	// Sort by total profit (descending)
+	sort.Slice(sorted, func(i, j int) bool {
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *ProfitSorter) Less(a, b catalog.Product) bool {
+	// Products without cost data have no meaningful profit
+	if a.HasCost() != b.HasCost() {
+		return a.HasCost()
+	}
+
+	// Primary sort: total profit (higher is better)
+	if cmp := s.profit(a).Cmp(s.profit(b)); cmp != 0 {
+		return cmp > 0
+	}
+
+	// Secondary sort: sales count (higher is better)
+	if a.SalesCount != b.SalesCount {
+		return a.SalesCount > b.SalesCount
+	}
+
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// WithCurrency returns a profit sorter that compares profit converted into
+// the conversion's target currency
+func (s *ProfitSorter) WithCurrency(conversion *catalog.CurrencyConversion) catalog.Sorter {
+	return &ProfitSorter{conversion: conversion}
+}
+
+// profit returns the product's total profit in the sorter's comparison currency
+func (s *ProfitSorter) profit(product catalog.Product) catalog.Money {
+	if s.conversion == nil {
+		return product.TotalProfit()
+	}
+	return s.conversion.ConvertOrKeep(product.TotalProfit())
+}
+
+// Criteria reports the values Less compares, in order
+func (s *ProfitSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		boolCriterion("has_cost", product.HasCost()),
+		moneyCriterion("total_profit", s.profit(product), true),
+		intCriterion("sales_count", product.SalesCount, true),
+		idCriterion(product),
+	}
+}
+
+// GetStrategy returns the sort strategy
+func (s *ProfitSorter) GetStrategy() catalog.SortStrategy {
+	return catalog.SortByProfit
+}
+
+// GetDescription returns a human-readable description
+func (s *ProfitSorter) GetDescription() string {
+	return "Sorts products by total profit (unit profit × sales) from highest to lowest"
+}
@@ -0,0 +1,72 @@
+package sorting
+
+import (
+	"context"
+	"sort"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// RatingSorter sorts products by Bayesian-average star rating
+type RatingSorter struct {
+	prior catalog.RatingPrior
+}
+
+// NewRatingSorter creates a new rating sorter that shrinks ratings toward the prior
+func NewRatingSorter(prior catalog.RatingPrior) catalog.Sorter {
+	return &RatingSorter{prior: prior}
+}
+
+// Sort implements the Sorter interface
+func (s *RatingSorter) Sort(ctx context.Context, products catalog.ProductCollection) (catalog.ProductCollection, error) {
+	if len(products) == 0 {
+		return catalog.ProductCollection{}, nil
+	}
+
+	// Create a copy to avoid mutating the original
+	sorted := products.Copy()
+
+	// Sort by Bayesian rating (descending)
+	sort.Slice(sorted, func(i, j int) bool {
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
+
+// Less reports whether product a should be ordered before product b
+func (s *RatingSorter) Less(a, b catalog.Product) bool {
+	// Primary sort: Bayesian rating (higher is better)
+	ratingA := a.BayesianRating(s.prior)
+	ratingB := b.BayesianRating(s.prior)
+	if ratingA != ratingB {
+		return ratingA > ratingB
+	}
+
+	// Secondary sort: review count (more evidence is better)
+	if a.RatingCount != b.RatingCount {
+		return a.RatingCount > b.RatingCount
+	}
+
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// Criteria reports the values Less compares, in order
+func (s *RatingSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		floatCriterion("bayesian_rating", product.BayesianRating(s.prior), true),
+		intCriterion("rating_count", product.RatingCount, true),
+		idCriterion(product),
+	}
+}
+
+// GetStrategy returns the sort strategy
+func (s *RatingSorter) GetStrategy() catalog.SortStrategy {
+	return catalog.SortByRating
+}
+
+// GetDescription returns a human-readable description
+func (s *RatingSorter) GetDescription() string {
+	return "Sorts products by Bayesian-average star rating from highest to lowest"
+}
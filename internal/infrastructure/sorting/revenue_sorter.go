@@ -8,7 +8,10 @@ import (
 )
 
 // RevenueSorter sorts products by revenue generated
-type RevenueSorter struct{}
+type RevenueSorter struct {
+	conversion *catalog.CurrencyConversion
+	prices     catalog.PriceResolver
+}
 
 // NewRevenueSorter creates a new revenue sorter
 func NewRevenueSorter() catalog.Sorter {
@@ -26,24 +29,59 @@ func (s *RevenueSorter) Sort(ctx context.Context, products catalog.ProductCollec
 
 	// Sort by revenue generated (descending)
 	sort.Slice(sorted, func(i, j int) bool {
-		revenueI := sorted[i].RevenueGenerated()
-		revenueJ := sorted[j].RevenueGenerated()
+		return s.Less(sorted[i], sorted[j])
+	})
+
+	return sorted, nil
+}
 
-		// Primary sort: revenue (higher is better)
-		if revenueI != revenueJ {
-			return revenueI > revenueJ
-		}
+// Less reports whether product a should be ordered before product b
+func (s *RevenueSorter) Less(a, b catalog.Product) bool {
+	// Primary sort: revenue (higher is better)
+	if cmp := s.revenue(a).Cmp(s.revenue(b)); cmp != 0 {
+		return cmp > 0
+	}
 
-		// Secondary sort: sales count (higher is better)
-		if sorted[i].SalesCount != sorted[j].SalesCount {
-			return sorted[i].SalesCount > sorted[j].SalesCount
-		}
+	// Secondary sort: sales count (higher is better)
+	if a.SalesCount != b.SalesCount {
+		return a.SalesCount > b.SalesCount
+	}
 
-		// Tertiary sort: ID for consistent ordering
-		return sorted[i].ID < sorted[j].ID
-	})
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
 
-	return sorted, nil
+// WithCurrency returns a revenue sorter that compares revenue converted into
+// the conversion's target currency
+func (s *RevenueSorter) WithCurrency(conversion *catalog.CurrencyConversion) catalog.Sorter {
+	return &RevenueSorter{conversion: conversion, prices: s.prices}
+}
+
+// WithPriceResolver returns a revenue sorter that values sales at the
+// effective price instead of the list price
+func (s *RevenueSorter) WithPriceResolver(resolver catalog.PriceResolver) catalog.Sorter {
+	return &RevenueSorter{conversion: s.conversion, prices: resolver}
+}
+
+// revenue returns the product revenue in the sorter's comparison currency
+func (s *RevenueSorter) revenue(product catalog.Product) catalog.Money {
+	revenue := product.RevenueGenerated()
+	if s.prices != nil {
+		revenue = s.prices.EffectivePrice(product).Mul(int64(product.SalesCount))
+	}
+	if s.conversion == nil {
+		return revenue
+	}
+	return s.conversion.ConvertOrKeep(revenue)
+}
+
+// Criteria reports the values Less compares, in order
+func (s *RevenueSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		moneyCriterion("revenue", s.revenue(product), true),
+		intCriterion("sales_count", product.SalesCount, true),
+		idCriterion(product),
+	}
 }
 
 // GetStrategy returns the sort strategy
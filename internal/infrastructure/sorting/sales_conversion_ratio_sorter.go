@@ -26,24 +26,38 @@ func (s *SalesConversionRatioSorter) Sort(ctx context.Context, products catalog.
 
 	// Sort by conversion ratio (descending), then by sales count (descending)
 	sort.Slice(sorted, func(i, j int) bool {
-		ratioI := sorted[i].SalesConversionRatio()
-		ratioJ := sorted[j].SalesConversionRatio()
+		return s.Less(sorted[i], sorted[j])
+	})
 
-		// Primary sort: conversion ratio (higher is better)
-		if ratioI != ratioJ {
-			return ratioI > ratioJ
-		}
+	return sorted, nil
+}
 
-		// Secondary sort: sales count (higher is better) for tie-breaking
-		if sorted[i].SalesCount != sorted[j].SalesCount {
-			return sorted[i].SalesCount > sorted[j].SalesCount
-		}
+// Less reports whether product a should be ordered before product b
+func (s *SalesConversionRatioSorter) Less(a, b catalog.Product) bool {
+	ratioA := a.SalesConversionRatio()
+	ratioB := b.SalesConversionRatio()
 
-		// Tertiary sort: ID for consistent ordering
-		return sorted[i].ID < sorted[j].ID
-	})
+	// Primary sort: conversion ratio (higher is better)
+	if ratioA != ratioB {
+		return ratioA > ratioB
+	}
 
-	return sorted, nil
+	// Secondary sort: sales count (higher is better) for tie-breaking
+	if a.SalesCount != b.SalesCount {
+		return a.SalesCount > b.SalesCount
+	}
+
+	// Tertiary sort: ID for consistent ordering
+	return a.ID < b.ID
+}
+
+// Criteria reports the values Less compares, in order
+func (s *SalesConversionRatioSorter) Criteria(product catalog.Product) []catalog.RankingCriterion {
+	return []catalog.RankingCriterion{
+		floatCriterion("conversion_ratio", product.SalesConversionRatio(), true),
+		intCriterion("sales_count", product.SalesCount, true),
+		idCriterion(product),
+	}
 }
 
 // GetStrategy returns the sort strategy
@@ -37,7 +37,7 @@ func main() {
 		{
 			ID:         1,
 			Name:       "Alabaster Table",
-			Price:      12.99,
+			Price:      catalog.NewMoney(1299, catalog.USD),
 			CreatedAt:  parseDate("2019-01-04"),
 			SalesCount: 32,
 			ViewsCount: 730,
@@ -45,7 +45,7 @@ func main() {
 		{
 			ID:         2,
 			Name:       "Zebra Table",
-			Price:      44.49,
+			Price:      catalog.NewMoney(4449, catalog.USD),
 			CreatedAt:  parseDate("2012-01-04"),
 			SalesCount: 301,
 			ViewsCount: 3279,
@@ -53,7 +53,7 @@ func main() {
 		{
 			ID:         3,
 			Name:       "Coffee Table",
-			Price:      10.00,
+			Price:      catalog.NewMoney(1000, catalog.USD),
 			CreatedAt:  parseDate("2014-05-28"),
 			SalesCount: 1048,
 			ViewsCount: 20123,
@@ -155,8 +155,8 @@ func displayProducts(products []catalog.Product) {
 	for _, product := range products {
 		ratio := product.SalesConversionRatio()
 		revenue := product.RevenueGenerated()
-		fmt.Printf("  • %s - $%.2f (Sales: %d, Views: %d, Ratio: %.4f, Revenue: $%.2f)\n",
-			product.Name, float64(product.Price), product.SalesCount, product.ViewsCount, ratio, revenue)
+		fmt.Printf("  • %s - %s (Sales: %d, Views: %d, Ratio: %.4f, Revenue: %s)\n",
+			product.Name, product.Price, product.SalesCount, product.ViewsCount, ratio, revenue)
 	}
 }
 
@@ -166,7 +166,7 @@ func generateLargeDataset(size int) []catalog.Product {
 		products[i] = catalog.Product{
 			ID:         catalog.ProductID(i + 1),
 			Name:       fmt.Sprintf("Product %d", i+1),
-			Price:      catalog.Price(10 + float64(i%100)),
+			Price:      catalog.NewMoney(int64(10+i%100)*100, catalog.USD),
 			CreatedAt:  time.Now().AddDate(0, 0, -i%365),
 			SalesCount: i%1000 + 1,
 			ViewsCount: (i%5000 + 100),
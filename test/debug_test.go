@@ -31,8 +31,8 @@ func TestDebugSorting(t *testing.T) {
 		for _, p := range products {
 			ratio := p.SalesConversionRatio()
 			revenue := p.RevenueGenerated()
-			fmt.Printf("ID: %d, Name: %s, Price: $%.2f, Sales: %d, Views: %d, Ratio: %.4f, Revenue: $%.2f, Created: %s\n", 
-				p.ID, p.Name, float64(p.Price), p.SalesCount, p.ViewsCount, ratio, revenue, p.CreatedAt.Format("2006-01-02"))
+			fmt.Printf("ID: %d, Name: %s, Price: %s, Sales: %d, Views: %d, Ratio: %.4f, Revenue: %s, Created: %s\n", 
+				p.ID, p.Name, p.Price, p.SalesCount, p.ViewsCount, ratio, revenue, p.CreatedAt.Format("2006-01-02"))
 		}
 	})
 
@@ -58,8 +58,8 @@ func TestDebugSorting(t *testing.T) {
 
 		fmt.Println("\n=== Sorted by Price Ascending ===")
 		for i, p := range result.Products {
-			fmt.Printf("%d. ID: %d, Name: %s, Price: $%.2f\n", 
-				i+1, p.ID, p.Name, float64(p.Price))
+			fmt.Printf("%d. ID: %d, Name: %s, Price: %s\n", 
+				i+1, p.ID, p.Name, p.Price)
 		}
 	})
 
@@ -72,8 +72,8 @@ func TestDebugSorting(t *testing.T) {
 		fmt.Println("\n=== Sorted by Revenue ===")
 		for i, p := range result.Products {
 			revenue := p.RevenueGenerated()
-			fmt.Printf("%d. ID: %d, Name: %s, Revenue: $%.2f (Price: $%.2f × Sales: %d)\n", 
-				i+1, p.ID, p.Name, revenue, float64(p.Price), p.SalesCount)
+			fmt.Printf("%d. ID: %d, Name: %s, Revenue: %s (Price: %s × Sales: %d)\n", 
+				i+1, p.ID, p.Name, revenue, p.Price, p.SalesCount)
 		}
 	})
 }
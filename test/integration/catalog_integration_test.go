@@ -97,7 +97,7 @@ func TestCatalogIntegration(t *testing.T) {
 		
 		// Test with invalid products
 		invalidProducts := []catalog.Product{
-			{ID: 0, Name: "", Price: -10.0}, // Invalid product
+			{ID: 0, Name: "", Price: catalog.NewMoney(-1000, catalog.USD)}, // Invalid product
 		}
 		err = app.ValidateProducts(ctx, invalidProducts)
 		assert.Error(t, err)
@@ -142,7 +142,7 @@ func generateLargeDataset(size int) []catalog.Product {
 		products[i] = catalog.Product{
 			ID:         catalog.ProductID(i + 1),
 			Name:       fmt.Sprintf("Product %d", i+1),
-			Price:      catalog.Price(10 + float64(i%100)),
+			Price:      catalog.NewMoney(int64(10+i%100)*100, catalog.USD),
 			CreatedAt:  baseTime.Add(-time.Duration(i%365) * 24 * time.Hour),
 			SalesCount: (i%500 + 1),
 			ViewsCount: (i%2000 + 100),
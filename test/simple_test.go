@@ -34,7 +34,7 @@ func TestSimpleWorkflow(t *testing.T) {
 		require.Len(t, result.Products, 3)
 		
 		// Should be sorted by price ascending - Coffee Table is cheapest at $10.00
-		assert.Equal(t, catalog.Price(10.00), result.Products[0].Price)
+		assert.Equal(t, catalog.NewMoney(1000, catalog.USD), result.Products[0].Price)
 		assert.Equal(t, "Coffee Table", result.Products[0].Name)
 	})
 
@@ -0,0 +1,110 @@
+package testdata
+
+import (
+	"time"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+// ProductOption customises a product built by Product
+type ProductOption func(*catalog.Product)
+
+// Product builds a valid test product named "Product", priced at $10.00,
+// created a month ago, with 10 sales in 1000 views. Options override the
+// defaults in order.
+func Product(id catalog.ProductID, opts ...ProductOption) catalog.Product {
+	product := catalog.Product{
+		ID:         id,
+		Name:       "Product",
+		Price:      catalog.NewMoney(1000, catalog.USD),
+		CreatedAt:  time.Now().AddDate(0, -1, 0),
+		SalesCount: 10,
+		ViewsCount: 1000,
+	}
+	for _, opt := range opts {
+		opt(&product)
+	}
+	return product
+}
+
+// WithName sets the product name
+func WithName(name string) ProductOption {
+	return func(p *catalog.Product) { p.Name = name }
+}
+
+// WithPrice sets the price in US cents
+func WithPrice(cents int64) ProductOption {
+	return func(p *catalog.Product) { p.Price = catalog.NewMoney(cents, catalog.USD) }
+}
+
+// WithCost sets the cost price in US cents
+func WithCost(cents int64) ProductOption {
+	return func(p *catalog.Product) { p.CostPrice = usd(cents) }
+}
+
+// WithShipping sets the shipping cost in US cents
+func WithShipping(cents int64) ProductOption {
+	return func(p *catalog.Product) { p.ShippingCost = usd(cents) }
+}
+
+// WithLossLeader marks the product as sold at a loss on purpose
+func WithLossLeader() ProductOption {
+	return func(p *catalog.Product) { p.LossLeader = true }
+}
+
+// WithCategory sets the category
+func WithCategory(category string) ProductOption {
+	return func(p *catalog.Product) { p.Category = category }
+}
+
+// WithParent makes the product a variant of parent
+func WithParent(parent catalog.ProductID) ProductOption {
+	return func(p *catalog.Product) { p.ParentID = parent }
+}
+
+// WithSales sets the sales count
+func WithSales(sales int) ProductOption {
+	return func(p *catalog.Product) { p.SalesCount = sales }
+}
+
+// WithViews sets the views count
+func WithViews(views int) ProductOption {
+	return func(p *catalog.Product) { p.ViewsCount = views }
+}
+
+// WithStock sets the availability and stock quantity
+func WithStock(availability catalog.Availability, quantity int) ProductOption {
+	return func(p *catalog.Product) {
+		p.Availability = availability
+		p.StockQuantity = quantity
+	}
+}
+
+// WithRatings sets the review count and the sum of their stars
+func WithRatings(count, sum int) ProductOption {
+	return func(p *catalog.Product) {
+		p.RatingCount = count
+		p.RatingSum = sum
+	}
+}
+
+// WithStatus sets the lifecycle status
+func WithStatus(status catalog.LifecycleStatus) ProductOption {
+	return func(p *catalog.Product) { p.Status = status }
+}
+
+// WithAttributes sets the custom attributes
+func WithAttributes(attributes catalog.Attributes) ProductOption {
+	return func(p *catalog.Product) { p.Attributes = attributes }
+}
+
+// WithCreatedAt sets the creation time
+func WithCreatedAt(at time.Time) ProductOption {
+	return func(p *catalog.Product) { p.CreatedAt = at }
+}
+
+// usd returns an optional amount in US cents
+func usd(cents int64) *catalog.Money {
+	money := catalog.NewMoney(cents, catalog.USD)
+	return &money
+}
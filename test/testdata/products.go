@@ -12,7 +12,7 @@ func GetTestProducts() []catalog.Product {
 		{
 			ID:         1,
 			Name:       "Alabaster Table",
-			Price:      12.99,
+			Price:      catalog.NewMoney(1299, catalog.USD),
 			CreatedAt:  parseDate("2019-01-04"),
 			SalesCount: 32,
 			ViewsCount: 730, // 4.38% conversion ratio
@@ -20,7 +20,7 @@ func GetTestProducts() []catalog.Product {
 		{
 			ID:         2,
 			Name:       "Zebra Table",
-			Price:      44.49,
+			Price:      catalog.NewMoney(4449, catalog.USD),
 			CreatedAt:  parseDate("2012-01-04"),
 			SalesCount: 301,
 			ViewsCount: 3279, // 9.18% conversion ratio
@@ -28,7 +28,7 @@ func GetTestProducts() []catalog.Product {
 		{
 			ID:         3,
 			Name:       "Coffee Table",
-			Price:      10.00,
+			Price:      catalog.NewMoney(1000, catalog.USD),
 			CreatedAt:  parseDate("2014-05-28"),
 			SalesCount: 1048,
 			ViewsCount: 20123, // 5.21% conversion ratio
@@ -0,0 +1,196 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+func attributeTestProducts() catalog.ProductCollection {
+	return catalog.ProductCollection{
+		testdata.Product(1, testdata.WithAttributes(catalog.Attributes{"weight_kg": catalog.NumberAttribute(2.5), "brand": catalog.StringAttribute("Acme")})),
+		testdata.Product(2, testdata.WithAttributes(catalog.Attributes{"weight_kg": catalog.NumberAttribute(0.4), "brand": catalog.StringAttribute("Zenith")})),
+		testdata.Product(3, testdata.WithAttributes(catalog.Attributes{"brand": catalog.StringAttribute("Acme")})),
+		testdata.Product(4, testdata.WithAttributes(catalog.Attributes{"weight_kg": catalog.NumberAttribute(12), "fragile": catalog.BoolAttribute(true)})),
+		testdata.Product(5), // no attributes
+	}
+}
+
+func TestAttributeValue_JSON(t *testing.T) {
+	released := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	attributes := catalog.Attributes{
+		"color":     catalog.StringAttribute("red"),
+		"weight_kg": catalog.NumberAttribute(1.25),
+		"fragile":   catalog.BoolAttribute(true),
+		"released":  catalog.TimeAttribute(released),
+	}
+
+	data, err := json.Marshal(attributes)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"released":{"type":"time","value":"2024-05-01T12:00:00Z"}`)
+
+	var decoded catalog.Attributes
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, attributes, decoded)
+
+	t.Run("Bare Values", func(t *testing.T) {
+		var bare catalog.Attributes
+		require.NoError(t, json.Unmarshal([]byte(`{"color": "blue", "weight_kg": 3, "fragile": false}`), &bare))
+		assert.Equal(t, catalog.StringAttribute("blue"), bare["color"])
+		assert.Equal(t, catalog.NumberAttribute(3), bare["weight_kg"])
+		assert.Equal(t, catalog.BoolAttribute(false), bare["fragile"])
+	})
+
+	t.Run("Invalid Values", func(t *testing.T) {
+		var value catalog.AttributeValue
+		assert.Error(t, json.Unmarshal([]byte(`[1, 2]`), &value))
+		assert.Error(t, json.Unmarshal([]byte(`{"type": "color", "value": "red"}`), &value))
+		assert.Error(t, json.Unmarshal([]byte(`{"type": "time", "value": "yesterday"}`), &value))
+	})
+}
+
+func TestProduct_AttributeValidation(t *testing.T) {
+	for _, product := range attributeTestProducts() {
+		assert.NoError(t, product.Validate(), "Product %d", product.ID)
+	}
+
+	invalid := map[string]catalog.Attributes{
+		"Empty Name":    {"": catalog.StringAttribute("x")},
+		"Colon In Name": {"size:eu": catalog.NumberAttribute(42)},
+		"Untyped Value": {"color": {}},
+		"NaN Number":    {"weight_kg": catalog.NumberAttribute(math.NaN())},
+	}
+	for name, attributes := range invalid {
+		t.Run(name, func(t *testing.T) {
+			product := attributeTestProducts()[0]
+			product.Attributes = attributes
+			assert.Error(t, product.Validate())
+		})
+	}
+}
+
+func TestProductFilter_AttributePredicates(t *testing.T) {
+	products := attributeTestProducts()
+
+	tests := []struct {
+		name       string
+		predicates []catalog.AttributePredicate
+		expected   []catalog.ProductID
+	}{
+		{"Exists", []catalog.AttributePredicate{{Name: "weight_kg", Op: catalog.AttrExists}}, []catalog.ProductID{1, 2, 4}},
+		{"Equal", []catalog.AttributePredicate{{Name: "brand", Op: catalog.AttrEq, Value: catalog.StringAttribute("Acme")}}, []catalog.ProductID{1, 3}},
+		{"Not Equal Includes Missing", []catalog.AttributePredicate{{Name: "brand", Op: catalog.AttrNe, Value: catalog.StringAttribute("Acme")}}, []catalog.ProductID{2, 4, 5}},
+		{"Range", []catalog.AttributePredicate{
+			{Name: "weight_kg", Op: catalog.AttrGte, Value: catalog.NumberAttribute(0.5)},
+			{Name: "weight_kg", Op: catalog.AttrLt, Value: catalog.NumberAttribute(10)},
+		}, []catalog.ProductID{1}},
+		{"Type Mismatch", []catalog.AttributePredicate{{Name: "weight_kg", Op: catalog.AttrGt, Value: catalog.StringAttribute("1")}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := catalog.ProductFilter{Attributes: tt.predicates}
+			assert.False(t, filter.IsEmpty())
+
+			var matched []catalog.ProductID
+			for _, product := range products {
+				require.NoError(t, filter.Attributes[0].Validate())
+				if filter.Matches(product) {
+					matched = append(matched, product.ID)
+				}
+			}
+			assert.Equal(t, tt.expected, matched)
+		})
+	}
+
+	assert.Error(t, catalog.AttributePredicate{Name: "brand", Op: "like"}.Validate())
+}
+
+func TestSortStrategy_AttributeSpec(t *testing.T) {
+	valid := []struct {
+		strategy catalog.SortStrategy
+		expected catalog.AttributeSortSpec
+	}{
+		{"attr:weight_kg:asc", catalog.AttributeSortSpec{Name: "weight_kg", Ascending: true, Missing: catalog.MissingLast}},
+		{"attr:brand:desc:first", catalog.AttributeSortSpec{Name: "brand", Missing: catalog.MissingFirst}},
+		{"attr:weight_kg:asc:error", catalog.AttributeSortSpec{Name: "weight_kg", Ascending: true, Missing: catalog.MissingError}},
+	}
+	for _, tt := range valid {
+		spec, ok := tt.strategy.AttributeSpec()
+		require.True(t, ok, string(tt.strategy))
+		assert.Equal(t, tt.expected, spec)
+		assert.True(t, tt.strategy.IsValid())
+		assert.Equal(t, tt.strategy, catalog.AttributeSortStrategy(spec.Name, spec.Ascending, spec.Missing))
+	}
+
+	for _, strategy := range []catalog.SortStrategy{"attr:", "attr:weight_kg", "attr::asc", "attr:weight_kg:up", "attr:weight_kg:asc:skip", "weight_kg:asc"} {
+		_, ok := strategy.AttributeSpec()
+		assert.False(t, ok, string(strategy))
+		assert.False(t, strategy.IsValid(), string(strategy))
+	}
+
+	assert.Equal(t, "Attribute weight_kg (Ascending, Missing Last)", catalog.SortStrategy("attr:weight_kg:asc").Description())
+}
+
+func TestAttributeSorter(t *testing.T) {
+	ctx := context.Background()
+	factory := sorting.NewSorterFactory()
+	products := attributeTestProducts()
+
+	tests := []struct {
+		strategy catalog.SortStrategy
+		expected []catalog.ProductID
+	}{
+		{"attr:weight_kg:asc", []catalog.ProductID{2, 1, 4, 3, 5}},
+		{"attr:weight_kg:desc", []catalog.ProductID{4, 1, 2, 3, 5}},
+		{"attr:weight_kg:asc:first", []catalog.ProductID{3, 5, 2, 1, 4}},
+		{"attr:brand:asc", []catalog.ProductID{1, 3, 2, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			sorter, err := factory.CreateSorter(tt.strategy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.strategy, sorter.GetStrategy())
+
+			sorted, err := sorter.Sort(ctx, products)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, productIDs(sorted))
+		})
+	}
+
+	t.Run("Missing Is An Error", func(t *testing.T) {
+		service := catalog.NewService(factory, zap.NewNop())
+		_, err := service.SortProducts(ctx, products, "attr:weight_kg:asc:error")
+		assert.ErrorIs(t, err, catalog.ErrMissingAttribute)
+
+		_, err = service.StreamSort(ctx, catalog.NewCollectionIterator(products), "attr:weight_kg:asc:error",
+			catalog.StreamOptions{TopK: 2}, func(catalog.Product) error { return nil })
+		assert.ErrorIs(t, err, catalog.ErrMissingAttribute)
+
+		result, err := service.SortProducts(ctx, products[:2], "attr:weight_kg:asc:error")
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{2, 1}, productIDs(result.Products))
+	})
+
+	t.Run("Cursor Pagination", func(t *testing.T) {
+		service := catalog.NewService(factory, zap.NewNop())
+		first, err := service.SortPage(ctx, products, "attr:weight_kg:asc", catalog.PageRequest{Limit: 3})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{2, 1, 4}, productIDs(first.Products))
+
+		second, err := service.SortPage(ctx, products, "attr:weight_kg:asc", catalog.PageRequest{Limit: 3, After: first.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{3, 5}, productIDs(second.Products))
+	})
+}
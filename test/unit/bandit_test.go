@@ -0,0 +1,391 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/bandit"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// banditConfig builds a bandit over three strategies
+func banditConfig(policy catalog.BanditPolicy, seed int64) catalog.BanditConfig {
+	return catalog.BanditConfig{
+		Name:       "home",
+		Policy:     policy,
+		Strategies: catalog.SortStrategySet{catalog.SortByPopularity, catalog.SortByRevenue, catalog.SortByPriceAsc},
+		Seed:       seed,
+	}
+}
+
+// failingBanditStore fails every save while fail is set
+type failingBanditStore struct {
+	catalog.BanditStore
+	fail bool
+}
+
+func (s *failingBanditStore) Save(ctx context.Context, state catalog.BanditState) error {
+	if s.fail {
+		return errors.New("disk full")
+	}
+	return s.BanditStore.Save(ctx, state)
+}
+
+// blockingBanditStore holds saves of one bandit until release is closed
+type blockingBanditStore struct {
+	catalog.BanditStore
+	bandit  string
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingBanditStore) Save(ctx context.Context, state catalog.BanditState) error {
+	if state.Config.Name == s.bandit {
+		close(s.blocked)
+		<-s.release
+	}
+	return s.BanditStore.Save(ctx, state)
+}
+
+// simulateBandit serves rounds requests, rewarding each strategy with its
+// conversion probability, and returns how often each strategy was picked
+func simulateBandit(t *testing.T, b *catalog.Bandit, rounds int, conversion map[catalog.SortStrategy]float64) map[catalog.SortStrategy]int {
+	outcomes := rand.New(rand.NewSource(7))
+	picks := make(map[catalog.SortStrategy]int)
+	for i := 0; i < rounds; i++ {
+		selection := b.Select()
+		picks[selection.Strategy]++
+
+		reward := 0.0
+		if outcomes.Float64() < conversion[selection.Strategy] {
+			reward = 1
+		}
+		require.NoError(t, b.Update(selection.Strategy, reward))
+	}
+	return picks
+}
+
+func TestBanditConfigValidate(t *testing.T) {
+	require.NoError(t, banditConfig(catalog.PolicyThompson, 1).Validate())
+
+	tests := map[string]func(*catalog.BanditConfig){
+		"Empty Name":         func(c *catalog.BanditConfig) { c.Name = "" },
+		"Unknown Policy":     func(c *catalog.BanditConfig) { c.Policy = "ucb" },
+		"One Strategy":       func(c *catalog.BanditConfig) { c.Strategies = c.Strategies[:1] },
+		"Duplicate Strategy": func(c *catalog.BanditConfig) { c.Strategies[1] = c.Strategies[0] },
+		"Invalid Strategy":   func(c *catalog.BanditConfig) { c.Strategies[1] = "nope" },
+		"Epsilon Above 1":    func(c *catalog.BanditConfig) { epsilon := 1.5; c.Epsilon = &epsilon },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := banditConfig(catalog.PolicyEpsilonGreedy, 1)
+			mutate(&config)
+			assert.Error(t, config.Validate())
+		})
+	}
+}
+
+func TestBandit(t *testing.T) {
+	conversion := map[catalog.SortStrategy]float64{
+		catalog.SortByPopularity: 0.05,
+		catalog.SortByRevenue:    0.30,
+		catalog.SortByPriceAsc:   0.10,
+	}
+
+	for _, policy := range []catalog.BanditPolicy{catalog.PolicyThompson, catalog.PolicyEpsilonGreedy} {
+		t.Run(string(policy), func(t *testing.T) {
+			t.Run("Deterministic", func(t *testing.T) {
+				first, err := catalog.NewBandit(banditConfig(policy, 42))
+				require.NoError(t, err)
+				second, err := catalog.NewBandit(banditConfig(policy, 42))
+				require.NoError(t, err)
+
+				assert.Equal(t, simulateBandit(t, first, 300, conversion), simulateBandit(t, second, 300, conversion))
+				assert.Equal(t, first.State().Arms, second.State().Arms)
+			})
+
+			t.Run("Converges", func(t *testing.T) {
+				b, err := catalog.NewBandit(banditConfig(policy, 42))
+				require.NoError(t, err)
+				picks := simulateBandit(t, b, 3000, conversion)
+				assert.Greater(t, picks[catalog.SortByRevenue], 2100)
+			})
+
+			t.Run("Resumes From State", func(t *testing.T) {
+				original, err := catalog.NewBandit(banditConfig(policy, 9))
+				require.NoError(t, err)
+				simulateBandit(t, original, 100, conversion)
+
+				restored, err := catalog.RestoreBandit(original.State())
+				require.NoError(t, err)
+				for i := 0; i < 50; i++ {
+					assert.Equal(t, original.Select(), restored.Select())
+				}
+			})
+		})
+	}
+
+	t.Run("Epsilon Greedy Tries Each Strategy", func(t *testing.T) {
+		config := banditConfig(catalog.PolicyEpsilonGreedy, 3)
+		greedy := 0.0
+		config.Epsilon = &greedy
+		b, err := catalog.NewBandit(config)
+		require.NoError(t, err)
+
+		for _, strategy := range config.Strategies {
+			selection := b.Select()
+			assert.Equal(t, strategy, selection.Strategy)
+			assert.False(t, selection.Explored)
+			require.NoError(t, b.Update(strategy, 0))
+		}
+
+		// Zero epsilon is kept, so the best strategy is served every time
+		require.NoError(t, b.Update(config.Strategies[1], 1))
+		for i := 0; i < 200; i++ {
+			selection := b.Select()
+			require.False(t, selection.Explored)
+			require.Equal(t, config.Strategies[1], selection.Strategy)
+		}
+		assert.Equal(t, 0.0, *b.State().Config.Epsilon)
+	})
+
+	t.Run("Invalid Rewards", func(t *testing.T) {
+		b, err := catalog.NewBandit(banditConfig(catalog.PolicyThompson, 1))
+		require.NoError(t, err)
+		assert.Error(t, b.Update(catalog.SortByRevenue, 1.5))
+		assert.Error(t, b.Update(catalog.SortByRevenue, -1))
+		assert.Error(t, b.Update(catalog.SortByName, 1))
+	})
+
+	t.Run("Rejects Mismatched State", func(t *testing.T) {
+		b, err := catalog.NewBandit(banditConfig(catalog.PolicyThompson, 1))
+		require.NoError(t, err)
+		state := b.State()
+		state.Arms = state.Arms[:2]
+		_, err = catalog.RestoreBandit(state)
+		assert.Error(t, err)
+	})
+}
+
+func TestService_Bandits(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "bandits.json")
+	store, err := bandit.NewFileStore(path)
+	require.NoError(t, err)
+
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithBandits(store))
+	config := banditConfig(catalog.PolicyThompson, 5)
+	require.NoError(t, service.CreateBandit(ctx, config))
+	assert.Error(t, service.CreateBandit(ctx, config))
+
+	products := generateLargeProductCollection(20)
+	for i := 0; i < 20; i++ {
+		result, err := service.SortWithBandit(ctx, products, "home", catalog.SortOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, result.Bandit)
+		assert.Equal(t, result.Bandit.Strategy, result.Strategy)
+		require.NoError(t, service.RecordBanditReward(ctx, catalog.BanditReward{
+			Bandit: "home", Strategy: result.Strategy, Reward: 1,
+		}))
+	}
+
+	_, err = service.SortWithBandit(ctx, products, "missing", catalog.SortOptions{})
+	assert.ErrorIs(t, err, catalog.ErrBanditNotFound)
+	assert.Error(t, service.RecordBanditReward(ctx, catalog.BanditReward{Bandit: "home", Strategy: catalog.SortByName, Reward: 1}))
+
+	t.Run("Survives Restart", func(t *testing.T) {
+		reopened, err := bandit.NewFileStore(path)
+		require.NoError(t, err)
+		state, err := reopened.Find(ctx, "home")
+		require.NoError(t, err)
+
+		pulls := int64(0)
+		for _, arm := range state.Arms {
+			pulls += arm.Pulls
+		}
+		assert.Equal(t, int64(20), pulls)
+
+		// The restarted service continues the same random sequence
+		expected, err := catalog.RestoreBandit(*state)
+		require.NoError(t, err)
+		restarted := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithBandits(reopened))
+		for i := 0; i < 10; i++ {
+			result, err := restarted.SortWithBandit(ctx, products, "home", catalog.SortOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, expected.Select().Strategy, result.Strategy)
+		}
+	})
+
+	t.Run("Failed Save Keeps Sequence", func(t *testing.T) {
+		store, err := bandit.NewFileStore(filepath.Join(t.TempDir(), "bandits.json"))
+		require.NoError(t, err)
+		failing := &failingBanditStore{BanditStore: store}
+		flaky := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithBandits(failing))
+		require.NoError(t, flaky.CreateBandit(ctx, config))
+
+		expected, err := catalog.NewBandit(config)
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			failing.fail = i%3 == 0
+			selection, err := flaky.SelectBandit(ctx, "home")
+			if failing.fail {
+				assert.Error(t, err)
+				assert.Error(t, flaky.RecordBanditReward(ctx, catalog.BanditReward{Bandit: "home", Strategy: catalog.SortByRevenue, Reward: 1}))
+				continue
+			}
+			require.NoError(t, err)
+			assert.Equal(t, expected.Select().Strategy, selection.Strategy)
+		}
+
+		states, err := flaky.ListBandits(ctx)
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.Equal(t, expected.State().Arms, states[0].Arms)
+	})
+
+	t.Run("Bandits Do Not Wait On Each Other", func(t *testing.T) {
+		store, err := bandit.NewFileStore(filepath.Join(t.TempDir(), "bandits.json"))
+		require.NoError(t, err)
+		blocking := &blockingBanditStore{BanditStore: store}
+		service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithBandits(blocking))
+
+		search := banditConfig(catalog.PolicyThompson, 1)
+		search.Name = "search"
+		require.NoError(t, service.CreateBandit(ctx, config))
+		require.NoError(t, service.CreateBandit(ctx, search))
+
+		blocking.bandit = "home"
+		blocking.blocked, blocking.release = make(chan struct{}), make(chan struct{})
+		slow := make(chan error, 1)
+		go func() {
+			_, err := service.SelectBandit(ctx, "home")
+			slow <- err
+		}()
+		<-blocking.blocked
+
+		fast := make(chan error, 1)
+		go func() {
+			_, err := service.SelectBandit(ctx, "search")
+			fast <- err
+		}()
+		select {
+		case err := <-fast:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("selecting one bandit waited for another bandit's save")
+		}
+
+		close(blocking.release)
+		assert.NoError(t, <-slow)
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		handler := httpapi.NewHandler(service, zap.NewNop())
+		var body bytes.Buffer
+		for _, product := range products {
+			require.NoError(t, json.NewEncoder(&body).Encode(product))
+		}
+		request := httptest.NewRequest(http.MethodPost, "/v1/bandits/sort?bandit=home", &body)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var decoded catalog.SortResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&decoded))
+		require.NotNil(t, decoded.Bandit)
+
+		reward := `{"bandit": "home", "strategy": "` + string(decoded.Strategy) + `", "reward": 0}`
+		request = httptest.NewRequest(http.MethodPost, "/v1/bandits/reward", strings.NewReader(reward))
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+		request = httptest.NewRequest(http.MethodPost, "/v1/bandits/reward", strings.NewReader(`{"bandit": "missing"}`))
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestBanditFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Concurrent Saves", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bandits.json")
+		store, err := bandit.NewFileStore(path)
+		require.NoError(t, err)
+
+		const bandits = 20
+		var wg sync.WaitGroup
+		for i := 0; i < bandits; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				config := banditConfig(catalog.PolicyThompson, int64(i))
+				config.Name = fmt.Sprintf("bandit-%02d", i)
+				b, err := catalog.NewBandit(config)
+				require.NoError(t, err)
+				assert.NoError(t, store.Save(ctx, b.State()))
+			}(i)
+		}
+		wg.Wait()
+
+		reopened, err := bandit.NewFileStore(path)
+		require.NoError(t, err)
+		states, err := reopened.FindAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, states, bandits)
+	})
+
+	t.Run("Failed Write Is Undone", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bandits.json")
+		store, err := bandit.NewFileStore(path)
+		require.NoError(t, err)
+
+		b, err := catalog.NewBandit(banditConfig(catalog.PolicyThompson, 1))
+		require.NoError(t, err)
+		require.NoError(t, store.Save(ctx, b.State()))
+
+		// A directory in the file's place makes every write fail
+		require.NoError(t, os.Remove(path))
+		require.NoError(t, os.MkdirAll(filepath.Join(path, "blocked"), 0o755))
+
+		b.Select()
+		assert.Error(t, store.Save(ctx, b.State()))
+		other := banditConfig(catalog.PolicyThompson, 2)
+		other.Name = "search"
+		created, err := catalog.NewBandit(other)
+		require.NoError(t, err)
+		assert.Error(t, store.Save(ctx, created.State()))
+
+		state, err := store.Find(ctx, "home")
+		require.NoError(t, err)
+		assert.NotEqual(t, b.State().RNGState, state.RNGState)
+		_, err = store.Find(ctx, "search")
+		assert.ErrorIs(t, err, catalog.ErrBanditNotFound)
+
+		require.NoError(t, os.RemoveAll(path))
+		require.NoError(t, store.Save(ctx, b.State()))
+		state, err = store.Find(ctx, "home")
+		require.NoError(t, err)
+		assert.Equal(t, b.State().RNGState, state.RNGState)
+	})
+}
@@ -36,13 +36,13 @@ func TestBasicFunctionality(t *testing.T) {
 
 		// Should be sorted by price ascending: Coffee Table ($10.00), Alabaster Table ($12.99), Zebra Table ($44.49)
 		assert.Equal(t, "Coffee Table", result.Products[0].Name)
-		assert.Equal(t, catalog.Price(10.00), result.Products[0].Price)
+		assert.Equal(t, catalog.NewMoney(1000, catalog.USD), result.Products[0].Price)
 		
 		assert.Equal(t, "Alabaster Table", result.Products[1].Name)
-		assert.Equal(t, catalog.Price(12.99), result.Products[1].Price)
+		assert.Equal(t, catalog.NewMoney(1299, catalog.USD), result.Products[1].Price)
 		
 		assert.Equal(t, "Zebra Table", result.Products[2].Name)
-		assert.Equal(t, catalog.Price(44.49), result.Products[2].Price)
+		assert.Equal(t, catalog.NewMoney(4449, catalog.USD), result.Products[2].Price)
 	})
 
 	t.Run("Sort by Price Descending", func(t *testing.T) {
@@ -52,7 +52,7 @@ func TestBasicFunctionality(t *testing.T) {
 
 		// Should be sorted by price descending: Zebra Table ($44.49), Alabaster Table ($12.99), Coffee Table ($10.00)
 		assert.Equal(t, "Zebra Table", result.Products[0].Name)
-		assert.Equal(t, catalog.Price(44.49), result.Products[0].Price)
+		assert.Equal(t, catalog.NewMoney(4449, catalog.USD), result.Products[0].Price)
 	})
 
 	t.Run("Sort by Sales Conversion Ratio", func(t *testing.T) {
@@ -117,7 +117,7 @@ func TestBasicFunctionality(t *testing.T) {
 
 		// Test with invalid product
 		invalidProducts := []catalog.Product{
-			{ID: 0, Name: "", Price: -10.0}, // Invalid product
+			{ID: 0, Name: "", Price: catalog.NewMoney(-1000, catalog.USD)}, // Invalid product
 		}
 		err = app.ValidateProducts(ctx, invalidProducts)
 		assert.Error(t, err)
@@ -129,7 +129,7 @@ func TestProductValidation(t *testing.T) {
 		product := catalog.Product{
 			ID:         1,
 			Name:       "Valid Product",
-			Price:      10.0,
+			Price:      catalog.NewMoney(1000, catalog.USD),
 			CreatedAt:  time.Now(),
 			SalesCount: 5,
 			ViewsCount: 50,
@@ -144,7 +144,7 @@ func TestProductValidation(t *testing.T) {
 		product := catalog.Product{
 			ID:         0,
 			Name:       "",
-			Price:      -10.0,
+			Price:      catalog.NewMoney(-1000, catalog.USD),
 			CreatedAt:  time.Time{},
 			SalesCount: -1,
 			ViewsCount: -1,
@@ -0,0 +1,176 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// countingSorterFactory wraps the default factory with slow, counted sorters
+type countingSorterFactory struct {
+	catalog.SorterFactory
+	sorts int32
+	delay time.Duration
+}
+
+func (f *countingSorterFactory) CreateSorter(strategy catalog.SortStrategy) (catalog.Sorter, error) {
+	sorter, err := f.SorterFactory.CreateSorter(strategy)
+	if err != nil {
+		return nil, err
+	}
+	return &countingSorter{Sorter: sorter, factory: f}, nil
+}
+
+type countingSorter struct {
+	catalog.Sorter
+	factory *countingSorterFactory
+}
+
+func (s *countingSorter) Sort(ctx context.Context, products catalog.ProductCollection) (catalog.ProductCollection, error) {
+	atomic.AddInt32(&s.factory.sorts, 1)
+	time.Sleep(s.factory.delay)
+	return s.Sorter.Sort(ctx, products)
+}
+
+func TestService_RequestCoalescing(t *testing.T) {
+	ctx := context.Background()
+	products := generateLargeProductCollection(200)
+
+	t.Run("Identical Concurrent Requests Share One Sort", func(t *testing.T) {
+		factory := &countingSorterFactory{SorterFactory: sorting.NewSorterFactory(), delay: 100 * time.Millisecond}
+		service := catalog.NewService(factory, zap.NewNop())
+
+		const callers = 10
+		results := make([]*catalog.SortResult, callers)
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				result, err := service.SortProducts(ctx, products, catalog.SortByRevenue)
+				assert.NoError(t, err)
+				results[i] = result
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&factory.sorts))
+
+		// Every caller owns an independent copy
+		require.NotNil(t, results[0])
+		require.NotNil(t, results[1])
+		originalID := results[1].Products[0].ID
+		results[0].Products[0].ID = -1
+		assert.Equal(t, originalID, results[1].Products[0].ID)
+	})
+
+	t.Run("Simultaneous Arrivals Share One Sort", func(t *testing.T) {
+		const rounds, callers = 20, 32
+		for round := 0; round < rounds; round++ {
+			factory := &countingSorterFactory{SorterFactory: sorting.NewSorterFactory(), delay: 20 * time.Millisecond}
+			service := catalog.NewService(factory, zap.NewNop())
+
+			// Release every caller at once, so none finds a sort already running
+			start := make(chan struct{})
+			var wg sync.WaitGroup
+			for i := 0; i < callers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					_, err := service.SortProducts(ctx, products, catalog.SortByRevenue)
+					assert.NoError(t, err)
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			require.Equal(t, int32(1), atomic.LoadInt32(&factory.sorts), "round %d", round)
+		}
+	})
+
+	t.Run("Equal Catalogs In Different Slices Share One Sort", func(t *testing.T) {
+		factory := &countingSorterFactory{SorterFactory: sorting.NewSorterFactory(), delay: 100 * time.Millisecond}
+		service := catalog.NewService(factory, zap.NewNop())
+
+		changed := products.Copy()
+		changed[7].SalesCount++
+		catalogs := []catalog.ProductCollection{products.Copy(), products.Copy(), products.Copy(), changed}
+
+		var wg sync.WaitGroup
+		for i, collection := range catalogs {
+			wg.Add(1)
+			go func(i int, collection catalog.ProductCollection) {
+				defer wg.Done()
+				// Let the first caller start the sort the others compare with
+				time.Sleep(time.Duration(i) * 10 * time.Millisecond)
+				_, err := service.SortProducts(ctx, collection, catalog.SortByRevenue)
+				assert.NoError(t, err)
+			}(i, collection)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&factory.sorts))
+	})
+
+	t.Run("Different Strategies Are Not Coalesced", func(t *testing.T) {
+		factory := &countingSorterFactory{SorterFactory: sorting.NewSorterFactory(), delay: 50 * time.Millisecond}
+		service := catalog.NewService(factory, zap.NewNop())
+
+		var wg sync.WaitGroup
+		for _, strategy := range []catalog.SortStrategy{catalog.SortByName, catalog.SortByPopularity} {
+			wg.Add(1)
+			go func(strategy catalog.SortStrategy) {
+				defer wg.Done()
+				_, err := service.SortProducts(ctx, products, strategy)
+				assert.NoError(t, err)
+			}(strategy)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&factory.sorts))
+	})
+
+	t.Run("Sequential Requests Recompute", func(t *testing.T) {
+		factory := &countingSorterFactory{SorterFactory: sorting.NewSorterFactory()}
+		service := catalog.NewService(factory, zap.NewNop())
+
+		for i := 0; i < 3; i++ {
+			_, err := service.SortProducts(ctx, products, catalog.SortByName)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(3), atomic.LoadInt32(&factory.sorts))
+	})
+
+	t.Run("Errors Are Shared", func(t *testing.T) {
+		factory := &countingSorterFactory{SorterFactory: sorting.NewSorterFactory()}
+		service := catalog.NewService(factory, zap.NewNop())
+
+		invalid := catalog.ProductCollection{{ID: 0, Name: ""}}
+		_, err := service.SortProducts(ctx, invalid, catalog.SortByName)
+		assert.Error(t, err)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&factory.sorts))
+	})
+}
+
+func TestNewCacheKey(t *testing.T) {
+	products := generateLargeProductCollection(10)
+
+	key := catalog.NewCacheKey(products, catalog.SortByName)
+	assert.Equal(t, key, catalog.NewCacheKey(products.Copy(), catalog.SortByName))
+	assert.NotEqual(t, key, catalog.NewCacheKey(products, catalog.SortByPriceAsc))
+
+	changed := products.Copy()
+	changed[3].SalesCount++
+	assert.NotEqual(t, key.ProductHash, catalog.NewCacheKey(changed, catalog.SortByName).ProductHash)
+}
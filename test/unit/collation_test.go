@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// namedProducts creates one product per name, with IDs in argument order
+func namedProducts(names ...string) catalog.ProductCollection {
+	products := make(catalog.ProductCollection, len(names))
+	for i, name := range names {
+		products[i] = catalog.Product{ID: catalog.ProductID(i + 1), Name: name,
+			Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now().AddDate(0, 0, -1)}
+	}
+	return products
+}
+
+func sortedNames(t *testing.T, sorter catalog.Sorter, products catalog.ProductCollection) []string {
+	t.Helper()
+	sorted, err := sorter.Sort(context.Background(), products)
+	require.NoError(t, err)
+
+	names := make([]string, len(sorted))
+	for i, product := range sorted {
+		names[i] = product.Name
+	}
+	return names
+}
+
+func collating(collation catalog.Collation) catalog.Sorter {
+	return sorting.NewNameSorter().(catalog.CollatingSorter).WithCollation(collation)
+}
+
+func TestNameSorter_Collation(t *testing.T) {
+	t.Run("Accents Sort With Their Base Letter", func(t *testing.T) {
+		products := namedProducts("Zebra", "Éclair", "eclipse", "Apple")
+		assert.Equal(t, []string{"Apple", "Éclair", "eclipse", "Zebra"}, sortedNames(t, sorting.NewNameSorter(), products))
+	})
+
+	t.Run("German And Swedish", func(t *testing.T) {
+		products := namedProducts("Zucker", "Öl", "Ost")
+		assert.Equal(t, []string{"Öl", "Ost", "Zucker"}, sortedNames(t, collating(catalog.Collation{Locale: "de"}), products))
+		assert.Equal(t, []string{"Ost", "Zucker", "Öl"}, sortedNames(t, collating(catalog.Collation{Locale: "sv"}), products))
+	})
+
+	t.Run("Turkish Dotless I", func(t *testing.T) {
+		products := namedProducts("iğne", "ılık")
+		assert.Equal(t, []string{"ılık", "iğne"}, sortedNames(t, collating(catalog.Collation{Locale: "tr"}), products))
+	})
+
+	t.Run("Natural Numbers", func(t *testing.T) {
+		products := namedProducts("Table 10", "Table 2", "Table 1")
+		assert.Equal(t, []string{"Table 1", "Table 10", "Table 2"}, sortedNames(t, sorting.NewNameSorter(), products))
+		assert.Equal(t, []string{"Table 1", "Table 2", "Table 10"}, sortedNames(t, collating(catalog.Collation{Numeric: true}), products))
+	})
+}
+
+func TestProduct_NameIn(t *testing.T) {
+	product := namedProducts("Chair")[0]
+	product.LocalizedNames = map[string]string{"de": "Stuhl", "fr-CA": "Chaise"}
+
+	assert.Equal(t, "Stuhl", product.NameIn("de"))
+	assert.Equal(t, "Stuhl", product.NameIn("de-CH"))
+	assert.Equal(t, "Chaise", product.NameIn("fr-ca"))
+	assert.Equal(t, "Chair", product.NameIn("fr"))
+	assert.Equal(t, "Chair", product.NameIn(""))
+	require.NoError(t, product.Validate())
+
+	product.LocalizedNames["sv"] = ""
+	assert.Error(t, product.Validate())
+
+	t.Run("Tags Differing By Case", func(t *testing.T) {
+		product.LocalizedNames = map[string]string{"de": "Stuhl", "DE": "Sessel", "fr": "Chaise"}
+		assert.Error(t, product.Validate())
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, "Sessel", product.NameIn("De"))
+			assert.Equal(t, "Stuhl", product.NameIn("de-CH"))
+		}
+	})
+
+	t.Run("Malformed Tag", func(t *testing.T) {
+		product.LocalizedNames = map[string]string{"not a tag": "Stuhl"}
+		assert.Error(t, product.Validate())
+	})
+}
+
+func TestService_SortWithCollation(t *testing.T) {
+	ctx := context.Background()
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+
+	products := namedProducts("Chair", "Table", "Lamp")
+	products[0].LocalizedNames = map[string]string{"de": "Stuhl"}
+	products[1].LocalizedNames = map[string]string{"de": "Tisch"}
+	products[2].LocalizedNames = map[string]string{"de": "Lampe"}
+
+	result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByName,
+		catalog.SortOptions{Collation: &catalog.Collation{Locale: "de-DE"}})
+	require.NoError(t, err)
+	assert.Equal(t, []catalog.ProductID{3, 1, 2}, productIDs(result.Products))
+
+	result, err = service.SortProductsWithOptions(ctx, products, catalog.SortByName, catalog.SortOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []catalog.ProductID{1, 3, 2}, productIDs(result.Products))
+
+	_, err = service.SortProductsWithOptions(ctx, products, catalog.SortByName,
+		catalog.SortOptions{Collation: &catalog.Collation{Locale: "not a locale"}})
+	assert.Error(t, err)
+}
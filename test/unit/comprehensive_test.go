@@ -120,7 +120,7 @@ func (suite *CatalogTestSuite) TestProductValidationComprehensive() {
 
 			// Test revenue calculation
 			revenue := product.RevenueGenerated()
-			expectedRevenue := float64(product.Price) * float64(product.SalesCount)
+			expectedRevenue := catalog.NewMoney(product.Price.Amount*int64(product.SalesCount), product.Price.Currency)
 			suite.Equal(expectedRevenue, revenue)
 
 			// Test days on market
@@ -207,9 +207,9 @@ func (suite *CatalogTestSuite) TestEdgeCasesAndErrorHandling() {
 func (suite *CatalogTestSuite) TestSortStability() {
 	// Create products with identical prices but different IDs
 	identicalPriceProducts := []catalog.Product{
-		{ID: 3, Name: "Product C", Price: 100.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
-		{ID: 1, Name: "Product A", Price: 100.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
-		{ID: 2, Name: "Product B", Price: 100.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+		{ID: 3, Name: "Product C", Price: catalog.NewMoney(10000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+		{ID: 1, Name: "Product A", Price: catalog.NewMoney(10000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+		{ID: 2, Name: "Product B", Price: catalog.NewMoney(10000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
 	}
 
 	result, err := suite.app.SortProducts(suite.ctx, identicalPriceProducts, catalog.SortByPriceAsc)
@@ -226,7 +226,7 @@ func (suite *CatalogTestSuite) TestSortStability() {
 
 	// All should have the same price
 	for _, product := range result.Products {
-		suite.Equal(catalog.Price(100.0), product.Price)
+		suite.Equal(catalog.NewMoney(10000, catalog.USD), product.Price)
 	}
 }
 
@@ -257,7 +257,7 @@ func generateTestProducts(count int) []catalog.Product {
 		products[i] = catalog.Product{
 			ID:         catalog.ProductID(i + 1),
 			Name:       fmt.Sprintf("Product %d", i+1),
-			Price:      catalog.Price(10 + float64(i%100)),
+			Price:      catalog.NewMoney(int64(10+i%100)*100, catalog.USD),
 			CreatedAt:  baseTime.Add(-time.Duration(i%365) * 24 * time.Hour),
 			SalesCount: (i%500 + 1),
 			ViewsCount: (i%2000 + 100),
@@ -0,0 +1,207 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/repository"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	product := catalog.Product{
+		ID:         7,
+		Name:       "Walnut Desk",
+		Price:      catalog.NewMoney(9950, catalog.USD),
+		CreatedAt:  time.Date(2020, 5, 1, 12, 30, 0, 123456789, time.UTC),
+		SalesCount: 12,
+		ViewsCount: 300,
+	}
+
+	t.Run("Round Trip Keeps Tie-Break Chain", func(t *testing.T) {
+		cursor := catalog.NewCursor(catalog.SortByRevenue, product)
+
+		decoded, err := catalog.DecodeCursor(cursor.Encode())
+		require.NoError(t, err)
+
+		assert.Equal(t, catalog.SortByRevenue, decoded.Strategy)
+		assert.Equal(t, product.ID, decoded.Key.ID)
+		assert.Equal(t, product.Price, decoded.Key.Price)
+		assert.Equal(t, product.SalesCount, decoded.Key.SalesCount)
+		assert.Empty(t, decoded.Key.Name, "Fields outside the sort key should not be encoded")
+	})
+
+	t.Run("Created At Precision", func(t *testing.T) {
+		cursor := catalog.NewCursor(catalog.SortByCreatedAtDesc, product)
+
+		decoded, err := catalog.DecodeCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.True(t, product.CreatedAt.Equal(decoded.Key.CreatedAt))
+	})
+
+	t.Run("Invalid Tokens", func(t *testing.T) {
+		_, err := catalog.DecodeCursor("not base64!")
+		assert.ErrorIs(t, err, catalog.ErrInvalidCursor)
+
+		_, err = catalog.DecodeCursor("bm90IGpzb24")
+		assert.ErrorIs(t, err, catalog.ErrInvalidCursor)
+
+		bogus := catalog.Cursor{Strategy: "bogus"}.Encode()
+		_, err = catalog.DecodeCursor(bogus)
+		assert.ErrorIs(t, err, catalog.ErrInvalidCursor)
+	})
+
+	t.Run("Strategy Mismatch", func(t *testing.T) {
+		page := catalog.PageRequest{After: catalog.NewCursor(catalog.SortByName, product).Encode(), Limit: 5}
+
+		_, err := page.ResolveCursor(catalog.SortByPriceAsc)
+		assert.ErrorIs(t, err, catalog.ErrInvalidCursor)
+	})
+}
+
+func TestService_SortPage(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := generateLargeProductCollection(300)
+	ctx := context.Background()
+
+	for _, strategy := range catalog.AllSortStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			expected, err := service.SortProducts(ctx, products, strategy)
+			require.NoError(t, err)
+
+			var paged catalog.ProductCollection
+			page := catalog.PageRequest{Limit: 40}
+			for {
+				result, err := service.SortPage(ctx, products, strategy, page)
+				require.NoError(t, err)
+				paged = append(paged, result.Products...)
+
+				if !result.HasMore {
+					assert.Empty(t, result.NextCursor)
+					break
+				}
+				page.After = result.NextCursor
+			}
+
+			assert.Equal(t, productIDs(expected.Products), productIDs(paged))
+		})
+	}
+
+	t.Run("Catalog Changes Between Pages", func(t *testing.T) {
+		first, err := service.SortPage(ctx, products, catalog.SortByPriceAsc, catalog.PageRequest{Limit: 10})
+		require.NoError(t, err)
+		require.True(t, first.HasMore)
+
+		// Remove a product from the first page and add one that sorts before the cursor
+		changed := products[1:].Copy()
+		changed = append(changed, catalog.Product{
+			ID: 9999, Name: "Bargain", Price: catalog.NewMoney(100, catalog.USD), CreatedAt: time.Now().Add(-time.Hour), SalesCount: 1, ViewsCount: 10,
+		})
+
+		second, err := service.SortPage(ctx, changed, catalog.SortByPriceAsc,
+			catalog.PageRequest{After: first.NextCursor, Limit: 10})
+		require.NoError(t, err)
+
+		seen := make(map[catalog.ProductID]bool)
+		for _, product := range first.Products {
+			seen[product.ID] = true
+		}
+		for _, product := range second.Products {
+			assert.False(t, seen[product.ID], "Product %d was duplicated across pages", product.ID)
+			assert.GreaterOrEqual(t, product.Price.Cmp(first.Products[9].Price), 0)
+		}
+	})
+
+	t.Run("Invalid Limit", func(t *testing.T) {
+		_, err := service.SortPage(ctx, products, catalog.SortByName, catalog.PageRequest{})
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryRepository_GetSortedPageAfter(t *testing.T) {
+	factory := sorting.NewSorterFactory()
+	repo, err := repository.NewMemoryRepository(factory)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	products := generateLargeProductCollection(200)
+	for _, product := range products {
+		product := product
+		require.NoError(t, repo.SaveProduct(ctx, &product))
+	}
+
+	for _, strategy := range catalog.AllSortStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			sorter, err := factory.CreateSorter(strategy)
+			require.NoError(t, err)
+			expected, err := sorter.Sort(ctx, products)
+			require.NoError(t, err)
+
+			var paged catalog.ProductCollection
+			page := catalog.PageRequest{Limit: 33}
+			for {
+				result, err := repo.GetSortedPageAfter(ctx, strategy, page)
+				require.NoError(t, err)
+				paged = append(paged, result.Products...)
+
+				if !result.HasMore {
+					break
+				}
+				page.After = result.NextCursor
+			}
+
+			assert.Equal(t, productIDs(expected), productIDs(paged))
+		})
+	}
+}
+
+func TestHTTPHandler_SortPage(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	handler := httpapi.NewHandler(service, zap.NewNop())
+
+	var body bytes.Buffer
+	for _, product := range generateLargeProductCollection(30) {
+		require.NoError(t, json.NewEncoder(&body).Encode(product))
+	}
+
+	fetch := func(after string) (*catalog.PageResult, int) {
+		query := url.Values{"strategy": {"name"}, "limit": {"12"}}
+		if after != "" {
+			query.Set("after", after)
+		}
+		request := httptest.NewRequest(http.MethodPost, "/v1/sort?"+query.Encode(), bytes.NewReader(body.Bytes()))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		var result catalog.PageResult
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		}
+		return &result, recorder.Code
+	}
+
+	first, status := fetch("")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, first.Products, 12)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, status := fetch(first.NextCursor)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, second.Products, 12)
+	assert.NotEqual(t, first.Products[0].ID, second.Products[0].ID)
+
+	_, status = fetch("garbage")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,169 @@
+package unit
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// categorized returns products with IDs 1..n in the given categories
+func categorized(categories ...string) catalog.ProductCollection {
+	products := generateLargeProductCollection(len(categories))
+	for i, category := range categories {
+		products[i].Category = category
+	}
+	return products
+}
+
+func TestDiversityPolicy_Validate(t *testing.T) {
+	valid := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 3}
+	require.NoError(t, valid.Validate())
+
+	brand := catalog.DiversityPolicy{GroupBy: "attr:color", MaxPerWindow: 2, Window: 2, Depth: 10}
+	require.NoError(t, brand.Validate())
+	assert.Equal(t, "attr:color:2/2:top10", brand.String())
+
+	invalid := map[string]func(p *catalog.DiversityPolicy){
+		"Unknown Group":    func(p *catalog.DiversityPolicy) { p.GroupBy = "supplier" },
+		"Bad Attribute":    func(p *catalog.DiversityPolicy) { p.GroupBy = "attr:" },
+		"Zero Window":      func(p *catalog.DiversityPolicy) { p.Window = 0 },
+		"Zero Max":         func(p *catalog.DiversityPolicy) { p.MaxPerWindow = 0 },
+		"Max Above Window": func(p *catalog.DiversityPolicy) { p.MaxPerWindow = 4 },
+		"Negative Depth":   func(p *catalog.DiversityPolicy) { p.Depth = -1 },
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			policy := valid
+			mutate(&policy)
+			assert.Error(t, policy.Validate())
+		})
+	}
+}
+
+func TestDiversityPolicy_Apply(t *testing.T) {
+	products := categorized("a", "a", "a", "b", "b", "c")
+
+	t.Run("One Per Window", func(t *testing.T) {
+		policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 2}
+		assert.Equal(t, []catalog.ProductID{1, 4, 2, 5, 3, 6}, productIDs(policy.Apply(products)))
+	})
+
+	t.Run("Keeps Base Order When Satisfied", func(t *testing.T) {
+		policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 3, Window: 3}
+		assert.Equal(t, productIDs(products), productIDs(policy.Apply(products)))
+	})
+
+	t.Run("Relaxes When Impossible", func(t *testing.T) {
+		same := categorized("a", "a", "a")
+		policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 3}
+		assert.Equal(t, []catalog.ProductID{1, 2, 3}, productIDs(policy.Apply(same)))
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 2, Depth: 2}
+		assert.Equal(t, []catalog.ProductID{1, 4, 2, 3, 5, 6}, productIDs(policy.Apply(products)))
+	})
+
+	t.Run("Ungrouped Products Unconstrained", func(t *testing.T) {
+		mixed := categorized("", "", "a", "a")
+		policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 2}
+		assert.Equal(t, []catalog.ProductID{1, 2, 3, 4}, productIDs(policy.Apply(mixed)))
+	})
+
+	t.Run("Matches Greedy Reference", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(3))
+		for round := 0; round < 50; round++ {
+			categories := make([]string, 40)
+			for i := range categories {
+				categories[i] = []string{"", "a", "a", "a", "b", "b", "c"}[rng.Intn(7)]
+			}
+			policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory,
+				MaxPerWindow: 1 + rng.Intn(2), Window: 2 + rng.Intn(4), Depth: rng.Intn(45)}
+			shuffled := categorized(categories...)
+			assert.Equal(t, greedyDiversity(policy, shuffled), productIDs(policy.Apply(shuffled)))
+		}
+	})
+
+	t.Run("Large Catalog", func(t *testing.T) {
+		categories := make([]string, 20000)
+		for i := range categories {
+			categories[i] = []string{"a", "a", "a", "b", "c"}[i%5]
+		}
+		start := time.Now()
+		reranked := catalog.DiversityPolicy{GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 3}.
+			Apply(categorized(categories...))
+		assert.Len(t, reranked, len(categories))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Brand Attribute", func(t *testing.T) {
+		branded := generateLargeProductCollection(3)
+		for i, brand := range []string{"acme", "acme", "globex"} {
+			branded[i].Attributes = catalog.Attributes{"brand": catalog.StringAttribute(brand)}
+		}
+		policy := catalog.DiversityPolicy{GroupBy: catalog.GroupByBrand, MaxPerWindow: 1, Window: 2}
+		assert.Equal(t, []catalog.ProductID{1, 3, 2}, productIDs(policy.Apply(branded)))
+	})
+}
+
+// greedyDiversity is the definition of DiversityPolicy.Apply: each position
+// takes the first remaining product that keeps the constraint, or the first
+// remaining product when none does
+func greedyDiversity(policy catalog.DiversityPolicy, sorted catalog.ProductCollection) []catalog.ProductID {
+	depth := len(sorted)
+	if policy.Depth > 0 && policy.Depth < depth {
+		depth = policy.Depth
+	}
+
+	remaining := sorted.Copy()
+	var placed catalog.ProductCollection
+	for len(placed) < depth {
+		pick := 0
+		for i, candidate := range remaining {
+			group := policy.Group(candidate)
+			count := 0
+			for j := len(placed) - 1; j >= 0 && j > len(placed)-policy.Window; j-- {
+				if policy.Group(placed[j]) == group {
+					count++
+				}
+			}
+			if group == "" || count < policy.MaxPerWindow {
+				pick = i
+				break
+			}
+		}
+		placed = append(placed, remaining[pick])
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+	return productIDs(append(placed, remaining...))
+}
+
+func TestService_SortWithDiversity(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := categorized("a", "a", "a", "b", "b", "c")
+	options := catalog.SortOptions{Diversity: &catalog.DiversityPolicy{
+		GroupBy: catalog.GroupByCategory, MaxPerWindow: 1, Window: 2}}
+
+	plain, err := service.SortProducts(context.Background(), products, catalog.SortByName)
+	require.NoError(t, err)
+
+	diverse, err := service.SortProductsWithOptions(context.Background(), products, catalog.SortByName, options)
+	require.NoError(t, err)
+	require.NoError(t, diverse.Validate())
+	assert.ElementsMatch(t, productIDs(plain.Products), productIDs(diverse.Products))
+	for i := 1; i < len(diverse.Products); i++ {
+		assert.NotEqual(t, diverse.Products[i-1].Category, diverse.Products[i].Category)
+	}
+
+	options.Diversity.Window = 0
+	_, err = service.SortProductsWithOptions(context.Background(), products, catalog.SortByName, options)
+	assert.Error(t, err)
+}
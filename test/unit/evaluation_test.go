@@ -0,0 +1,189 @@
+package unit
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/evaluation"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+func TestEvaluateRanking(t *testing.T) {
+	t.Run("Binary Gains", func(t *testing.T) {
+		queries := []catalog.RelevanceQuery{{ID: "q1", Gains: map[catalog.ProductID]float64{1: 1, 3: 1}}}
+		metrics := catalog.EvaluateRanking(ranking(catalog.SortByPopularity, 1, 2, 3, 4).Products, queries, 2)
+
+		// DCG@2 = 1; IDCG@2 = 1 + 1/log2(3)
+		assert.InDelta(t, 1/(1+1/math.Log2(3)), metrics.NDCG, 1e-9)
+		assert.InDelta(t, (1+2.0/3)/2, metrics.MAP, 1e-9)
+		assert.Equal(t, 1.0, metrics.MRR)
+		assert.Equal(t, 0.5, metrics.Precision)
+	})
+
+	t.Run("Graded Gains", func(t *testing.T) {
+		queries := []catalog.RelevanceQuery{{ID: "q1", Gains: map[catalog.ProductID]float64{1: 1, 2: 3}}}
+		metrics := catalog.EvaluateRanking(ranking(catalog.SortByPopularity, 1, 2).Products, queries, 2)
+
+		dcg := 1 + 7/math.Log2(3)
+		idcg := 7 + 1/math.Log2(3)
+		assert.InDelta(t, dcg/idcg, metrics.NDCG, 1e-9)
+	})
+
+	t.Run("Perfect Ranking", func(t *testing.T) {
+		queries := []catalog.RelevanceQuery{{ID: "q1", Gains: map[catalog.ProductID]float64{1: 2, 2: 1}}}
+		metrics := catalog.EvaluateRanking(ranking(catalog.SortByPopularity, 1, 2, 3).Products, queries, 3)
+		assert.InDelta(t, 1.0, metrics.NDCG, 1e-9)
+		assert.InDelta(t, 1.0, metrics.MAP, 1e-9)
+		assert.InDelta(t, 2.0/3, metrics.Precision, 1e-9)
+	})
+
+	t.Run("Averages Queries", func(t *testing.T) {
+		queries := []catalog.RelevanceQuery{
+			{ID: "q1", Gains: map[catalog.ProductID]float64{1: 1}},
+			{ID: "q2", Gains: map[catalog.ProductID]float64{2: 1}},
+		}
+		metrics := catalog.EvaluateRanking(ranking(catalog.SortByPopularity, 1, 2).Products, queries, 1)
+		assert.Equal(t, 0.5, metrics.NDCG)
+		assert.Equal(t, 0.75, metrics.MRR)
+		assert.Equal(t, 0.5, metrics.Precision)
+	})
+
+	t.Run("Purchase Sessions", func(t *testing.T) {
+		judgments := catalog.Judgments{Sessions: []catalog.PurchaseSession{{ID: "s1", Purchases: []catalog.ProductID{3, 3}}}}
+		require.NoError(t, judgments.Validate())
+
+		queries := judgments.RelevanceQueries()
+		require.Len(t, queries, 1)
+		assert.Equal(t, 2.0, queries[0].Gains[3])
+
+		metrics := catalog.EvaluateRanking(ranking(catalog.SortByPopularity, 1, 2, 3).Products, queries, 2)
+		assert.Equal(t, 0.0, metrics.NDCG)
+		assert.InDelta(t, 1.0/3, metrics.MRR, 1e-9)
+		assert.InDelta(t, 1.0/3, metrics.MAP, 1e-9)
+	})
+}
+
+func TestJudgmentsValidate(t *testing.T) {
+	assert.Error(t, catalog.Judgments{}.Validate())
+	assert.Error(t, catalog.Judgments{Queries: []catalog.RelevanceQuery{
+		{ID: "q1", Gains: map[catalog.ProductID]float64{1: -1}},
+	}}.Validate())
+	assert.Error(t, catalog.Judgments{Queries: []catalog.RelevanceQuery{
+		{ID: "q1", Gains: map[catalog.ProductID]float64{1: 0}},
+	}}.Validate())
+	assert.Error(t, catalog.Judgments{Queries: []catalog.RelevanceQuery{
+		{ID: "q1", Gains: map[catalog.ProductID]float64{1: catalog.MaxRelevanceGain + 1}},
+	}}.Validate())
+	assert.NoError(t, catalog.Judgments{Queries: []catalog.RelevanceQuery{
+		{ID: "q1", Gains: map[catalog.ProductID]float64{1: catalog.MaxRelevanceGain}},
+	}}.Validate())
+	assert.Error(t, catalog.Judgments{
+		Queries:  []catalog.RelevanceQuery{{ID: "q1", Gains: map[catalog.ProductID]float64{1: 1}}},
+		Sessions: []catalog.PurchaseSession{{ID: "q1", Purchases: []catalog.ProductID{2}}},
+	}.Validate())
+}
+
+func TestEvaluateBatch(t *testing.T) {
+	batch := rankingBatch(map[catalog.SortStrategy][]catalog.ProductID{
+		catalog.SortByPriceAsc:   {1, 2, 3},
+		catalog.SortByPopularity: {2, 1, 3},
+		catalog.SortByRevenue:    {3, 2, 1},
+	})
+	products := batch.Results[catalog.SortByPriceAsc].Products
+	judgments := catalog.Judgments{Queries: []catalog.RelevanceQuery{{ID: "q1", Gains: map[catalog.ProductID]float64{1: 1}}}}
+
+	report, err := catalog.EvaluateBatch(batch, products, judgments, 0)
+	require.NoError(t, err)
+	assert.Equal(t, catalog.DefaultEvaluationK, report.K)
+	assert.Equal(t, 1, report.Queries)
+
+	require.Len(t, report.Leaderboard, 3)
+	assert.Equal(t, catalog.SortByPriceAsc, report.Leaderboard[0].Strategy)
+	assert.Equal(t, catalog.SortByPopularity, report.Leaderboard[1].Strategy)
+	assert.Equal(t, catalog.SortByRevenue, report.Leaderboard[2].Strategy)
+	assert.Equal(t, 3, report.Leaderboard[2].Rank)
+	assert.InDelta(t, 0.5, report.Leaderboard[2].NDCG, 1e-9)
+
+	entry, ok := report.Entry(catalog.SortByPopularity)
+	require.True(t, ok)
+	assert.Equal(t, 2, entry.Rank)
+
+	t.Run("Comparable Across Runs", func(t *testing.T) {
+		again, err := catalog.EvaluateBatch(batch, products, judgments, 0)
+		require.NoError(t, err)
+		assert.True(t, report.Comparable(again))
+		assert.Equal(t, report.Leaderboard, again.Leaderboard)
+
+		otherK, err := catalog.EvaluateBatch(batch, products, judgments, 2)
+		require.NoError(t, err)
+		assert.False(t, report.Comparable(otherK))
+
+		otherJudgments := catalog.Judgments{Queries: []catalog.RelevanceQuery{{ID: "q1", Gains: map[catalog.ProductID]float64{2: 1}}}}
+		other, err := catalog.EvaluateBatch(batch, products, otherJudgments, 0)
+		require.NoError(t, err)
+		assert.False(t, report.Comparable(other))
+	})
+
+	t.Run("Negative K", func(t *testing.T) {
+		_, err := catalog.EvaluateBatch(batch, products, judgments, -1)
+		assert.Error(t, err)
+	})
+}
+
+func TestService_EvaluateStrategies(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := generateLargeProductCollection(50)
+	judgments := catalog.Judgments{Sessions: []catalog.PurchaseSession{
+		{ID: "s1", Purchases: []catalog.ProductID{products[0].ID, products[5].ID}},
+		{ID: "s2", Purchases: []catalog.ProductID{products[10].ID}},
+	}}
+
+	strategies := catalog.SortStrategySet{catalog.SortByPopularity, catalog.SortByRevenue, catalog.SortByPriceAsc}
+	report, err := service.EvaluateStrategies(context.Background(), products, strategies, judgments, 5)
+	require.NoError(t, err)
+	assert.Len(t, report.Leaderboard, 3)
+	assert.Equal(t, 2, report.Queries)
+	assert.Equal(t, products.Hash(), report.CatalogHash)
+
+	_, err = service.EvaluateStrategies(context.Background(), products, strategies, catalog.Judgments{}, 5)
+	assert.Error(t, err)
+}
+
+func TestEvaluationFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "judgments.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"queries": [{"id": "q1", "gains": {"1": 3, "2": 1}}],
+		"sessions": [{"id": "s1", "purchases": [3, 3]}]
+	}`), 0o644))
+
+	judgments, err := evaluation.LoadJudgments(path)
+	require.NoError(t, err)
+	require.Len(t, judgments.Queries, 1)
+	assert.Equal(t, 3.0, judgments.Queries[0].Gains[1])
+	assert.Equal(t, []catalog.ProductID{3, 3}, judgments.Sessions[0].Purchases)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"queries": [{"id": "q1", "gains": {}}]}`), 0o644))
+	_, err = evaluation.LoadJudgments(invalid)
+	assert.Error(t, err)
+
+	batch := rankingBatch(map[catalog.SortStrategy][]catalog.ProductID{catalog.SortByPriceAsc: {1, 2, 3}})
+	report, err := catalog.EvaluateBatch(batch, batch.Results[catalog.SortByPriceAsc].Products, judgments, 2)
+	require.NoError(t, err)
+
+	reportPath := filepath.Join(dir, "report.json")
+	require.NoError(t, evaluation.SaveReport(reportPath, report))
+	loaded, err := evaluation.LoadReport(reportPath)
+	require.NoError(t, err)
+	assert.True(t, report.Comparable(loaded))
+	assert.Equal(t, report.Leaderboard, loaded.Leaderboard)
+}
@@ -0,0 +1,196 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/exchange"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// staticRateProvider serves a fixed snapshot
+type staticRateProvider struct {
+	snapshot *catalog.RateSnapshot
+}
+
+func (p staticRateProvider) Snapshot(ctx context.Context) (*catalog.RateSnapshot, error) {
+	return p.snapshot, nil
+}
+
+func testRateSnapshot() *catalog.RateSnapshot {
+	return &catalog.RateSnapshot{
+		ID:    "test-rates",
+		Base:  catalog.USD,
+		Rates: map[catalog.Currency]float64{catalog.EUR: 0.5, catalog.GBP: 0.25, catalog.JPY: 150},
+		AsOf:  time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRateSnapshot_Convert(t *testing.T) {
+	snapshot := testRateSnapshot()
+
+	tests := []struct {
+		name     string
+		amount   catalog.Money
+		target   catalog.Currency
+		expected catalog.Money
+	}{
+		{"Base To Quote", catalog.NewMoney(1000, catalog.USD), catalog.EUR, catalog.NewMoney(500, catalog.EUR)},
+		{"Quote To Base", catalog.NewMoney(500, catalog.GBP), catalog.USD, catalog.NewMoney(2000, catalog.USD)},
+		{"Cross Rate", catalog.NewMoney(100, catalog.EUR), catalog.GBP, catalog.NewMoney(50, catalog.GBP)},
+		{"Zero Decimal Target", catalog.NewMoney(199, catalog.USD), catalog.JPY, catalog.NewMoney(299, catalog.JPY)},
+		{"Same Currency", catalog.NewMoney(123, catalog.EUR), catalog.EUR, catalog.NewMoney(123, catalog.EUR)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := snapshot.Convert(tt.amount, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, converted)
+		})
+	}
+
+	t.Run("Missing Rate", func(t *testing.T) {
+		_, err := snapshot.Convert(catalog.NewMoney(100, "CHF"), catalog.USD)
+		assert.ErrorIs(t, err, catalog.ErrRateUnavailable)
+	})
+
+	t.Run("Invalid Snapshot", func(t *testing.T) {
+		invalid := testRateSnapshot()
+		invalid.Rates[catalog.EUR] = 0
+		_, err := catalog.NewCurrencyConversion(invalid, catalog.USD)
+		assert.Error(t, err)
+	})
+}
+
+func TestProductCollection_TotalRevenueIn(t *testing.T) {
+	conversion, err := catalog.NewCurrencyConversion(testRateSnapshot(), catalog.USD)
+	require.NoError(t, err)
+
+	products := catalog.ProductCollection{
+		{ID: 1, Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 2}, // $20
+		{ID: 2, Price: catalog.NewMoney(500, catalog.EUR), SalesCount: 3},  // €15 = $30
+		{ID: 3, Price: catalog.NewMoney(250, catalog.GBP), SalesCount: 4},  // £10 = $40
+	}
+
+	total, err := products.TotalRevenueIn(conversion)
+	require.NoError(t, err)
+	assert.Equal(t, catalog.NewMoney(9000, catalog.USD), total)
+
+	products = append(products, catalog.Product{ID: 4, Price: catalog.NewMoney(100, "CHF"), SalesCount: 1})
+	_, err = products.TotalRevenueIn(conversion)
+	assert.ErrorIs(t, err, catalog.ErrRateUnavailable)
+}
+
+func TestService_SortProductsInCurrency(t *testing.T) {
+	ctx := context.Background()
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(),
+		catalog.WithExchangeRates(staticRateProvider{snapshot: testRateSnapshot()}))
+
+	now := time.Now()
+	products := catalog.ProductCollection{
+		{ID: 1, Name: "Dollar", Price: catalog.NewMoney(3000, catalog.USD), CreatedAt: now, SalesCount: 10, ViewsCount: 100}, // $30
+		{ID: 2, Name: "Euro", Price: catalog.NewMoney(2000, catalog.EUR), CreatedAt: now, SalesCount: 10, ViewsCount: 100},   // $40
+		{ID: 3, Name: "Pound", Price: catalog.NewMoney(500, catalog.GBP), CreatedAt: now, SalesCount: 10, ViewsCount: 100},   // $20
+	}
+
+	t.Run("Price Ascending", func(t *testing.T) {
+		result, err := service.SortProductsInCurrency(ctx, products, catalog.SortByPriceAsc, catalog.USD)
+		require.NoError(t, err)
+
+		assert.Equal(t, []catalog.ProductID{3, 1, 2}, productIDs(result.Products))
+		assert.Equal(t, catalog.USD, result.Currency)
+		assert.Equal(t, "test-rates", result.RateSnapshot)
+	})
+
+	t.Run("Revenue", func(t *testing.T) {
+		result, err := service.SortProductsInCurrency(ctx, products, catalog.SortByRevenue, catalog.GBP)
+		require.NoError(t, err)
+
+		assert.Equal(t, []catalog.ProductID{2, 1, 3}, productIDs(result.Products))
+		assert.Equal(t, catalog.GBP, result.Currency)
+	})
+
+	t.Run("Original Prices Are Kept", func(t *testing.T) {
+		result, err := service.SortProductsInCurrency(ctx, products, catalog.SortByPriceDesc, catalog.USD)
+		require.NoError(t, err)
+		assert.Equal(t, catalog.NewMoney(2000, catalog.EUR), result.Products[0].Price)
+	})
+
+	t.Run("Unconvertible Product", func(t *testing.T) {
+		mixed := append(products.Copy(), catalog.Product{
+			ID: 4, Name: "Franc", Price: catalog.NewMoney(100, "CHF"), CreatedAt: now, SalesCount: 1, ViewsCount: 10,
+		})
+		_, err := service.SortProductsInCurrency(ctx, mixed, catalog.SortByPriceAsc, catalog.USD)
+		assert.ErrorIs(t, err, catalog.ErrRateUnavailable)
+	})
+
+	t.Run("No Provider", func(t *testing.T) {
+		plain := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+		_, err := plain.SortProductsInCurrency(ctx, products, catalog.SortByPriceAsc, catalog.USD)
+		assert.Error(t, err)
+	})
+
+	t.Run("Plain Sort Reports No Currency", func(t *testing.T) {
+		result, err := service.SortProducts(ctx, products, catalog.SortByPriceAsc)
+		require.NoError(t, err)
+		assert.Empty(t, result.Currency)
+		assert.Empty(t, result.RateSnapshot)
+	})
+}
+
+func TestFileRateProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	t.Run("Explicit ID", func(t *testing.T) {
+		path := filepath.Join(dir, "rates.json")
+		require.NoError(t, os.WriteFile(path, []byte(
+			`{"id":"2024-06-01","base":"USD","as_of":"2024-06-01T00:00:00Z","rates":{"EUR":0.92,"GBP":0.79}}`), 0o644))
+
+		provider, err := exchange.NewFileRateProvider(path)
+		require.NoError(t, err)
+
+		snapshot, err := provider.Snapshot(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-06-01", snapshot.ID)
+		assert.Equal(t, 0.92, snapshot.Rates[catalog.EUR])
+		assert.True(t, snapshot.Supports(catalog.USD))
+	})
+
+	t.Run("Derived ID Changes On Reload", func(t *testing.T) {
+		path := filepath.Join(dir, "derived.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"base":"EUR","rates":{"USD":1.08}}`), 0o644))
+
+		provider, err := exchange.NewFileRateProvider(path)
+		require.NoError(t, err)
+		first, err := provider.Snapshot(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, first.ID, "sha256:")
+		assert.False(t, first.AsOf.IsZero())
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"base":"EUR","rates":{"USD":1.10}}`), 0o644))
+		require.NoError(t, provider.Reload())
+		second, err := provider.Snapshot(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+	})
+
+	t.Run("Invalid Files", func(t *testing.T) {
+		_, err := exchange.NewFileRateProvider(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+
+		path := filepath.Join(dir, "negative.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"base":"USD","rates":{"EUR":-1}}`), 0o644))
+		_, err = exchange.NewFileRateProvider(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,340 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/experiment"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// capturingPublisher records published alerts
+type capturingPublisher struct {
+	mu     sync.Mutex
+	alerts []catalog.PerformanceAlertEvent
+}
+
+func (p *capturingPublisher) PublishSortCompleted(ctx context.Context, event catalog.SortCompletedEvent) error {
+	return nil
+}
+
+func (p *capturingPublisher) PublishBatchCompleted(ctx context.Context, event catalog.BatchCompletedEvent) error {
+	return nil
+}
+
+func (p *capturingPublisher) PublishPerformanceAlert(ctx context.Context, event catalog.PerformanceAlertEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.alerts = append(p.alerts, event)
+	return nil
+}
+
+// variantEvents builds an impression for each of units users of a variant
+// and a purchase of 10.00 USD from the first converters of them
+func variantEvents(experimentName, variant string, units, converters int) []catalog.ExperimentEvent {
+	var events []catalog.ExperimentEvent
+	for i := 0; i < units; i++ {
+		unit := fmt.Sprintf("%s-user-%d", variant, i)
+		events = append(events, catalog.ExperimentEvent{
+			Experiment: experimentName, Variant: variant, Type: catalog.OutcomeImpression, UnitID: unit,
+		})
+		if i < converters {
+			events = append(events, catalog.ExperimentEvent{
+				Experiment: experimentName, Variant: variant, Type: catalog.OutcomePurchase, UnitID: unit,
+				ProductID: 1, Revenue: catalog.NewMoney(1000, catalog.USD),
+			})
+		}
+	}
+	return events
+}
+
+func TestOutcomeTracker(t *testing.T) {
+	tracker := catalog.NewOutcomeTracker()
+	event := func(variant string, outcome catalog.OutcomeType, unit string, cents int64) catalog.ExperimentEvent {
+		return catalog.ExperimentEvent{Experiment: "ranking", Variant: variant, Type: outcome, UnitID: unit,
+			Revenue: catalog.NewMoney(cents, catalog.USD)}
+	}
+
+	require.NoError(t, tracker.Record(
+		event("control", catalog.OutcomeImpression, "a", 0),
+		event("control", catalog.OutcomeImpression, "a", 0),
+		event("control", catalog.OutcomeImpression, "b", 0),
+		event("control", catalog.OutcomeClick, "a", 0),
+		event("control", catalog.OutcomePurchase, "a", 2500),
+		event("control", catalog.OutcomePurchase, "a", 500),
+		// Purchases from units never shown the variant do not convert
+		event("control", catalog.OutcomePurchase, "z", 100),
+		event("revenue", catalog.OutcomeImpression, "c", 0),
+	))
+
+	outcomes := tracker.Outcomes("ranking")
+	require.Len(t, outcomes, 2)
+	assert.Equal(t, catalog.VariantOutcome{
+		Variant: "control", Units: 2, Converters: 1, Impressions: 3, Clicks: 1, Purchases: 3,
+		Revenue: catalog.NewMoney(3100, catalog.USD),
+	}, outcomes[0])
+	assert.Equal(t, "revenue", outcomes[1].Variant)
+	assert.Equal(t, catalog.NewMoney(0, catalog.USD), outcomes[1].Revenue)
+
+	t.Run("Rejects Batches Atomically", func(t *testing.T) {
+		err := tracker.Record(
+			event("revenue", catalog.OutcomeImpression, "d", 0),
+			catalog.ExperimentEvent{Experiment: "ranking", Variant: "revenue", Type: catalog.OutcomePurchase,
+				UnitID: "d", Revenue: catalog.NewMoney(100, catalog.EUR)},
+		)
+		assert.ErrorIs(t, err, catalog.ErrCurrencyMismatch)
+		assert.Equal(t, 1, tracker.Outcomes("ranking")[1].Units)
+	})
+
+	t.Run("Invalid Events", func(t *testing.T) {
+		assert.Error(t, tracker.Record(event("control", "view", "a", 0)))
+		assert.Error(t, tracker.Record(event("control", catalog.OutcomeClick, "a", 100)))
+		assert.Error(t, tracker.Record(event("control", catalog.OutcomeImpression, "", 0)))
+		assert.Error(t, tracker.Record(event("", catalog.OutcomeImpression, "a", 0)))
+	})
+}
+
+func TestBuildExperimentReport(t *testing.T) {
+	definition := splitExperiment("ranking", 50)
+	outcomes := []catalog.VariantOutcome{
+		{Variant: "control", Units: 1000, Converters: 100, Impressions: 2000, Clicks: 400, Revenue: catalog.NewMoney(100000, catalog.USD)},
+		{Variant: "revenue", Units: 1000, Converters: 130, Impressions: 2000, Clicks: 500, Revenue: catalog.NewMoney(150000, catalog.USD)},
+	}
+
+	report, err := catalog.BuildExperimentReport(definition, outcomes, catalog.ReportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "control", report.Control)
+	assert.Equal(t, catalog.DefaultConfidenceLevel, report.ConfidenceLevel)
+
+	require.Len(t, report.Variants, 2)
+	control := report.Variants[0]
+	assert.Equal(t, catalog.SortByPopularity, control.Strategy)
+	assert.InDelta(t, 0.1, control.ConversionRate, 1e-12)
+	assert.InDelta(t, 0.2, control.ClickThroughRate, 1e-12)
+	assert.InDelta(t, 0.5, control.RevenuePerImpression, 1e-12)
+	assert.InDelta(t, 0.082909, control.ConversionInterval.Lower, 1e-6)
+	assert.InDelta(t, 0.120152, control.ConversionInterval.Upper, 1e-6)
+
+	require.Len(t, report.Comparisons, 1)
+	comparison := report.Comparisons[0]
+	assert.Equal(t, catalog.SortByRevenue, comparison.Strategy)
+	assert.InDelta(t, 0.03, comparison.Difference, 1e-12)
+	assert.InDelta(t, 0.3, comparison.Lift, 1e-12)
+	assert.InDelta(t, 2.102741, comparison.ZScore, 1e-6)
+	assert.InDelta(t, 0.035488, comparison.PValue, 1e-6)
+	assert.True(t, comparison.Significant)
+	assert.Less(t, comparison.DifferenceInterval.Lower, comparison.Difference)
+	assert.Greater(t, comparison.DifferenceInterval.Lower, 0.0)
+
+	// A 10% relative lift on a 10% baseline needs 14751 units per variant
+	assert.Equal(t, 14751, report.RequiredSampleSize)
+	assert.False(t, report.SampleSizeReached())
+	require.Len(t, report.Warnings, 2)
+	assert.Contains(t, report.Warnings[0], "1000 of the 14751 units")
+	assert.Empty(t, report.Alerts())
+
+	t.Run("Stricter Confidence", func(t *testing.T) {
+		strict, err := catalog.BuildExperimentReport(definition, outcomes, catalog.ReportOptions{ConfidenceLevel: 0.99})
+		require.NoError(t, err)
+		assert.False(t, strict.Comparisons[0].Significant)
+		assert.Greater(t, strict.RequiredSampleSize, report.RequiredSampleSize)
+	})
+
+	t.Run("No Data", func(t *testing.T) {
+		empty, err := catalog.BuildExperimentReport(definition, nil, catalog.ReportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, empty.Comparisons[0].PValue)
+		assert.False(t, empty.Comparisons[0].Significant)
+		assert.Equal(t, 0, empty.RequiredSampleSize)
+		assert.Contains(t, empty.Warnings[0], "no conversions")
+	})
+
+	t.Run("Control Always Converts", func(t *testing.T) {
+		saturated, err := catalog.BuildExperimentReport(definition, []catalog.VariantOutcome{
+			{Variant: "control", Units: 50, Converters: 50},
+			{Variant: "revenue", Units: 50, Converters: 45},
+		}, catalog.ReportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, saturated.RequiredSampleSize)
+		require.NotEmpty(t, saturated.Warnings)
+		assert.Contains(t, saturated.Warnings[0], "every unit of control control converted")
+		assert.NotContains(t, saturated.Warnings[0], "no conversions")
+	})
+
+	t.Run("Invalid Options", func(t *testing.T) {
+		_, err := catalog.BuildExperimentReport(definition, outcomes, catalog.ReportOptions{ConfidenceLevel: 1.5})
+		assert.Error(t, err)
+		_, err = catalog.BuildExperimentReport(definition, outcomes, catalog.ReportOptions{Power: -0.1})
+		assert.Error(t, err)
+	})
+}
+
+func TestService_ExperimentReport(t *testing.T) {
+	ctx := context.Background()
+	store, err := experiment.NewFileStore(filepath.Join(t.TempDir(), "experiments.json"))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, splitExperiment("ranking", 50)))
+
+	publisher := &capturingPublisher{}
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(),
+		catalog.WithExperiments(store), catalog.WithEventPublisher(publisher))
+
+	assert.ErrorIs(t, service.RecordExperimentEvents(ctx, variantEvents("missing", "control", 1, 0)), catalog.ErrExperimentNotFound)
+	assert.Error(t, service.RecordExperimentEvents(ctx, variantEvents("ranking", "unknown", 1, 0)))
+
+	require.NoError(t, service.RecordExperimentEvents(ctx, variantEvents("ranking", "control", 20000, 2000)))
+	require.NoError(t, service.RecordExperimentEvents(ctx, variantEvents("ranking", "revenue", 20000, 2400)))
+
+	report, err := service.ExperimentReport(ctx, "ranking", catalog.ReportOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.SampleSizeReached())
+	assert.Empty(t, report.Warnings)
+	assert.True(t, report.Comparisons[0].Significant)
+	assert.Equal(t, catalog.NewMoney(2400*1000, catalog.USD), report.Variants[1].Revenue)
+
+	require.Len(t, publisher.alerts, 1)
+	alert := publisher.alerts[0]
+	assert.Equal(t, catalog.AlertExperimentWinner, alert.AlertType)
+	assert.Equal(t, "info", alert.Severity)
+	assert.Equal(t, "revenue", alert.Metadata["variant"])
+	assert.Equal(t, string(catalog.SortByRevenue), alert.Metadata["strategy"])
+
+	// Alerts are raised once
+	_, err = service.ExperimentReport(ctx, "ranking", catalog.ReportOptions{})
+	require.NoError(t, err)
+	assert.Len(t, publisher.alerts, 1)
+
+	t.Run("HTTP", func(t *testing.T) {
+		handler := httpapi.NewHandler(service, zap.NewNop())
+		var body bytes.Buffer
+		for _, event := range variantEvents("ranking", "control", 3, 1) {
+			require.NoError(t, json.NewEncoder(&body).Encode(event))
+		}
+		request := httptest.NewRequest(http.MethodPost, "/v1/experiments/events", &body)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+		assert.JSONEq(t, `{"recorded": 4}`, recorder.Body.String())
+
+		request = httptest.NewRequest(http.MethodGet, "/v1/experiments/report?experiment=ranking&confidence=0.9", nil)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var decoded catalog.ExperimentReport
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&decoded))
+		assert.Equal(t, 0.9, decoded.ConfidenceLevel)
+		// The three new control users are fresh units
+		assert.Equal(t, 20000, decoded.Variants[0].Units)
+
+		request = httptest.NewRequest(http.MethodGet, "/v1/experiments/report?experiment=missing", nil)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestService_ExperimentEventLog(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := experiment.NewFileStore(filepath.Join(dir, "experiments.json"))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, splitExperiment("ranking", 50)))
+
+	newService := func() catalog.Service {
+		log, err := experiment.NewEventLog(filepath.Join(dir, "events.jsonl"), zap.NewNop())
+		require.NoError(t, err)
+		return catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(),
+			catalog.WithExperiments(store), catalog.WithExperimentEventLog(log))
+	}
+
+	service := newService()
+	require.NoError(t, service.RecordExperimentEvents(ctx, variantEvents("ranking", "control", 30, 3)))
+	require.NoError(t, service.RecordExperimentEvents(ctx, variantEvents("ranking", "revenue", 20, 4)))
+
+	// Rejected batches are not logged
+	mixed := variantEvents("ranking", "revenue", 1, 1)
+	mixed[1].Revenue = catalog.NewMoney(1000, catalog.EUR)
+	assert.Error(t, service.RecordExperimentEvents(ctx, mixed))
+
+	// A restarted service rebuilds the outcomes from the log
+	restarted := newService()
+	report, err := restarted.ExperimentReport(ctx, "ranking", catalog.ReportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 30, report.Variants[0].Units)
+	assert.Equal(t, 3, report.Variants[0].Converters)
+	assert.Equal(t, 20, report.Variants[1].Units)
+	assert.Equal(t, catalog.NewMoney(4000, catalog.USD), report.Variants[1].Revenue)
+
+	require.NoError(t, restarted.RecordExperimentEvents(ctx, variantEvents("ranking", "revenue", 25, 4)))
+	report, err = newService().ExperimentReport(ctx, "ranking", catalog.ReportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 25, report.Variants[1].Units)
+	assert.Equal(t, 8, report.Variants[1].Purchases)
+}
+
+func TestEventLog_TornFinalLine(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	log, err := experiment.NewEventLog(path, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, log.Append(ctx, variantEvents("ranking", "control", 2, 0)))
+
+	// A crash mid-write leaves half a line behind
+	tear := func() {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"experiment": "ranking", "variant": "con`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+	}
+
+	t.Run("Read Drops It", func(t *testing.T) {
+		tear()
+		events, err := log.Events(ctx)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(string(data), "}\n"))
+	})
+
+	t.Run("Append Drops It", func(t *testing.T) {
+		tear()
+		require.NoError(t, log.Append(ctx, variantEvents("ranking", "revenue", 1, 0)))
+		events, err := log.Events(ctx)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, "revenue", events[2].Variant)
+	})
+}
+
+func TestReadExperimentEvents(t *testing.T) {
+	events, err := experiment.ReadEvents(strings.NewReader(`
+{"experiment": "ranking", "variant": "control", "type": "impression", "unit_id": "u1"}
+{"experiment": "ranking", "variant": "control", "type": "purchase", "unit_id": "u1", "product_id": 12, "revenue": {"minor_units": 2599, "currency": "USD"}}
+`))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, catalog.OutcomePurchase, events[1].Type)
+	assert.Equal(t, catalog.NewMoney(2599, catalog.USD), events[1].Revenue)
+
+	_, err = experiment.ReadEvents(strings.NewReader(`{"experiment": "ranking", "variant": "control", "type": "view", "unit_id": "u1"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/experiment"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// splitExperiment builds a running experiment splitting traffic between
+// popularity (control) and revenue
+func splitExperiment(name string, controlTraffic float64) catalog.Experiment {
+	return catalog.Experiment{
+		Name: name,
+		Salt: "salt-1",
+		Variants: []catalog.ExperimentVariant{
+			{Name: "control", Strategy: catalog.SortByPopularity, Traffic: controlTraffic},
+			{Name: "revenue", Strategy: catalog.SortByRevenue, Traffic: 100 - controlTraffic},
+		},
+	}
+}
+
+func TestExperimentValidate(t *testing.T) {
+	require.NoError(t, splitExperiment("ranking", 50).Validate())
+	require.NoError(t, splitExperiment("ranking", 33.33).Validate())
+
+	tests := map[string]func(*catalog.Experiment){
+		"Empty Name":         func(e *catalog.Experiment) { e.Name = "" },
+		"Empty Salt":         func(e *catalog.Experiment) { e.Salt = "" },
+		"One Variant":        func(e *catalog.Experiment) { e.Variants = e.Variants[:1] },
+		"Duplicate Variant":  func(e *catalog.Experiment) { e.Variants[1].Name = "control" },
+		"Invalid Strategy":   func(e *catalog.Experiment) { e.Variants[1].Strategy = "nope" },
+		"Zero Traffic":       func(e *catalog.Experiment) { e.Variants[0].Traffic, e.Variants[1].Traffic = 0, 100 },
+		"Traffic Not 100":    func(e *catalog.Experiment) { e.Variants[0].Traffic = 40 },
+		"Traffic Too Fine":   func(e *catalog.Experiment) { e.Variants[0].Traffic, e.Variants[1].Traffic = 50.005, 49.995 },
+		"Ends Before Starts": func(e *catalog.Experiment) { e.StartsAt, e.EndsAt = time.Now(), time.Now().Add(-time.Hour) },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			definition := splitExperiment("ranking", 50)
+			mutate(&definition)
+			assert.Error(t, definition.Validate())
+		})
+	}
+}
+
+func TestExperimentAssign(t *testing.T) {
+	definition := splitExperiment("ranking", 50)
+	now := time.Now()
+
+	t.Run("Deterministic", func(t *testing.T) {
+		first, err := definition.Assign("user-42", now)
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			again, err := definition.Assign("user-42", now)
+			require.NoError(t, err)
+			assert.Equal(t, first, again)
+		}
+		assert.True(t, first.Active)
+		assert.Equal(t, definition.Bucket("user-42"), first.Bucket)
+	})
+
+	t.Run("Traffic Split", func(t *testing.T) {
+		skewed := splitExperiment("ranking", 20)
+		counts := make(map[string]int)
+		for i := 0; i < 20000; i++ {
+			assignment, err := skewed.Assign(fmt.Sprintf("user-%d", i), now)
+			require.NoError(t, err)
+			counts[assignment.Variant]++
+		}
+		assert.InDelta(t, 4000, counts["control"], 300)
+		assert.InDelta(t, 16000, counts["revenue"], 300)
+	})
+
+	t.Run("Salt Reshuffles", func(t *testing.T) {
+		resalted := definition
+		resalted.Salt = "salt-2"
+		changed := 0
+		for i := 0; i < 1000; i++ {
+			unit := fmt.Sprintf("user-%d", i)
+			if definition.Bucket(unit) != resalted.Bucket(unit) {
+				changed++
+			}
+		}
+		assert.Greater(t, changed, 900)
+	})
+
+	t.Run("Control Outside Dates", func(t *testing.T) {
+		scheduled := definition
+		scheduled.StartsAt = now.Add(time.Hour)
+		for i := 0; i < 100; i++ {
+			assignment, err := scheduled.Assign(fmt.Sprintf("user-%d", i), now)
+			require.NoError(t, err)
+			assert.False(t, assignment.Active)
+			assert.Equal(t, "control", assignment.Variant)
+			assert.Equal(t, catalog.SortByPopularity, assignment.Strategy)
+		}
+
+		ended := definition
+		ended.EndsAt = now.Add(-time.Hour)
+		assert.False(t, ended.IsActiveAt(now))
+	})
+
+	t.Run("Empty Unit", func(t *testing.T) {
+		_, err := definition.Assign("", now)
+		assert.Error(t, err)
+	})
+}
+
+func TestExperimentFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "experiments.json")
+
+	store, err := experiment.NewFileStore(path)
+	require.NoError(t, err)
+	all, err := store.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	definition := splitExperiment("ranking", 50)
+	definition.StartsAt = time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	definition.EndsAt = time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(ctx, definition))
+	require.NoError(t, store.Save(ctx, splitExperiment("another", 10)))
+	assert.Error(t, store.Save(ctx, splitExperiment("", 50)))
+
+	reopened, err := experiment.NewFileStore(path)
+	require.NoError(t, err)
+	found, err := reopened.Find(ctx, "ranking")
+	require.NoError(t, err)
+	assert.Equal(t, definition.Variants, found.Variants)
+	assert.True(t, definition.StartsAt.Equal(found.StartsAt))
+	assert.True(t, definition.EndsAt.Equal(found.EndsAt))
+
+	all, err = reopened.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "another", all[0].Name)
+
+	require.NoError(t, reopened.Delete(ctx, "another"))
+	assert.ErrorIs(t, reopened.Delete(ctx, "another"), catalog.ErrExperimentNotFound)
+	_, err = reopened.Find(ctx, "another")
+	assert.ErrorIs(t, err, catalog.ErrExperimentNotFound)
+}
+
+func TestService_ManageExperiments(t *testing.T) {
+	ctx := context.Background()
+	store, err := experiment.NewFileStore(filepath.Join(t.TempDir(), "experiments.json"))
+	require.NoError(t, err)
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithExperiments(store))
+
+	require.NoError(t, service.SaveExperiment(ctx, splitExperiment("ranking", 50)))
+	require.NoError(t, service.SaveExperiment(ctx, splitExperiment("another", 10)))
+	assert.Error(t, service.SaveExperiment(ctx, splitExperiment("", 50)))
+
+	all, err := service.ListExperiments(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "another", all[0].Name)
+
+	require.NoError(t, service.DeleteExperiment(ctx, "another"))
+	assert.ErrorIs(t, service.DeleteExperiment(ctx, "another"), catalog.ErrExperimentNotFound)
+
+	_, err = catalog.NewService(sorting.NewSorterFactory(), zap.NewNop()).ListExperiments(ctx)
+	assert.Error(t, err)
+}
+
+func TestService_SortForExperiment(t *testing.T) {
+	ctx := context.Background()
+	store, err := experiment.NewFileStore(filepath.Join(t.TempDir(), "experiments.json"))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, splitExperiment("ranking", 50)))
+
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithExperiments(store))
+	products := generateLargeProductCollection(30)
+
+	strategies := make(map[catalog.SortStrategy]bool)
+	for i := 0; i < 50; i++ {
+		unit := fmt.Sprintf("session-%d", i)
+		result, err := service.SortForExperiment(ctx, products, "ranking", unit, catalog.SortOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, result.Experiment)
+		assert.Equal(t, result.Experiment.Strategy, result.Strategy)
+		assert.Equal(t, unit, result.Experiment.UnitID)
+		strategies[result.Strategy] = true
+
+		expected, err := service.SortProducts(ctx, products, result.Strategy)
+		require.NoError(t, err)
+		assert.Equal(t, productIDs(expected.Products), productIDs(result.Products))
+	}
+	assert.Len(t, strategies, 2)
+
+	_, err = service.SortForExperiment(ctx, products, "missing", "session-1", catalog.SortOptions{})
+	assert.ErrorIs(t, err, catalog.ErrExperimentNotFound)
+
+	_, err = catalog.NewService(sorting.NewSorterFactory(), zap.NewNop()).AssignExperiment(ctx, "ranking", "session-1")
+	assert.Error(t, err)
+
+	t.Run("HTTP", func(t *testing.T) {
+		handler := httpapi.NewHandler(service, zap.NewNop())
+		var body bytes.Buffer
+		for _, product := range products {
+			require.NoError(t, json.NewEncoder(&body).Encode(product))
+		}
+		request := httptest.NewRequest(http.MethodPost, "/v1/experiments/sort?experiment=ranking&unit=user-7", &body)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var decoded catalog.SortResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&decoded))
+		require.NotNil(t, decoded.Experiment)
+		assert.Equal(t, "user-7", decoded.Experiment.UnitID)
+
+		request = httptest.NewRequest(http.MethodPost, "/v1/experiments/sort?experiment=missing&unit=user-7", &bytes.Buffer{})
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+func explainTestProducts() catalog.ProductCollection {
+	// Products 1 and 2 both earn $100, product 2 from more sales; product 3 earns $200
+	created := testdata.WithCreatedAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return catalog.ProductCollection{
+		testdata.Product(1, created),
+		testdata.Product(2, created, testdata.WithPrice(500), testdata.WithSales(20)),
+		testdata.Product(3, created, testdata.WithPrice(2000)),
+	}
+}
+
+func TestSorters_Criteria(t *testing.T) {
+	factory := sorting.NewSorterFactory()
+	product := explainTestProducts()[0]
+
+	for _, strategy := range catalog.AllSortStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			sorter, err := factory.CreateSorter(strategy)
+			require.NoError(t, err)
+
+			explaining, ok := sorter.(catalog.ExplainingSorter)
+			require.True(t, ok, "sorter does not explain its ranking")
+
+			criteria := explaining.Criteria(product)
+			require.NotEmpty(t, criteria)
+			assert.Equal(t, "id", criteria[len(criteria)-1].Name)
+		})
+	}
+}
+
+func TestService_Explain(t *testing.T) {
+	ctx := context.Background()
+	products := explainTestProducts()
+
+	t.Run("Tie Breakers", func(t *testing.T) {
+		service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByRevenue,
+			catalog.SortOptions{Explain: true})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{3, 2, 1}, productIDs(result.Products))
+		require.Len(t, result.Explanations, 3)
+
+		second := result.Explanations[1]
+		assert.Equal(t, catalog.ProductID(2), second.ProductID)
+		assert.Equal(t, 2, second.Position)
+		assert.Equal(t, 2, second.SorterPosition)
+		assert.Equal(t, []catalog.RankingCriterion{
+			{Name: "revenue", Value: "$100.00", Descending: true},
+			{Name: "sales_count", Value: "20", Descending: true},
+			{Name: "id", Value: "2"},
+		}, second.Criteria)
+		require.NotNil(t, second.Previous)
+		assert.Equal(t, "revenue", second.Previous.DecidedBy)
+		require.NotNil(t, second.Next)
+		assert.Equal(t, catalog.ProductID(1), second.Next.ProductID)
+		assert.Equal(t, "sales_count", second.Next.DecidedBy)
+		assert.False(t, second.Next.Overridden)
+		assert.Empty(t, second.Adjustments)
+	})
+
+	t.Run("Rule Adjustments", func(t *testing.T) {
+		service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(),
+			catalog.WithMerchandisingRules(staticRules{pinRule("hero", 0, 1, 1)}))
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByRevenue,
+			catalog.SortOptions{Explain: true})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{1, 3, 2}, productIDs(result.Products))
+
+		pinned := result.Explanations[0]
+		assert.Equal(t, 3, pinned.SorterPosition)
+		assert.Equal(t, []catalog.RankAdjustment{{Stage: catalog.StageMerchandising, From: 3, To: 1,
+			RuleIDs: []string{"hero"}}}, pinned.Adjustments)
+		require.NotNil(t, pinned.Next)
+		assert.True(t, pinned.Next.Overridden)
+	})
+
+	t.Run("Off By Default", func(t *testing.T) {
+		service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+		result, err := service.SortProducts(ctx, products, catalog.SortByRevenue)
+		require.NoError(t, err)
+		assert.Nil(t, result.Explanations)
+	})
+}
+
+func TestHTTPHandler_Explain(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	handler := httpapi.NewHandler(service, zap.NewNop())
+
+	var body bytes.Buffer
+	for _, product := range explainTestProducts() {
+		require.NoError(t, json.NewEncoder(&body).Encode(product))
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/explain?strategy=revenue", &body)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result catalog.SortResult
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+	require.Len(t, result.Explanations, 3)
+	assert.Equal(t, "sales_count", result.Explanations[1].Next.DecidedBy)
+
+	request = httptest.NewRequest(http.MethodPost, "/v1/explain?strategy=unknown", bytes.NewReader(nil))
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
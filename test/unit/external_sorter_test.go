@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+func TestExternalSorter_Comprehensive(t *testing.T) {
+	ctx := context.Background()
+	products := generateLargeProductCollection(2000)
+
+	var input bytes.Buffer
+	encoder := json.NewEncoder(&input)
+	for _, product := range products {
+		require.NoError(t, encoder.Encode(product))
+	}
+
+	factory := sorting.NewSorterFactory()
+
+	for _, strategy := range catalog.AllSortStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			sorter, err := factory.CreateSorter(strategy)
+			require.NoError(t, err)
+
+			expected, err := sorter.Sort(ctx, products)
+			require.NoError(t, err)
+
+			// A tiny budget forces many runs and multiple merge passes
+			external, err := sorting.NewExternalSorter(sorter, sorting.ExternalSortConfig{
+				MemoryBudget: 16 << 10,
+				TempDir:      t.TempDir(),
+			})
+			require.NoError(t, err)
+
+			var output bytes.Buffer
+			stats, err := external.Sort(ctx, bytes.NewReader(input.Bytes()), &output)
+			require.NoError(t, err)
+
+			assert.Equal(t, len(products), stats.ProductCount)
+			assert.Greater(t, stats.RunCount, 2)
+			assert.Greater(t, stats.MergePasses, 1)
+
+			actual := decodeJSONLProducts(t, output.String())
+			require.Len(t, actual, len(expected))
+			for i := range expected {
+				assert.Equal(t, expected[i].ID, actual[i].ID, "Position %d", i)
+			}
+		})
+	}
+
+	t.Run("Fits In Memory", func(t *testing.T) {
+		external, err := sorting.NewExternalSorter(sorting.NewPriceSorter(true), sorting.ExternalSortConfig{})
+		require.NoError(t, err)
+
+		var output bytes.Buffer
+		stats, err := external.Sort(ctx, bytes.NewReader(input.Bytes()), &output)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, stats.RunCount)
+		assert.Equal(t, 0, stats.MergePasses)
+		assert.Len(t, decodeJSONLProducts(t, output.String()), len(products))
+	})
+
+	t.Run("Attributes Count Toward Budget", func(t *testing.T) {
+		// Each product carries 16KB of attributes, which the budget must see
+		large := make(catalog.ProductCollection, 256)
+		var largeInput bytes.Buffer
+		largeEncoder := json.NewEncoder(&largeInput)
+		for i := range large {
+			large[i] = products[i]
+			large[i].Attributes = catalog.Attributes{
+				"description": catalog.StringAttribute(strings.Repeat("x", 16<<10)),
+			}
+			require.NoError(t, largeEncoder.Encode(large[i]))
+		}
+
+		external, err := sorting.NewExternalSorter(sorting.NewPriceSorter(true), sorting.ExternalSortConfig{
+			MemoryBudget: 2 << 20,
+			TempDir:      t.TempDir(),
+		})
+		require.NoError(t, err)
+
+		var output bytes.Buffer
+		stats, err := external.Sort(ctx, &largeInput, &output)
+		require.NoError(t, err)
+
+		// Runs of about 1MB are spilled, and a 2MB budget only leaves room
+		// to merge two maximum-size records at a time
+		assert.Greater(t, stats.RunCount, 2)
+		assert.Greater(t, stats.MergePasses, 1)
+		assert.Len(t, decodeJSONLProducts(t, output.String()), len(large))
+	})
+
+	t.Run("Empty Input", func(t *testing.T) {
+		external, err := sorting.NewExternalSorter(sorting.NewNameSorter(), sorting.ExternalSortConfig{})
+		require.NoError(t, err)
+
+		var output bytes.Buffer
+		stats, err := external.Sort(ctx, strings.NewReader(""), &output)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, stats.ProductCount)
+		assert.Empty(t, output.String())
+	})
+
+	t.Run("Invalid Record", func(t *testing.T) {
+		external, err := sorting.NewExternalSorter(sorting.NewNameSorter(), sorting.ExternalSortConfig{})
+		require.NoError(t, err)
+
+		valid := fmt.Sprintf(`{"id":1,"name":"Valid","price":1,"created_at":%q,"sales_count":1,"views_count":2}`,
+			time.Now().Add(-time.Hour).Format(time.RFC3339))
+		input := valid + "\n" + `{"id":0,"name":""}` + "\n"
+
+		_, err = external.Sort(ctx, strings.NewReader(input), &bytes.Buffer{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+
+	t.Run("Negative Budget", func(t *testing.T) {
+		_, err := sorting.NewExternalSorter(sorting.NewNameSorter(), sorting.ExternalSortConfig{MemoryBudget: -1})
+		assert.Error(t, err)
+	})
+
+	t.Run("Nil Sorter", func(t *testing.T) {
+		_, err := sorting.NewExternalSorter(nil, sorting.ExternalSortConfig{})
+		assert.Error(t, err)
+	})
+}
+
+// decodeJSONLProducts parses newline-delimited product JSON
+func decodeJSONLProducts(t *testing.T, data string) catalog.ProductCollection {
+	t.Helper()
+
+	var products catalog.ProductCollection
+	decoder := json.NewDecoder(strings.NewReader(data))
+	for decoder.More() {
+		var product catalog.Product
+		require.NoError(t, decoder.Decode(&product))
+		products = append(products, product)
+	}
+	return products
+}
@@ -0,0 +1,111 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// rankingBatch builds a batch result from fixed rankings of product IDs
+func rankingBatch(rankings map[catalog.SortStrategy][]catalog.ProductID) *catalog.BatchSortResult {
+	results := make(map[catalog.SortStrategy]*catalog.SortResult, len(rankings))
+	for strategy, ids := range rankings {
+		products := make(catalog.ProductCollection, len(ids))
+		for i, id := range ids {
+			products[i] = catalog.Product{ID: id, Name: "Product", Price: catalog.NewMoney(100, catalog.USD), CreatedAt: time.Now()}
+		}
+		results[strategy] = catalog.NewSortResult(products, strategy, 0)
+	}
+	return catalog.NewBatchSortResult(results, 0)
+}
+
+func TestFuseRankings(t *testing.T) {
+	batch := rankingBatch(map[catalog.SortStrategy][]catalog.ProductID{
+		catalog.SortByPriceAsc:   {1, 2, 3, 4},
+		catalog.SortByPopularity: {2, 1, 4, 3},
+		catalog.SortByRevenue:    {2, 3, 1, 4},
+	})
+
+	t.Run("Borda", func(t *testing.T) {
+		result, err := catalog.FuseRankings(batch, catalog.FusionOptions{Method: catalog.FuseBorda})
+		require.NoError(t, err)
+		require.NoError(t, result.Validate())
+		assert.Equal(t, catalog.SortStrategy("fusion:borda"), result.Strategy)
+		assert.Equal(t, []catalog.ProductID{2, 1, 3, 4}, productIDs(result.Products))
+
+		// Product 2: 2 + 3 + 3 points
+		score := result.Fusion.Scores[2]
+		assert.Equal(t, 8.0, score.Score)
+		require.Len(t, score.Contributions, 3)
+		assert.Equal(t, catalog.RankContribution{Strategy: catalog.SortByPopularity, Rank: 1, Weight: 1, Score: 3},
+			score.Contributions[0])
+	})
+
+	t.Run("Weighted RRF", func(t *testing.T) {
+		result, err := catalog.FuseRankings(batch, catalog.FusionOptions{Method: catalog.FuseRRF,
+			RRFConstant: 1, Weights: map[catalog.SortStrategy]float64{catalog.SortByPriceAsc: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{1, 2, 3, 4}, productIDs(result.Products))
+		assert.InDelta(t, 10.0/2+1.0/3+1.0/4, result.Fusion.Scores[1].Score, 1e-9)
+	})
+
+	t.Run("Median", func(t *testing.T) {
+		result, err := catalog.FuseRankings(batch, catalog.FusionOptions{Method: catalog.FuseMedian})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{2, 1, 3, 4}, productIDs(result.Products))
+		assert.Equal(t, 1.0, result.Fusion.Scores[2].Score)
+	})
+
+	t.Run("Missing Products", func(t *testing.T) {
+		partial := rankingBatch(map[catalog.SortStrategy][]catalog.ProductID{
+			catalog.SortByPriceAsc:   {1, 2},
+			catalog.SortByPopularity: {3, 2, 1},
+		})
+		result, err := catalog.FuseRankings(partial, catalog.FusionOptions{Method: catalog.FuseBorda})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []catalog.ProductID{1, 2, 3}, productIDs(result.Products))
+		// Contributions follow strategy name order: popularity, then price_asc
+		assert.Equal(t, 0, result.Fusion.Scores[3].Contributions[1].Rank)
+	})
+
+	t.Run("Invalid Options", func(t *testing.T) {
+		_, err := catalog.FuseRankings(batch, catalog.FusionOptions{Method: "condorcet"})
+		assert.Error(t, err)
+
+		_, err = catalog.FuseRankings(batch, catalog.FusionOptions{Method: catalog.FuseBorda,
+			Weights: map[catalog.SortStrategy]float64{catalog.SortByPriceAsc: -1}})
+		assert.Error(t, err)
+
+		_, err = catalog.FuseRankings(batch, catalog.FusionOptions{Method: catalog.FuseBorda,
+			Weights: map[catalog.SortStrategy]float64{
+				catalog.SortByPriceAsc: 0, catalog.SortByPopularity: 0, catalog.SortByRevenue: 0}})
+		assert.Error(t, err)
+	})
+}
+
+func TestService_FuseStrategies(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := generateLargeProductCollection(20)
+	strategies := catalog.NewSortStrategySet(catalog.SortByPriceAsc, catalog.SortByPopularity)
+
+	result, err := service.FuseStrategies(context.Background(), products, strategies,
+		catalog.FusionOptions{Method: catalog.FuseRRF})
+	require.NoError(t, err)
+	require.NoError(t, result.Validate())
+	assert.Len(t, result.Products, 20)
+	assert.Len(t, result.Fusion.Scores, 20)
+
+	clone := result.Clone()
+	clone.Fusion.Scores[products[0].ID].Contributions[0].Rank = 99
+	assert.NotEqual(t, 99, result.Fusion.Scores[products[0].ID].Contributions[0].Rank)
+
+	_, err = service.FuseStrategies(context.Background(), products, strategies, catalog.FusionOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/jsonfile"
+	"product-catalog-sorting/internal/infrastructure/merchandising"
+)
+
+func TestJSONFileList(t *testing.T) {
+	dir := t.TempDir()
+	ruleID := func(r catalog.MerchandisingRule) string { return r.ID }
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("Reload Keeps Previous Items On Error", func(t *testing.T) {
+		path := write("rules.json", `[{"id":"hero","action":"pin","position":1,"condition":{"ids":[42]}}]`)
+		list, err := jsonfile.NewList(path, "merchandising rules", ruleID)
+		require.NoError(t, err)
+		require.Len(t, list.Items(), 1)
+
+		write("rules.json", `[{"id":"hero","action":"pin","position":0}]`)
+		assert.Error(t, list.Reload())
+		assert.Equal(t, "hero", list.Items()[0].ID)
+
+		write("rules.json", `[]`)
+		require.NoError(t, list.Reload())
+		assert.Empty(t, list.Items())
+	})
+
+	t.Run("Invalid Files", func(t *testing.T) {
+		for name, tt := range map[string]struct{ content, message string }{
+			"malformed.json": {`{"id":"hero"}`, "failed to parse merchandising rules file"},
+			"duplicate.json": {`[{"id":"a","action":"bury","positions":1,"condition":{"ids":[1]}},
+				{"id":"a","action":"bury","positions":2,"condition":{"ids":[2]}}]`, "duplicate ID a"},
+		} {
+			_, err := jsonfile.NewList(write(name, tt.content), "merchandising rules", ruleID)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), tt.message)
+		}
+
+		_, err := jsonfile.NewList("", "merchandising rules", ruleID)
+		assert.EqualError(t, err, "merchandising rules file path cannot be empty")
+		_, err = jsonfile.NewList(filepath.Join(dir, "missing.json"), "merchandising rules", ruleID)
+		assert.Error(t, err)
+	})
+
+	t.Run("File Provider", func(t *testing.T) {
+		ctx := context.Background()
+		rules, err := merchandising.NewFileProvider(write("hero.json",
+			`[{"id":"hero","action":"pin","position":1,"condition":{"ids":[42]}}]`))
+		require.NoError(t, err)
+		loadedRules, err := rules.Rules(ctx)
+		require.NoError(t, err)
+		require.Len(t, loadedRules, 1)
+		assert.Equal(t, catalog.ProductID(42), loadedRules[0].Condition.IDs[0])
+	})
+}
@@ -0,0 +1,158 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/repository"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+// lifecycleTestProducts returns one product per lifecycle status, IDs 1-5
+func lifecycleTestProducts() catalog.ProductCollection {
+	return catalog.ProductCollection{
+		testdata.Product(1, testdata.WithStatus(catalog.StatusUnset)),
+		testdata.Product(2, testdata.WithStatus(catalog.StatusDraft)),
+		testdata.Product(3, testdata.WithStatus(catalog.StatusActive)),
+		testdata.Product(4, testdata.WithStatus(catalog.StatusDiscontinued)),
+		testdata.Product(5, testdata.WithStatus(catalog.StatusArchived)),
+	}
+}
+
+func TestProduct_TransitionTo(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Full Lifecycle", func(t *testing.T) {
+		product := generateLargeProductCollection(1)[0]
+		product.Status = catalog.StatusDraft
+		assert.False(t, product.IsActive())
+
+		require.NoError(t, product.TransitionTo(catalog.StatusActive, at))
+		assert.True(t, product.IsActive())
+		require.NotNil(t, product.ActivatedAt)
+		assert.Equal(t, at, *product.ActivatedAt)
+
+		require.NoError(t, product.TransitionTo(catalog.StatusDiscontinued, at.Add(time.Hour)))
+		require.NoError(t, product.TransitionTo(catalog.StatusArchived, at.Add(2*time.Hour)))
+		assert.Equal(t, catalog.StatusArchived, product.Status)
+		assert.Equal(t, at.Add(time.Hour), *product.DiscontinuedAt)
+		assert.Equal(t, at.Add(2*time.Hour), *product.ArchivedAt)
+	})
+
+	t.Run("Unset Status Is Active", func(t *testing.T) {
+		product := generateLargeProductCollection(1)[0]
+		assert.True(t, product.IsActive())
+		require.NoError(t, product.TransitionTo(catalog.StatusDiscontinued, at))
+	})
+
+	invalid := []struct {
+		from catalog.LifecycleStatus
+		to   catalog.LifecycleStatus
+	}{
+		{catalog.StatusDraft, catalog.StatusDiscontinued},
+		{catalog.StatusActive, catalog.StatusDraft},
+		{catalog.StatusDiscontinued, catalog.StatusActive},
+		{catalog.StatusArchived, catalog.StatusActive},
+		{catalog.StatusActive, catalog.StatusActive},
+		{catalog.StatusActive, "deleted"},
+	}
+	for _, tt := range invalid {
+		t.Run(string(tt.from)+" To "+string(tt.to), func(t *testing.T) {
+			product := catalog.Product{Status: tt.from}
+			err := product.TransitionTo(tt.to, at)
+			assert.ErrorIs(t, err, catalog.ErrInvalidTransition)
+			assert.Equal(t, tt.from, product.Status)
+		})
+	}
+
+	t.Run("Unknown Status Fails Validation", func(t *testing.T) {
+		product := generateLargeProductCollection(1)[0]
+		product.Status = "hidden"
+		assert.Error(t, product.Validate())
+	})
+}
+
+func TestService_LifecycleVisibility(t *testing.T) {
+	ctx := context.Background()
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := lifecycleTestProducts()
+
+	t.Run("Sorts Exclude Inactive By Default", func(t *testing.T) {
+		result, err := service.SortProducts(ctx, products, catalog.SortByName)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []catalog.ProductID{1, 3}, productIDs(result.Products))
+	})
+
+	t.Run("Admin Opt-In", func(t *testing.T) {
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByName,
+			catalog.SortOptions{IncludeInactive: true})
+		require.NoError(t, err)
+		assert.Len(t, result.Products, 5)
+	})
+
+	t.Run("Stream Sort", func(t *testing.T) {
+		var emitted catalog.ProductCollection
+		collect := func(product catalog.Product) error {
+			emitted = append(emitted, product)
+			return nil
+		}
+
+		result, err := service.StreamSort(ctx, catalog.NewCollectionIterator(products), catalog.SortByName,
+			catalog.StreamOptions{}, collect)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.ProductCount)
+		assert.Equal(t, 2, result.EmittedCount)
+
+		emitted = nil
+		_, err = service.StreamSort(ctx, catalog.NewCollectionIterator(products), catalog.SortByName,
+			catalog.StreamOptions{IncludeInactive: true}, collect)
+		require.NoError(t, err)
+		assert.Len(t, emitted, 5)
+	})
+}
+
+func TestMemoryRepository_LifecycleVisibility(t *testing.T) {
+	ctx := context.Background()
+	repo, err := repository.NewMemoryRepository(sorting.NewSorterFactory())
+	require.NoError(t, err)
+
+	for _, product := range lifecycleTestProducts() {
+		product := product
+		require.NoError(t, repo.SaveProduct(ctx, &product))
+	}
+
+	products, err := repo.GetProducts(ctx, catalog.ProductFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []catalog.ProductID{1, 3}, productIDs(products))
+
+	count, err := repo.GetProductCount(ctx, catalog.ProductFilter{IncludeInactive: true})
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	page, err := repo.GetSortedPage(ctx, catalog.SortByPriceAsc, 0, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []catalog.ProductID{1, 3}, productIDs(page))
+
+	t.Run("Transition Updates Index", func(t *testing.T) {
+		draft, err := repo.GetProductByID(ctx, 2)
+		require.NoError(t, err)
+		require.NoError(t, draft.TransitionTo(catalog.StatusActive, time.Now()))
+		require.NoError(t, repo.SaveProduct(ctx, draft))
+
+		active, err := repo.GetProductByID(ctx, 3)
+		require.NoError(t, err)
+		require.NoError(t, active.TransitionTo(catalog.StatusDiscontinued, time.Now()))
+		require.NoError(t, repo.SaveProduct(ctx, active))
+
+		page, err := repo.GetSortedPage(ctx, catalog.SortByPriceAsc, 0, 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []catalog.ProductID{1, 2}, productIDs(page))
+	})
+}
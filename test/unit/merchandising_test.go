@@ -0,0 +1,181 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// staticRules is a fixed MerchandisingRuleProvider for tests
+type staticRules []catalog.MerchandisingRule
+
+func (r staticRules) Rules(ctx context.Context) ([]catalog.MerchandisingRule, error) {
+	return r, nil
+}
+
+var rulesNow = time.Date(2024, 11, 29, 12, 0, 0, 0, time.UTC)
+
+func pinRule(id string, priority, position int, ids ...catalog.ProductID) catalog.MerchandisingRule {
+	return catalog.MerchandisingRule{ID: id, Priority: priority, Action: catalog.ActionPin,
+		Position: position, Condition: catalog.ProductFilter{IDs: ids}}
+}
+
+func applyRules(t *testing.T, products catalog.ProductCollection, rules ...catalog.MerchandisingRule) (catalog.ProductCollection, []catalog.FiredRule) {
+	ruleSet, err := catalog.NewRuleSet(rules, rulesNow)
+	require.NoError(t, err)
+	return ruleSet.Apply(products)
+}
+
+func TestMerchandisingRule_Validate(t *testing.T) {
+	valid := catalog.MerchandisingRule{ID: "boost", Action: catalog.ActionBoost, Positions: 2}
+	require.NoError(t, valid.Validate())
+
+	invalid := map[string]func(r *catalog.MerchandisingRule){
+		"Missing ID":        func(r *catalog.MerchandisingRule) { r.ID = "" },
+		"Unknown Action":    func(r *catalog.MerchandisingRule) { r.Action = "shuffle" },
+		"No Amount":         func(r *catalog.MerchandisingRule) { r.Positions = 0 },
+		"Score And Amount":  func(r *catalog.MerchandisingRule) { r.Score = 0.5 },
+		"Negative Amount":   func(r *catalog.MerchandisingRule) { r.Positions = -1 },
+		"Pin Without Slot":  func(r *catalog.MerchandisingRule) { r.Action = catalog.ActionPin; r.Positions = 0 },
+		"Ends Before Start": func(r *catalog.MerchandisingRule) { r.StartsAt = rulesNow; r.EndsAt = rulesNow.Add(-time.Hour) },
+		"Bad Predicate": func(r *catalog.MerchandisingRule) {
+			r.Condition.Attributes = []catalog.AttributePredicate{{Name: "brand", Op: "like"}}
+		},
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			rule := valid
+			mutate(&rule)
+			assert.Error(t, rule.Validate())
+		})
+	}
+}
+
+func TestRuleSet_Apply(t *testing.T) {
+	products := generateLargeProductCollection(6)
+
+	t.Run("Exclude", func(t *testing.T) {
+		result, fired := applyRules(t, products, catalog.MerchandisingRule{ID: "hide", Action: catalog.ActionExclude,
+			Condition: catalog.ProductFilter{IDs: []catalog.ProductID{2, 5}}})
+		assert.Equal(t, []catalog.ProductID{1, 3, 4, 6}, productIDs(result))
+		assert.Equal(t, []catalog.FiredRule{{RuleID: "hide", Action: catalog.ActionExclude,
+			ProductIDs: []catalog.ProductID{2, 5}}}, fired)
+	})
+
+	t.Run("Boost And Bury By Positions", func(t *testing.T) {
+		result, _ := applyRules(t, products,
+			catalog.MerchandisingRule{ID: "up", Action: catalog.ActionBoost, Positions: 2,
+				Condition: catalog.ProductFilter{IDs: []catalog.ProductID{4, 5}}},
+			catalog.MerchandisingRule{ID: "down", Action: catalog.ActionBury, Positions: 10,
+				Condition: catalog.ProductFilter{IDs: []catalog.ProductID{1}}})
+		// Equal priorities run by ID, so product 1 is buried before the boost
+		assert.Equal(t, []catalog.ProductID{4, 5, 2, 3, 6, 1}, productIDs(result))
+	})
+
+	t.Run("Clamped Boost Keeps Match Order", func(t *testing.T) {
+		result, _ := applyRules(t, products, catalog.MerchandisingRule{ID: "up", Action: catalog.ActionBoost,
+			Positions: 5, Condition: catalog.ProductFilter{IDs: []catalog.ProductID{2, 3}}})
+		assert.Equal(t, []catalog.ProductID{2, 3, 1, 4, 5, 6}, productIDs(result))
+	})
+
+	t.Run("Clamped Bury Keeps Match Order", func(t *testing.T) {
+		result, _ := applyRules(t, products, catalog.MerchandisingRule{ID: "down", Action: catalog.ActionBury,
+			Positions: 5, Condition: catalog.ProductFilter{IDs: []catalog.ProductID{4, 5}}})
+		assert.Equal(t, []catalog.ProductID{1, 2, 3, 6, 4, 5}, productIDs(result))
+	})
+
+	t.Run("Boost By Score", func(t *testing.T) {
+		// A score of 1 lifts matches above every unmatched product
+		result, _ := applyRules(t, products, catalog.MerchandisingRule{ID: "feature", Action: catalog.ActionBoost,
+			Score: 1, Condition: catalog.ProductFilter{IDs: []catalog.ProductID{3, 6}}})
+		assert.Equal(t, []catalog.ProductID{3, 6, 1, 2, 4, 5}, productIDs(result))
+	})
+
+	t.Run("Pins Compete By Priority", func(t *testing.T) {
+		result, fired := applyRules(t, products,
+			pinRule("low", 1, 1, 6),
+			pinRule("high", 5, 1, 5),
+			pinRule("second", 3, 2, 4))
+		// high takes slot 1, second holds slot 2, low shifts down to slot 3
+		assert.Equal(t, []catalog.ProductID{5, 4, 6, 1, 2, 3}, productIDs(result))
+		require.Len(t, fired, 3)
+		assert.Equal(t, "high", fired[0].RuleID)
+	})
+
+	t.Run("Pin Past End", func(t *testing.T) {
+		result, _ := applyRules(t, products, pinRule("far", 0, 50, 1))
+		assert.Equal(t, []catalog.ProductID{2, 3, 4, 5, 6, 1}, productIDs(result))
+	})
+
+	t.Run("Pin Far Past End", func(t *testing.T) {
+		start := time.Now()
+		result, _ := applyRules(t, products, pinRule("far", 0, 300000000, 1), pinRule("near", 0, 2, 6))
+		assert.Equal(t, []catalog.ProductID{2, 6, 3, 4, 5, 1}, productIDs(result))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Validity Window", func(t *testing.T) {
+		expired := pinRule("expired", 0, 1, 6)
+		expired.EndsAt = rulesNow.Add(-time.Hour)
+		result, fired := applyRules(t, products, expired)
+		assert.Equal(t, productIDs(products), productIDs(result))
+		assert.Empty(t, fired)
+	})
+
+	t.Run("Input Unchanged", func(t *testing.T) {
+		applyRules(t, products, pinRule("pin", 0, 1, 6))
+		assert.Equal(t, []catalog.ProductID{1, 2, 3, 4, 5, 6}, productIDs(products))
+	})
+}
+
+func TestService_MerchandisingRules(t *testing.T) {
+	ctx := context.Background()
+	products := generateLargeProductCollection(5)
+	products[2].Category = "clearance"
+	rules := staticRules{
+		pinRule("hero", 10, 1, 4),
+		{ID: "clearance", Action: catalog.ActionExclude, Condition: catalog.ProductFilter{Categories: []string{"clearance"}}},
+	}
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithMerchandisingRules(rules))
+
+	t.Run("Applied After Sorter", func(t *testing.T) {
+		result, err := service.SortProducts(ctx, products, catalog.SortByPriceAsc)
+		require.NoError(t, err)
+		require.NoError(t, result.Validate())
+
+		sorted := productIDs(result.Products)
+		assert.Equal(t, catalog.ProductID(4), sorted[0])
+		assert.NotContains(t, sorted, catalog.ProductID(3))
+		assert.Len(t, result.FiredRules, 2)
+	})
+
+	t.Run("Rules Resolved At PricedAt", func(t *testing.T) {
+		future := append(rules[:0:0], rules...)
+		future[0].StartsAt = rulesNow
+		service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(), catalog.WithMerchandisingRules(future))
+
+		before, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPriceAsc,
+			catalog.SortOptions{PricedAt: rulesNow.Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Len(t, before.FiredRules, 1)
+
+		after, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPriceAsc,
+			catalog.SortOptions{PricedAt: rulesNow})
+		require.NoError(t, err)
+		assert.Len(t, after.FiredRules, 2)
+	})
+
+	t.Run("Invalid Rule", func(t *testing.T) {
+		service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(),
+			catalog.WithMerchandisingRules(staticRules{{ID: "broken", Action: catalog.ActionPin}}))
+		_, err := service.SortProducts(ctx, products, catalog.SortByPriceAsc)
+		assert.Error(t, err)
+	})
+}
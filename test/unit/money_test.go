@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"product-catalog-sorting/internal/domain/catalog"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input    string
+		currency catalog.Currency
+		expected int64
+		wantErr  bool
+	}{
+		{input: "12.99", currency: catalog.USD, expected: 1299},
+		{input: "0.1", currency: catalog.USD, expected: 10},
+		{input: "7", currency: catalog.USD, expected: 700},
+		{input: ".5", currency: catalog.EUR, expected: 50},
+		{input: "-3.25", currency: catalog.USD, expected: -325},
+		{input: "1500", currency: catalog.JPY, expected: 1500},
+		{input: "1.005", currency: "KWD", expected: 1005},
+		{input: "12.999", currency: catalog.USD, wantErr: true},
+		{input: "1.5", currency: catalog.JPY, wantErr: true},
+		{input: "abc", currency: catalog.USD, wantErr: true},
+		{input: "", currency: catalog.USD, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			money, err := catalog.ParseMoney(tt.input, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, catalog.NewMoney(tt.expected, tt.currency), money)
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	t.Run("Sums Are Exact", func(t *testing.T) {
+		// 0.1 + 0.2 is not 0.3 in float64
+		total, err := catalog.NewMoney(10, catalog.USD).Add(catalog.NewMoney(20, catalog.USD))
+		require.NoError(t, err)
+		assert.Equal(t, "0.30", total.Decimal())
+	})
+
+	t.Run("Currency Mismatch", func(t *testing.T) {
+		_, err := catalog.NewMoney(100, catalog.USD).Add(catalog.NewMoney(100, catalog.EUR))
+		assert.ErrorIs(t, err, catalog.ErrCurrencyMismatch)
+	})
+
+	t.Run("Revenue Over Many Sales", func(t *testing.T) {
+		product := catalog.Product{Price: catalog.NewMoney(1999, catalog.USD), SalesCount: 1000003}
+		assert.Equal(t, "19990059.97", product.RevenueGenerated().Decimal())
+	})
+
+	t.Run("Totals By Currency", func(t *testing.T) {
+		products := catalog.ProductCollection{
+			{Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 2},
+			{Price: catalog.NewMoney(500, catalog.EUR), SalesCount: 3},
+			{Price: catalog.NewMoney(250, catalog.USD), SalesCount: 4},
+		}
+
+		totals := products.TotalRevenueByCurrency()
+		assert.Equal(t, catalog.NewMoney(3000, catalog.USD), totals[catalog.USD])
+		assert.Equal(t, catalog.NewMoney(1500, catalog.EUR), totals[catalog.EUR])
+	})
+}
+
+func TestProductFilter_PriceCurrency(t *testing.T) {
+	minPrice := catalog.NewMoney(1000, catalog.USD)
+	maxPrice := catalog.NewMoney(5000, catalog.USD)
+	filter := catalog.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}
+
+	assert.True(t, filter.Matches(catalog.Product{Price: catalog.NewMoney(2000, catalog.USD)}))
+	assert.False(t, filter.Matches(catalog.Product{Price: catalog.NewMoney(500, catalog.USD)}))
+
+	// 2000 yen is within the raw bounds but cannot be compared with dollars
+	assert.False(t, filter.Matches(catalog.Product{Price: catalog.NewMoney(2000, catalog.JPY)}))
+	assert.False(t, catalog.ProductFilter{MaxPrice: &maxPrice}.Matches(catalog.Product{Price: catalog.NewMoney(100, catalog.EUR)}))
+}
+
+func TestMoney_Formatting(t *testing.T) {
+	assert.Equal(t, "$0.05", catalog.NewMoney(5, catalog.USD).String())
+	assert.Equal(t, "-$1.50", catalog.NewMoney(-150, catalog.USD).String())
+	assert.Equal(t, "€12.00", catalog.NewMoney(1200, catalog.EUR).String())
+	assert.Equal(t, "¥1500", catalog.NewMoney(1500, catalog.JPY).String())
+	assert.Equal(t, "CHF 9.90", catalog.NewMoney(990, "CHF").String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		original := catalog.NewMoney(1299, catalog.EUR)
+
+		data, err := json.Marshal(original)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"minor_units":1299,"currency":"EUR"}`, string(data))
+
+		var decoded catalog.Money
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, original, decoded)
+	})
+
+	t.Run("Legacy Float Prices", func(t *testing.T) {
+		var product catalog.Product
+		require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"Legacy","price":19.99}`), &product))
+		assert.Equal(t, catalog.NewMoney(1999, catalog.USD), product.Price)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"Legacy","price":"0.07"}`), &product))
+		assert.Equal(t, catalog.NewMoney(7, catalog.USD), product.Price)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"Legacy","price":4.005}`), &product))
+		assert.Equal(t, catalog.NewMoney(401, catalog.USD), product.Price)
+	})
+
+	t.Run("Invalid Price", func(t *testing.T) {
+		var product catalog.Product
+		assert.Error(t, json.Unmarshal([]byte(`{"id":1,"price":"cheap"}`), &product))
+	})
+}
+
+func TestProduct_ValidateCurrency(t *testing.T) {
+	product := testProductWithPrice(catalog.NewMoney(1000, "usd"))
+	assert.Error(t, product.Validate())
+
+	product = testProductWithPrice(catalog.NewMoney(99999999, catalog.USD))
+	assert.NoError(t, product.Validate())
+
+	product = testProductWithPrice(catalog.NewMoney(100000000, catalog.USD))
+	assert.Error(t, product.Validate())
+}
+
+func testProductWithPrice(price catalog.Money) catalog.Product {
+	product := generateLargeProductCollection(1)[0]
+	product.Price = price
+	return product
+}
@@ -168,31 +168,31 @@ func TestProduct_RevenueGenerated(t *testing.T) {
 		name            string
 		price           catalog.Price
 		salesCount      int
-		expectedRevenue float64
+		expectedRevenue catalog.Money
 	}{
 		{
 			name:            "Basic revenue calculation",
-			price:           catalog.Price(25.50),
+			price:           catalog.NewMoney(2550, catalog.USD),
 			salesCount:      10,
-			expectedRevenue: 255.0,
+			expectedRevenue: catalog.NewMoney(25500, catalog.USD),
 		},
 		{
 			name:            "Zero sales",
-			price:           catalog.Price(100.0),
+			price:           catalog.NewMoney(10000, catalog.USD),
 			salesCount:      0,
-			expectedRevenue: 0.0,
+			expectedRevenue: catalog.NewMoney(0, catalog.USD),
 		},
 		{
 			name:            "High volume sales",
-			price:           catalog.Price(9.99),
+			price:           catalog.NewMoney(999, catalog.USD),
 			salesCount:      1000,
-			expectedRevenue: 9990.0,
+			expectedRevenue: catalog.NewMoney(999000, catalog.USD),
 		},
 		{
 			name:            "Expensive item low volume",
-			price:           catalog.Price(1999.99),
+			price:           catalog.NewMoney(199999, catalog.USD),
 			salesCount:      5,
-			expectedRevenue: 9999.95,
+			expectedRevenue: catalog.NewMoney(999995, catalog.USD),
 		},
 	}
 
@@ -213,7 +213,7 @@ func TestProduct_Validate(t *testing.T) {
 	validProduct := catalog.Product{
 		ID:         1,
 		Name:       "Test Product",
-		Price:      10.99,
+		Price:      catalog.NewMoney(1099, catalog.USD),
 		CreatedAt:  time.Now(),
 		SalesCount: 5,
 		ViewsCount: 50,
@@ -235,7 +235,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         0,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -248,7 +248,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         -1,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -261,7 +261,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -274,7 +274,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       string(make([]byte, 256)), // 256 characters
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -287,7 +287,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      -10.0,
+				Price:      catalog.NewMoney(-1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -300,7 +300,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      1000000.0,
+				Price:      catalog.NewMoney(100000000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -313,7 +313,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Time{},
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -326,7 +326,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now().Add(24 * time.Hour),
 				SalesCount: 5,
 				ViewsCount: 50,
@@ -339,7 +339,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: -1,
 				ViewsCount: 50,
@@ -352,7 +352,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 5,
 				ViewsCount: -1,
@@ -365,7 +365,7 @@ func TestProduct_Validate(t *testing.T) {
 			product: catalog.Product{
 				ID:         1,
 				Name:       "Test",
-				Price:      10.0,
+				Price:      catalog.NewMoney(1000, catalog.USD),
 				CreatedAt:  time.Now(),
 				SalesCount: 100,
 				ViewsCount: 50,
@@ -395,7 +395,7 @@ func TestProduct_IsValid(t *testing.T) {
 	validProduct := catalog.Product{
 		ID:         1,
 		Name:       "Test Product",
-		Price:      10.99,
+		Price:      catalog.NewMoney(1099, catalog.USD),
 		CreatedAt:  time.Now(),
 		SalesCount: 5,
 		ViewsCount: 50,
@@ -404,7 +404,7 @@ func TestProduct_IsValid(t *testing.T) {
 	invalidProduct := catalog.Product{
 		ID:         0,
 		Name:       "",
-		Price:      -10.0,
+		Price:      catalog.NewMoney(-1000, catalog.USD),
 		CreatedAt:  time.Time{},
 		SalesCount: -1,
 		ViewsCount: -1,
@@ -418,7 +418,7 @@ func TestProduct_String(t *testing.T) {
 	product := catalog.Product{
 		ID:         1,
 		Name:       "Test Product",
-		Price:      25.99,
+		Price:      catalog.NewMoney(2599, catalog.USD),
 		SalesCount: 10,
 		ViewsCount: 100,
 		CreatedAt:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
@@ -432,13 +432,13 @@ func TestProduct_String(t *testing.T) {
 
 func TestProductCollection_Validate(t *testing.T) {
 	validProducts := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
-		{ID: 2, Name: "Product 2", Price: 20.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+		{ID: 2, Name: "Product 2", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
 	}
 	
 	invalidProducts := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
-		{ID: 0, Name: "", Price: -10.0, CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+		{ID: 0, Name: "", Price: catalog.NewMoney(-1000, catalog.USD), CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1},
 	}
 	
 	emptyProducts := catalog.ProductCollection{}
@@ -462,8 +462,8 @@ func TestProductCollection_Validate(t *testing.T) {
 
 func TestProductCollection_Copy(t *testing.T) {
 	original := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0},
-		{ID: 2, Name: "Product 2", Price: 20.0},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD)},
+		{ID: 2, Name: "Product 2", Price: catalog.NewMoney(2000, catalog.USD)},
 	}
 	
 	copied := original.Copy()
@@ -482,6 +482,35 @@ func TestProductCollection_Copy(t *testing.T) {
 	assert.Nil(t, nilCopy)
 }
 
+func TestProductCollection_Clone(t *testing.T) {
+	cost := catalog.NewMoney(400, catalog.USD)
+	activated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := catalog.ProductCollection{{
+		ID:             1,
+		Name:           "Product 1",
+		Price:          catalog.NewMoney(1000, catalog.USD),
+		LocalizedNames: map[string]string{"de": "Produkt 1"},
+		Attributes:     catalog.Attributes{"brand": catalog.StringAttribute("Acme")},
+		CostPrice:      &cost,
+		ActivatedAt:    &activated,
+	}}
+
+	cloned := original.Clone()
+	require.Equal(t, original, cloned)
+
+	cloned[0].LocalizedNames["de"] = "Geändert"
+	cloned[0].Attributes["brand"] = catalog.StringAttribute("Other")
+	cloned[0].CostPrice.Amount = 1
+	*cloned[0].ActivatedAt = time.Time{}
+
+	assert.Equal(t, "Produkt 1", original[0].LocalizedNames["de"])
+	assert.Equal(t, "Acme", original[0].Attributes["brand"].String)
+	assert.Equal(t, int64(400), original[0].CostPrice.Amount)
+	assert.Equal(t, activated, *original[0].ActivatedAt)
+
+	assert.Nil(t, catalog.ProductCollection(nil).Clone())
+}
+
 func TestProductCollection_FilterHighPerformers(t *testing.T) {
 	products := catalog.ProductCollection{
 		{ID: 1, Name: "High Performer", SalesCount: 60, ViewsCount: 1000}, // 6%
@@ -498,17 +527,24 @@ func TestProductCollection_FilterHighPerformers(t *testing.T) {
 
 func TestProductCollection_TotalRevenue(t *testing.T) {
 	products := catalog.ProductCollection{
-		{Price: 10.0, SalesCount: 5},  // 50.0
-		{Price: 20.0, SalesCount: 3},  // 60.0
-		{Price: 15.0, SalesCount: 2},  // 30.0
+		{Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 5},  // 50.0
+		{Price: catalog.NewMoney(2000, catalog.USD), SalesCount: 3},  // 60.0
+		{Price: catalog.NewMoney(1500, catalog.USD), SalesCount: 2},  // 30.0
 	}
 	
-	totalRevenue := products.TotalRevenue()
-	assert.Equal(t, 140.0, totalRevenue)
-	
+	totalRevenue, err := products.TotalRevenue()
+	require.NoError(t, err)
+	assert.Equal(t, catalog.NewMoney(14000, catalog.USD), totalRevenue)
+
 	// Test empty collection
-	emptyProducts := catalog.ProductCollection{}
-	assert.Equal(t, 0.0, emptyProducts.TotalRevenue())
+	emptyRevenue, err := catalog.ProductCollection{}.TotalRevenue()
+	require.NoError(t, err)
+	assert.True(t, emptyRevenue.IsZero())
+
+	// Mixed currencies cannot be summed
+	products[2].Price = catalog.NewMoney(1500, catalog.EUR)
+	_, err = products.TotalRevenue()
+	assert.ErrorIs(t, err, catalog.ErrCurrencyMismatch)
 }
 
 func TestProductCollection_AverageConversionRatio(t *testing.T) {
@@ -561,10 +597,10 @@ func TestProductID_Methods(t *testing.T) {
 }
 
 func TestPrice_Methods(t *testing.T) {
-	validPrice := catalog.Price(25.99)
-	zeroPrice := catalog.Price(0.0)
-	negativePrice := catalog.Price(-10.0)
-	tooHighPrice := catalog.Price(1000000.0)
+	validPrice := catalog.NewMoney(2599, catalog.USD)
+	zeroPrice := catalog.NewMoney(0, catalog.USD)
+	negativePrice := catalog.NewMoney(-1000, catalog.USD)
+	tooHighPrice := catalog.NewMoney(100000000, catalog.USD)
 	
 	// Test String
 	assert.Equal(t, "$25.99", validPrice.String())
@@ -596,7 +632,7 @@ func BenchmarkProduct_Validate(b *testing.B) {
 	product := catalog.Product{
 		ID:         1,
 		Name:       "Test Product",
-		Price:      10.99,
+		Price:      catalog.NewMoney(1099, catalog.USD),
 		CreatedAt:  time.Now(),
 		SalesCount: 5,
 		ViewsCount: 50,
@@ -614,7 +650,7 @@ func BenchmarkProductCollection_Copy(b *testing.B) {
 		products[i] = catalog.Product{
 			ID:         catalog.ProductID(i + 1),
 			Name:       "Product",
-			Price:      10.0,
+			Price:      catalog.NewMoney(1000, catalog.USD),
 			CreatedAt:  time.Now(),
 			SalesCount: 5,
 			ViewsCount: 50,
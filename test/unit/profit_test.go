@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+func profitTestProducts() catalog.ProductCollection {
+	return catalog.ProductCollection{
+		// margin 50%, profit $5 x 10 = $50, $0.50 per view
+		testdata.Product(1, testdata.WithName("Half"), testdata.WithCost(500), testdata.WithViews(100)),
+		// margin 20%, profit $20 x 5 = $100, $2.00 per view
+		testdata.Product(2, testdata.WithName("Premium"), testdata.WithPrice(10000), testdata.WithCost(7000),
+			testdata.WithShipping(1000), testdata.WithSales(5), testdata.WithViews(50)),
+		// margin 80%, profit $4 x 2 = $8, $0.02 per view
+		testdata.Product(3, testdata.WithName("Niche"), testdata.WithPrice(500), testdata.WithCost(100),
+			testdata.WithSales(2), testdata.WithViews(400)),
+		// loss leader: margin -50%, profit -$1 x 40 = -$40
+		testdata.Product(4, testdata.WithName("Doorbuster"), testdata.WithPrice(200), testdata.WithCost(300),
+			testdata.WithLossLeader(), testdata.WithSales(40), testdata.WithViews(80)),
+		// unknown cost
+		testdata.Product(5, testdata.WithName("Legacy"), testdata.WithPrice(5000), testdata.WithSales(50), testdata.WithViews(60)),
+	}
+}
+
+func TestProduct_ProfitMetrics(t *testing.T) {
+	products := profitTestProducts()
+
+	premium := products[1]
+	assert.Equal(t, catalog.NewMoney(8000, catalog.USD), premium.UnitCost())
+	assert.Equal(t, catalog.NewMoney(2000, catalog.USD), premium.UnitProfit())
+	assert.InDelta(t, 0.2, premium.GrossMargin(), 1e-9)
+	assert.Equal(t, catalog.NewMoney(10000, catalog.USD), premium.TotalProfit())
+	assert.InDelta(t, 2.0, premium.ProfitPerView(), 1e-9)
+
+	doorbuster := products[3]
+	assert.InDelta(t, -0.5, doorbuster.GrossMargin(), 1e-9)
+	assert.Equal(t, catalog.NewMoney(-4000, catalog.USD), doorbuster.TotalProfit())
+
+	legacy := products[4]
+	assert.False(t, legacy.HasCost())
+	assert.Equal(t, legacy.RevenueGenerated(), legacy.TotalProfit())
+
+	assert.Equal(t, catalog.NewMoney(5000+10000+800-4000+250000, catalog.USD), products.TotalProfit())
+}
+
+func TestProduct_CostValidation(t *testing.T) {
+	products := profitTestProducts()
+	for _, product := range products {
+		assert.NoError(t, product.Validate(), "Product %d", product.ID)
+	}
+
+	t.Run("Cost Above Price Requires Loss Leader", func(t *testing.T) {
+		product := products[3]
+		product.LossLeader = false
+		assert.Error(t, product.Validate())
+	})
+
+	t.Run("Negative Shipping", func(t *testing.T) {
+		product := testdata.Product(1, testdata.WithCost(500), testdata.WithShipping(-1))
+		assert.Error(t, product.Validate())
+	})
+
+	t.Run("Currency Mismatch", func(t *testing.T) {
+		product := products[0]
+		cost := catalog.NewMoney(100, catalog.EUR)
+		product.CostPrice = &cost
+		assert.Error(t, product.Validate())
+	})
+}
+
+func TestProfitSorters(t *testing.T) {
+	ctx := context.Background()
+	factory := sorting.NewSorterFactory()
+	products := profitTestProducts()
+
+	tests := []struct {
+		strategy catalog.SortStrategy
+		expected []catalog.ProductID
+	}{
+		{catalog.SortByMargin, []catalog.ProductID{3, 1, 2, 4, 5}},
+		{catalog.SortByProfit, []catalog.ProductID{2, 1, 3, 4, 5}},
+		{catalog.SortByProfitPerView, []catalog.ProductID{2, 1, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			sorter, err := factory.CreateSorter(tt.strategy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.strategy, sorter.GetStrategy())
+
+			sorted, err := sorter.Sort(ctx, products)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, productIDs(sorted))
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+// staticPromotions is a fixed PromotionProvider for tests
+type staticPromotions []catalog.Promotion
+
+func (p staticPromotions) Promotions(ctx context.Context) ([]catalog.Promotion, error) {
+	return p, nil
+}
+
+var promotionStart = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func promotionTestProducts() catalog.ProductCollection {
+	created := testdata.WithCreatedAt(promotionStart.AddDate(0, -1, 0))
+	return catalog.ProductCollection{
+		testdata.Product(1, created, testdata.WithCategory("garden")),
+		testdata.Product(2, created, testdata.WithPrice(1500), testdata.WithCategory("garden")),
+		testdata.Product(3, created, testdata.WithPrice(1200), testdata.WithCategory("kitchen")),
+		testdata.Product(4, created, testdata.WithPrice(800)),
+	}
+}
+
+func promotionTestPromotions() []catalog.Promotion {
+	return []catalog.Promotion{
+		// 50% off garden during the first week of March: product 2 costs $7.50
+		{ID: "garden-sale", Type: catalog.DiscountPercentage, Percentage: 50, Categories: []string{"garden"},
+			StartsAt: promotionStart, EndsAt: promotionStart.AddDate(0, 0, 7)},
+		// $5 off product 3 from March onwards: product 3 costs $7.00
+		{ID: "kitchen-clearance", Type: catalog.DiscountFixed, Amount: catalog.NewMoney(500, catalog.USD),
+			ProductIDs: []catalog.ProductID{3}, StartsAt: promotionStart},
+	}
+}
+
+func TestPromotion_Validate(t *testing.T) {
+	valid := promotionTestPromotions()[0]
+	require.NoError(t, valid.Validate())
+
+	invalid := map[string]func(p *catalog.Promotion){
+		"Missing ID":         func(p *catalog.Promotion) { p.ID = "" },
+		"Unknown Type":       func(p *catalog.Promotion) { p.Type = "bogo" },
+		"Percentage Too Big": func(p *catalog.Promotion) { p.Percentage = 120 },
+		"Zero Percentage":    func(p *catalog.Promotion) { p.Percentage = 0 },
+		"Missing Start":      func(p *catalog.Promotion) { p.StartsAt = time.Time{} },
+		"Ends Before Start":  func(p *catalog.Promotion) { p.EndsAt = p.StartsAt.Add(-time.Hour) },
+		"No Scope":           func(p *catalog.Promotion) { p.Categories = nil },
+		"Fixed Without Amount": func(p *catalog.Promotion) {
+			p.Type = catalog.DiscountFixed
+			p.Amount = catalog.Money{}
+		},
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			promotion := valid
+			mutate(&promotion)
+			assert.Error(t, promotion.Validate())
+		})
+	}
+}
+
+func TestPromotionResolver_EffectivePrice(t *testing.T) {
+	products := promotionTestProducts()
+
+	t.Run("Active Window", func(t *testing.T) {
+		resolver, err := catalog.NewPromotionResolver(promotionTestPromotions(), promotionStart.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, catalog.NewMoney(500, catalog.USD), resolver.EffectivePrice(products[0]))
+		assert.Equal(t, catalog.NewMoney(750, catalog.USD), resolver.EffectivePrice(products[1]))
+		assert.Equal(t, catalog.NewMoney(700, catalog.USD), resolver.EffectivePrice(products[2]))
+		assert.Equal(t, products[3].Price, resolver.EffectivePrice(products[3]))
+	})
+
+	t.Run("Start Inclusive End Exclusive", func(t *testing.T) {
+		before, err := catalog.NewPromotionResolver(promotionTestPromotions(), promotionStart.Add(-time.Second))
+		require.NoError(t, err)
+		assert.Empty(t, before.ActivePromotions())
+
+		atEnd, err := catalog.NewPromotionResolver(promotionTestPromotions(), promotionStart.AddDate(0, 0, 7))
+		require.NoError(t, err)
+		assert.Equal(t, products[0].Price, atEnd.EffectivePrice(products[0]))
+		assert.Equal(t, catalog.NewMoney(700, catalog.USD), atEnd.EffectivePrice(products[2]))
+	})
+
+	t.Run("Discount Never Goes Below Zero", func(t *testing.T) {
+		promotion := catalog.Promotion{ID: "free", Type: catalog.DiscountFixed, Amount: catalog.NewMoney(5000, catalog.USD),
+			ProductIDs: []catalog.ProductID{4}, StartsAt: promotionStart}
+		resolver, err := catalog.NewPromotionResolver([]catalog.Promotion{promotion}, promotionStart)
+		require.NoError(t, err)
+		assert.True(t, resolver.EffectivePrice(products[3]).IsZero())
+	})
+
+	t.Run("Fixed Discount Ignores Other Currencies", func(t *testing.T) {
+		promotion := catalog.Promotion{ID: "euro", Type: catalog.DiscountFixed, Amount: catalog.NewMoney(100, catalog.EUR),
+			ProductIDs: []catalog.ProductID{4}, StartsAt: promotionStart}
+		resolver, err := catalog.NewPromotionResolver([]catalog.Promotion{promotion}, promotionStart)
+		require.NoError(t, err)
+		assert.Equal(t, products[3].Price, resolver.EffectivePrice(products[3]))
+	})
+
+	t.Run("Invalid Promotion", func(t *testing.T) {
+		_, err := catalog.NewPromotionResolver([]catalog.Promotion{{ID: "broken"}}, promotionStart)
+		assert.Error(t, err)
+	})
+}
+
+func TestPromotionResolver_Precedence(t *testing.T) {
+	product := promotionTestProducts()[0] // $10.00 garden product
+	promotion := func(id string, priority int, percentage float64, byProduct bool) catalog.Promotion {
+		p := catalog.Promotion{ID: id, Type: catalog.DiscountPercentage, Percentage: percentage,
+			Priority: priority, StartsAt: promotionStart}
+		if byProduct {
+			p.ProductIDs = []catalog.ProductID{product.ID}
+		} else {
+			p.Categories = []string{product.Category}
+		}
+		return p
+	}
+
+	tests := []struct {
+		name       string
+		promotions []catalog.Promotion
+		winner     string
+	}{
+		{"Highest Priority", []catalog.Promotion{promotion("a", 0, 50, true), promotion("b", 1, 10, false)}, "b"},
+		{"Product Scope Over Category", []catalog.Promotion{promotion("a", 0, 50, false), promotion("b", 0, 10, true)}, "b"},
+		{"Largest Discount", []catalog.Promotion{promotion("a", 0, 10, false), promotion("b", 0, 30, false)}, "b"},
+		{"Lowest ID", []catalog.Promotion{promotion("b", 0, 20, false), promotion("a", 0, 20, false)}, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := catalog.NewPromotionResolver(tt.promotions, promotionStart)
+			require.NoError(t, err)
+
+			winner, ok := resolver.Resolve(product)
+			require.True(t, ok)
+			assert.Equal(t, tt.winner, winner.ID)
+		})
+	}
+}
+
+func TestService_SortWithPromotions(t *testing.T) {
+	ctx := context.Background()
+	products := promotionTestProducts()
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop(),
+		catalog.WithPromotions(staticPromotions(promotionTestPromotions())))
+	during := catalog.SortOptions{PricedAt: promotionStart.Add(time.Hour)}
+
+	t.Run("Price Uses Effective Price", func(t *testing.T) {
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPriceAsc, during)
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{1, 3, 2, 4}, productIDs(result.Products))
+
+		require.NotNil(t, result.PricedAt)
+		assert.Equal(t, during.PricedAt, *result.PricedAt)
+		assert.Len(t, result.EffectivePrices, 3)
+		assert.Equal(t, catalog.NewMoney(750, catalog.USD), result.EffectivePrice(products[1]))
+		assert.Equal(t, products[3].Price, result.EffectivePrice(products[3]))
+	})
+
+	t.Run("Revenue Uses Effective Price", func(t *testing.T) {
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByRevenue, during)
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{4, 2, 3, 1}, productIDs(result.Products))
+	})
+
+	t.Run("List Price Outside Window", func(t *testing.T) {
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPriceAsc,
+			catalog.SortOptions{PricedAt: promotionStart.Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{4, 1, 3, 2}, productIDs(result.Products))
+		assert.Empty(t, result.EffectivePrices)
+	})
+
+	t.Run("No Provider Uses List Price", func(t *testing.T) {
+		plain := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+		result, err := plain.SortProductsWithOptions(ctx, products, catalog.SortByPriceAsc, during)
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{4, 1, 3, 2}, productIDs(result.Products))
+		assert.Nil(t, result.PricedAt)
+	})
+
+	t.Run("Pages Follow Effective Price", func(t *testing.T) {
+		// The kitchen clearance never ends, so it is active now
+		first, err := service.SortPage(ctx, products, catalog.SortByPriceAsc, catalog.PageRequest{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{3, 4}, productIDs(first.Products))
+		assert.Contains(t, first.EffectivePrices, catalog.ProductID(3))
+
+		second, err := service.SortPage(ctx, products, catalog.SortByPriceAsc,
+			catalog.PageRequest{Limit: 2, After: first.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{1, 2}, productIDs(second.Products))
+	})
+}
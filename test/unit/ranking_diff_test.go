@@ -0,0 +1,163 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// ranking builds a sort result listing products in the given ID order
+func ranking(strategy catalog.SortStrategy, ids ...catalog.ProductID) *catalog.SortResult {
+	products := make(catalog.ProductCollection, len(ids))
+	for i, id := range ids {
+		products[i] = catalog.Product{ID: id}
+	}
+	return catalog.NewSortResult(products, strategy, 0)
+}
+
+func TestCompareRankings(t *testing.T) {
+	t.Run("Identical", func(t *testing.T) {
+		diff, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 2, 3, 4),
+			ranking(catalog.SortByRevenue, 1, 2, 3, 4), catalog.RankingDiffOptions{TopK: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, diff.KendallTau)
+		assert.Equal(t, 1.0, diff.SpearmanRho)
+		assert.Equal(t, 1.0, diff.OverlapAtK)
+		assert.Empty(t, diff.BiggestMovers)
+		assert.Empty(t, diff.EnteredTopK)
+	})
+
+	t.Run("Shorter Than K", func(t *testing.T) {
+		diff, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 2, 3),
+			ranking(catalog.SortByRevenue, 1, 2, 3), catalog.RankingDiffOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, diff.OverlapAtK)
+
+		diff, err = catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 2, 3, 4),
+			ranking(catalog.SortByRevenue, 4, 5), catalog.RankingDiffOptions{TopK: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, diff.OverlapAtK)
+
+		diff, err = catalog.CompareRankings(ranking(catalog.SortByPopularity),
+			ranking(catalog.SortByRevenue), catalog.RankingDiffOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, diff.OverlapAtK)
+	})
+
+	t.Run("Reversed", func(t *testing.T) {
+		diff, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 2, 3, 4, 5),
+			ranking(catalog.SortByRevenue, 5, 4, 3, 2, 1), catalog.RankingDiffOptions{TopK: 2, Movers: 2})
+		require.NoError(t, err)
+		assert.Equal(t, -1.0, diff.KendallTau)
+		assert.Equal(t, -1.0, diff.SpearmanRho)
+		assert.Equal(t, 0.0, diff.OverlapAtK)
+		assert.Equal(t, []catalog.ProductID{5, 4}, diff.EnteredTopK)
+		assert.Equal(t, []catalog.ProductID{1, 2}, diff.ExitedTopK)
+		assert.Equal(t, []catalog.PositionDelta{
+			{ProductID: 5, Before: 5, After: 1, Delta: 4},
+			{ProductID: 1, Before: 1, After: 5, Delta: -4},
+		}, diff.BiggestMovers)
+	})
+
+	t.Run("One Swap", func(t *testing.T) {
+		diff, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 2, 3),
+			ranking(catalog.SortByRevenue, 1, 3, 2), catalog.RankingDiffOptions{})
+		require.NoError(t, err)
+		assert.InDelta(t, 1.0/3, diff.KendallTau, 1e-9)
+		assert.InDelta(t, 0.5, diff.SpearmanRho, 1e-9)
+	})
+
+	t.Run("Snapshots With Different Products", func(t *testing.T) {
+		diff, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 2, 3),
+			ranking(catalog.SortByPopularity, 4, 2, 1), catalog.RankingDiffOptions{TopK: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, diff.CommonProducts)
+		assert.Equal(t, -1.0, diff.KendallTau)
+		assert.Equal(t, []catalog.PositionDelta{
+			{ProductID: 4, After: 1},
+			{ProductID: 2, Before: 2, After: 2},
+			{ProductID: 1, Before: 1, After: 3, Delta: -2},
+			{ProductID: 3, Before: 3},
+		}, diff.Positions)
+		assert.Equal(t, []catalog.ProductID{4}, diff.EnteredTopK)
+		assert.Equal(t, []catalog.ProductID{1}, diff.ExitedTopK)
+		assert.Equal(t, 0.5, diff.OverlapAtK)
+	})
+
+	t.Run("Kendall Tau Matches Pairwise Count", func(t *testing.T) {
+		ids := make([]catalog.ProductID, 200)
+		for i := range ids {
+			ids[i] = catalog.ProductID(i + 1)
+		}
+		shuffled := append([]catalog.ProductID(nil), ids...)
+		rand.New(rand.NewSource(7)).Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+
+		concordant := 0
+		for i := range shuffled {
+			for j := i + 1; j < len(shuffled); j++ {
+				if shuffled[i] < shuffled[j] {
+					concordant++
+				} else {
+					concordant--
+				}
+			}
+		}
+		pairs := float64(len(ids)) * float64(len(ids)-1) / 2
+
+		diff, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, ids...),
+			ranking(catalog.SortByRevenue, shuffled...), catalog.RankingDiffOptions{})
+		require.NoError(t, err)
+		assert.InDelta(t, float64(concordant)/pairs, diff.KendallTau, 1e-9)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := catalog.CompareRankings(ranking(catalog.SortByPopularity, 1, 1), ranking(catalog.SortByRevenue, 1),
+			catalog.RankingDiffOptions{})
+		assert.Error(t, err)
+
+		_, err = catalog.CompareRankings(ranking(catalog.SortByPopularity, 1), ranking(catalog.SortByRevenue, 1),
+			catalog.RankingDiffOptions{TopK: -1})
+		assert.Error(t, err)
+	})
+}
+
+func TestService_CompareStrategies(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := generateLargeProductCollection(50)
+
+	diff, err := service.CompareStrategies(context.Background(), products, catalog.SortByPriceAsc,
+		catalog.SortByPriceDesc, catalog.RankingDiffOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 50, diff.CommonProducts)
+	assert.Len(t, diff.Positions, 50)
+	assert.Equal(t, catalog.DefaultDiffTopK, diff.TopK)
+
+	handler := httpapi.NewHandler(service, zap.NewNop())
+	var body bytes.Buffer
+	for _, product := range products {
+		require.NoError(t, json.NewEncoder(&body).Encode(product))
+	}
+	request := httptest.NewRequest(http.MethodPost, "/v1/compare?base=price_asc&candidate=price_desc&k=5", &body)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var decoded catalog.RankingDiff
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&decoded))
+	assert.Equal(t, 5, decoded.TopK)
+	assert.Equal(t, diff.KendallTau, decoded.KendallTau)
+}
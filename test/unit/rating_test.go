@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+func ratingTestProducts() catalog.ProductCollection {
+	return catalog.ProductCollection{
+		testdata.Product(1, testdata.WithRatings(1, 5)),       // a single 5-star review
+		testdata.Product(2, testdata.WithRatings(2000, 9400)), // 4.7 stars from 2,000 reviews
+		testdata.Product(3), // no reviews yet
+		testdata.Product(4, testdata.WithRatings(50, 100)),  // 2.0 stars
+		testdata.Product(5, testdata.WithRatings(200, 860)), // 4.3 stars
+	}
+}
+
+func TestProduct_BayesianRating(t *testing.T) {
+	products := ratingTestProducts()
+	prior := catalog.RatingPrior{Mean: 3.0, Weight: 10}
+
+	assert.InDelta(t, 5.0, products[0].AverageRating(), 1e-9)
+	assert.InDelta(t, 35.0/11.0, products[0].BayesianRating(prior), 1e-9)
+	assert.InDelta(t, 9430.0/2010.0, products[1].BayesianRating(prior), 1e-9)
+	assert.Equal(t, 0.0, products[2].AverageRating())
+	assert.Equal(t, 3.0, products[2].BayesianRating(prior))
+
+	// Without a prior weight the Bayesian average is the plain average
+	assert.InDelta(t, 5.0, products[0].BayesianRating(catalog.RatingPrior{Mean: 3.0}), 1e-9)
+
+	assert.InDelta(t, float64(5+9400+100+860)/2251.0, products.AverageRating(), 1e-9)
+}
+
+func TestProduct_RatingValidation(t *testing.T) {
+	for _, product := range ratingTestProducts() {
+		assert.NoError(t, product.Validate(), "Product %d", product.ID)
+	}
+
+	invalid := []struct {
+		name       string
+		count, sum int
+	}{
+		{"Negative Count", -1, 0},
+		{"Average Above Five", 2, 11},
+		{"Average Below One", 3, 2},
+		{"Sum Without Reviews", 0, 4},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			product := ratingTestProducts()[0]
+			product.RatingCount, product.RatingSum = tt.count, tt.sum
+			assert.Error(t, product.Validate())
+		})
+	}
+}
+
+func TestRatingSorter(t *testing.T) {
+	ctx := context.Background()
+	products := ratingTestProducts()
+
+	t.Run("Default Prior", func(t *testing.T) {
+		sorter, err := sorting.NewSorterFactory().CreateSorter(catalog.SortByRating)
+		require.NoError(t, err)
+		assert.Equal(t, catalog.SortByRating, sorter.GetStrategy())
+
+		sorted, err := sorter.Sort(ctx, products)
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{2, 5, 1, 3, 4}, productIDs(sorted))
+	})
+
+	t.Run("Configured Prior", func(t *testing.T) {
+		// A weightless prior ranks by plain average, more reviews breaking ties
+		factory := sorting.NewSorterFactory(sorting.WithRatingPrior(catalog.RatingPrior{Mean: 1.0}))
+		sorter, err := factory.CreateSorter(catalog.SortByRating)
+		require.NoError(t, err)
+
+		sorted, err := sorter.Sort(ctx, products)
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{1, 2, 5, 4, 3}, productIDs(sorted))
+	})
+
+	t.Run("Invalid Prior", func(t *testing.T) {
+		factory := sorting.NewSorterFactory(sorting.WithRatingPrior(catalog.RatingPrior{Mean: 7, Weight: 10}))
+		_, err := factory.CreateSorter(catalog.SortByRating)
+		assert.Error(t, err)
+		assert.Error(t, catalog.RatingPrior{Mean: 3, Weight: -1}.Validate())
+	})
+}
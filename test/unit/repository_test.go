@@ -0,0 +1,199 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/repository"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+func TestMemoryRepository_SortedIndexes(t *testing.T) {
+	factory := sorting.NewSorterFactory()
+	repo, err := repository.NewMemoryRepository(factory)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(42))
+	now := time.Now()
+
+	// Apply a random mix of inserts, updates and deletes
+	live := make(map[catalog.ProductID]catalog.Product)
+	for i := 0; i < 3000; i++ {
+		id := catalog.ProductID(rng.Intn(400) + 1)
+
+		if rng.Intn(5) == 0 {
+			err := repo.DeleteProduct(ctx, id)
+			if _, exists := live[id]; exists {
+				require.NoError(t, err)
+				delete(live, id)
+			} else {
+				assert.True(t, errors.Is(err, catalog.ErrProductNotFound))
+			}
+			continue
+		}
+
+		views := rng.Intn(1000) + 1
+		product := catalog.Product{
+			ID:         id,
+			Name:       string(rune('A'+rng.Intn(26))) + " Product",
+			Price:      catalog.NewMoney(int64(rng.Intn(50))*100, catalog.USD),
+			CreatedAt:  now.Add(-time.Duration(rng.Intn(100)) * time.Hour),
+			SalesCount: rng.Intn(views),
+			ViewsCount: views,
+		}
+		require.NoError(t, repo.SaveProduct(ctx, &product))
+		live[id] = product
+	}
+
+	all := make(catalog.ProductCollection, 0, len(live))
+	for _, product := range live {
+		all = append(all, product)
+	}
+
+	count, err := repo.GetProductCount(ctx, catalog.ProductFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, len(live), count)
+
+	for _, strategy := range catalog.AllSortStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			sorter, err := factory.CreateSorter(strategy)
+			require.NoError(t, err)
+			expected, err := sorter.Sort(ctx, all)
+			require.NoError(t, err)
+
+			var paged catalog.ProductCollection
+			for offset := 0; offset < len(expected); offset += 37 {
+				page, err := repo.GetSortedPage(ctx, strategy, offset, 37)
+				require.NoError(t, err)
+				paged = append(paged, page...)
+			}
+
+			assert.Equal(t, productIDs(expected), productIDs(paged))
+		})
+	}
+
+	t.Run("Page Bounds", func(t *testing.T) {
+		page, err := repo.GetSortedPage(ctx, catalog.SortByName, len(live)+10, 5)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		page, err = repo.GetSortedPage(ctx, catalog.SortByName, len(live)-2, 5)
+		require.NoError(t, err)
+		assert.Len(t, page, 2)
+
+		_, err = repo.GetSortedPage(ctx, catalog.SortByName, -1, 5)
+		assert.Error(t, err)
+
+		_, err = repo.GetSortedPage(ctx, "bogus", 0, 5)
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryRepository_CRUD(t *testing.T) {
+	repo, err := repository.NewMemoryRepository(sorting.NewSorterFactory())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, product := range generateLargeProductCollection(20) {
+		product := product
+		require.NoError(t, repo.SaveProduct(ctx, &product))
+	}
+
+	t.Run("Get By ID", func(t *testing.T) {
+		product, err := repo.GetProductByID(ctx, 5)
+		require.NoError(t, err)
+		assert.Equal(t, "Product 5", product.Name)
+
+		_, err = repo.GetProductByID(ctx, 999)
+		assert.ErrorIs(t, err, catalog.ErrProductNotFound)
+	})
+
+	t.Run("Filter And Paginate", func(t *testing.T) {
+		minPrice := catalog.NewMoney(1500, catalog.USD)
+		products, err := repo.GetProducts(ctx, catalog.ProductFilter{MinPrice: &minPrice, Offset: 2, Limit: 3})
+		require.NoError(t, err)
+
+		assert.Equal(t, []catalog.ProductID{8, 9, 10}, productIDs(products))
+
+		count, err := repo.GetProductCount(ctx, catalog.ProductFilter{MinPrice: &minPrice})
+		require.NoError(t, err)
+		assert.Equal(t, 15, count)
+	})
+
+	t.Run("Update Reorders Index", func(t *testing.T) {
+		product, err := repo.GetProductByID(ctx, 1)
+		require.NoError(t, err)
+
+		product.Price = catalog.NewMoney(50000, catalog.USD)
+		require.NoError(t, repo.SaveProduct(ctx, product))
+
+		top, err := repo.GetSortedPage(ctx, catalog.SortByPriceDesc, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, top, 1)
+		assert.Equal(t, catalog.ProductID(1), top[0].ID)
+	})
+
+	t.Run("Invalid Product Rejected", func(t *testing.T) {
+		err := repo.SaveProduct(ctx, &catalog.Product{ID: 0})
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryRepository_StaleIndexes(t *testing.T) {
+	ctx := context.Background()
+	expired := catalog.Promotion{ID: "expired", Type: catalog.DiscountPercentage, Percentage: 50,
+		ProductIDs: []catalog.ProductID{1}, StartsAt: promotionStart, EndsAt: promotionStart.AddDate(0, 0, 7)}
+	running := catalog.Promotion{ID: "running", Type: catalog.DiscountPercentage, Percentage: 50,
+		ProductIDs: []catalog.ProductID{1}, StartsAt: promotionStart}
+
+	newRepo := func(t *testing.T, options ...repository.Option) *repository.MemoryRepository {
+		repo, err := repository.NewMemoryRepository(sorting.NewSorterFactory(), options...)
+		require.NoError(t, err)
+		for _, product := range generateLargeProductCollection(10) {
+			product := product
+			require.NoError(t, repo.SaveProduct(ctx, &product))
+		}
+		return repo
+	}
+
+	t.Run("Expired Promotions Keep Indexes", func(t *testing.T) {
+		repo := newRepo(t, repository.WithPromotions(staticPromotions{expired}))
+
+		page, err := repo.GetSortedPage(ctx, catalog.SortByPriceAsc, 0, 5)
+		require.NoError(t, err)
+		assert.Len(t, page, 5)
+	})
+
+	t.Run("Active Promotion Refuses Price Strategies", func(t *testing.T) {
+		repo := newRepo(t, repository.WithPromotions(staticPromotions{expired, running}))
+
+		_, err := repo.GetSortedPage(ctx, catalog.SortByPriceAsc, 0, 5)
+		assert.ErrorIs(t, err, catalog.ErrIndexStale)
+
+		_, err = repo.GetSortedPageAfter(ctx, catalog.SortByRevenue, catalog.PageRequest{Limit: 5})
+		assert.ErrorIs(t, err, catalog.ErrIndexStale)
+
+		page, err := repo.GetSortedPage(ctx, catalog.SortByPopularity, 0, 5)
+		require.NoError(t, err)
+		assert.Len(t, page, 5)
+	})
+
+	t.Run("Exchange Rates Refuse Amount Strategies", func(t *testing.T) {
+		repo := newRepo(t, repository.WithExchangeRates(staticRateProvider{snapshot: testRateSnapshot()}))
+
+		_, err := repo.GetSortedPage(ctx, catalog.SortByPriceDesc, 0, 5)
+		assert.ErrorIs(t, err, catalog.ErrIndexStale)
+
+		page, err := repo.GetSortedPage(ctx, catalog.SortByName, 0, 5)
+		require.NoError(t, err)
+		assert.Len(t, page, 5)
+	})
+}
@@ -20,9 +20,9 @@ func TestService_SortProducts_Comprehensive(t *testing.T) {
 	service := catalog.NewService(factory, logger)
 
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Expensive", Price: 100.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
-		{ID: 2, Name: "Cheap", Price: 10.0, CreatedAt: time.Now(), SalesCount: 50, ViewsCount: 200},
-		{ID: 3, Name: "Medium", Price: 50.0, CreatedAt: time.Now(), SalesCount: 30, ViewsCount: 150},
+		{ID: 1, Name: "Expensive", Price: catalog.NewMoney(10000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+		{ID: 2, Name: "Cheap", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 50, ViewsCount: 200},
+		{ID: 3, Name: "Medium", Price: catalog.NewMoney(5000, catalog.USD), CreatedAt: time.Now(), SalesCount: 30, ViewsCount: 150},
 	}
 
 	ctx := context.Background()
@@ -54,9 +54,9 @@ func TestService_SortProducts_Comprehensive(t *testing.T) {
 			result, err := service.SortProducts(ctx, products, catalog.SortByPriceAsc)
 			require.NoError(t, err)
 
-			assert.Equal(t, catalog.Price(10.0), result.Products[0].Price)
-			assert.Equal(t, catalog.Price(50.0), result.Products[1].Price)
-			assert.Equal(t, catalog.Price(100.0), result.Products[2].Price)
+			assert.Equal(t, catalog.NewMoney(1000, catalog.USD), result.Products[0].Price)
+			assert.Equal(t, catalog.NewMoney(5000, catalog.USD), result.Products[1].Price)
+			assert.Equal(t, catalog.NewMoney(10000, catalog.USD), result.Products[2].Price)
 		})
 
 		t.Run("SortBySalesConversionRatio", func(t *testing.T) {
@@ -85,7 +85,7 @@ func TestService_SortProducts_Comprehensive(t *testing.T) {
 
 		t.Run("Invalid Products", func(t *testing.T) {
 			invalidProducts := catalog.ProductCollection{
-				{ID: 0, Name: "", Price: -10.0, CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1},
+				{ID: 0, Name: "", Price: catalog.NewMoney(-1000, catalog.USD), CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1},
 			}
 
 			_, err := service.SortProducts(ctx, invalidProducts, catalog.SortByPriceAsc)
@@ -103,7 +103,7 @@ func TestService_SortProducts_Comprehensive(t *testing.T) {
 
 	t.Run("Single Product", func(t *testing.T) {
 		singleProduct := catalog.ProductCollection{
-			{ID: 1, Name: "Only One", Price: 42.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+			{ID: 1, Name: "Only One", Price: catalog.NewMoney(4200, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
 		}
 
 		result, err := service.SortProducts(ctx, singleProduct, catalog.SortByPriceAsc)
@@ -119,9 +119,9 @@ func TestService_BatchSort_Comprehensive(t *testing.T) {
 	service := catalog.NewService(factory, logger)
 
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product A", Price: 20.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
-		{ID: 2, Name: "Product B", Price: 10.0, CreatedAt: time.Now().AddDate(0, 0, -1), SalesCount: 20, ViewsCount: 150},
-		{ID: 3, Name: "Product C", Price: 30.0, CreatedAt: time.Now().AddDate(0, 0, -2), SalesCount: 5, ViewsCount: 80},
+		{ID: 1, Name: "Product A", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+		{ID: 2, Name: "Product B", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now().AddDate(0, 0, -1), SalesCount: 20, ViewsCount: 150},
+		{ID: 3, Name: "Product C", Price: catalog.NewMoney(3000, catalog.USD), CreatedAt: time.Now().AddDate(0, 0, -2), SalesCount: 5, ViewsCount: 80},
 	}
 
 	ctx := context.Background()
@@ -170,7 +170,7 @@ func TestService_BatchSort_Comprehensive(t *testing.T) {
 
 		priceResult, exists := result.GetResult(catalog.SortByPriceAsc)
 		assert.True(t, exists)
-		assert.Equal(t, catalog.Price(10.0), priceResult.Products[0].Price)
+		assert.Equal(t, catalog.NewMoney(1000, catalog.USD), priceResult.Products[0].Price)
 	})
 
 	t.Run("All Strategies Batch", func(t *testing.T) {
@@ -217,7 +217,7 @@ func TestService_BatchSort_Comprehensive(t *testing.T) {
 
 		t.Run("Invalid Products", func(t *testing.T) {
 			invalidProducts := catalog.ProductCollection{
-				{ID: 0, Name: "", Price: -10.0},
+				{ID: 0, Name: "", Price: catalog.NewMoney(-1000, catalog.USD)},
 			}
 			strategies := catalog.NewSortStrategySet(catalog.SortByPriceAsc)
 
@@ -252,8 +252,8 @@ func TestService_ValidateProducts_Comprehensive(t *testing.T) {
 
 	t.Run("Valid Products", func(t *testing.T) {
 		validProducts := catalog.ProductCollection{
-			{ID: 1, Name: "Valid Product 1", Price: 10.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
-			{ID: 2, Name: "Valid Product 2", Price: 20.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+			{ID: 1, Name: "Valid Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+			{ID: 2, Name: "Valid Product 2", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
 		}
 
 		err := service.ValidateProducts(ctx, validProducts)
@@ -262,8 +262,8 @@ func TestService_ValidateProducts_Comprehensive(t *testing.T) {
 
 	t.Run("Invalid Products", func(t *testing.T) {
 		invalidProducts := catalog.ProductCollection{
-			{ID: 1, Name: "Valid Product", Price: 10.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
-			{ID: 0, Name: "", Price: -10.0, CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1},
+			{ID: 1, Name: "Valid Product", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+			{ID: 0, Name: "", Price: catalog.NewMoney(-1000, catalog.USD), CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1},
 		}
 
 		err := service.ValidateProducts(ctx, invalidProducts)
@@ -284,9 +284,9 @@ func TestService_ValidateProducts_Comprehensive(t *testing.T) {
 
 	t.Run("Mixed Valid and Invalid", func(t *testing.T) {
 		mixedProducts := catalog.ProductCollection{
-			{ID: 1, Name: "Valid", Price: 10.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
-			{ID: 2, Name: "Also Valid", Price: 20.0, CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
-			{ID: 0, Name: "", Price: -5.0, CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1}, // Invalid
+			{ID: 1, Name: "Valid", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+			{ID: 2, Name: "Also Valid", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now(), SalesCount: 10, ViewsCount: 100},
+			{ID: 0, Name: "", Price: catalog.NewMoney(-500, catalog.USD), CreatedAt: time.Time{}, SalesCount: -1, ViewsCount: -1}, // Invalid
 		}
 
 		err := service.ValidateProducts(ctx, mixedProducts)
@@ -323,7 +323,7 @@ func TestService_ContextHandling(t *testing.T) {
 	service := catalog.NewService(factory, logger)
 
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product", Price: 10.0, CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
+		{ID: 1, Name: "Product", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now(), SalesCount: 5, ViewsCount: 50},
 	}
 
 	t.Run("Cancelled Context", func(t *testing.T) {
@@ -462,9 +462,9 @@ func TestService_EdgeCases(t *testing.T) {
 
 	t.Run("Products with Extreme Values", func(t *testing.T) {
 		extremeProducts := catalog.ProductCollection{
-			{ID: 1, Name: "Max Values", Price: 999999.99, SalesCount: 1000000, ViewsCount: 1000000, CreatedAt: time.Now()},
-			{ID: 2, Name: "Min Values", Price: 0.01, SalesCount: 1, ViewsCount: 1, CreatedAt: time.Now().AddDate(-10, 0, 0)},
-			{ID: 3, Name: "Zero Values", Price: 0.0, SalesCount: 0, ViewsCount: 1, CreatedAt: time.Now()},
+			{ID: 1, Name: "Max Values", Price: catalog.NewMoney(99999999, catalog.USD), SalesCount: 1000000, ViewsCount: 1000000, CreatedAt: time.Now()},
+			{ID: 2, Name: "Min Values", Price: catalog.NewMoney(1, catalog.USD), SalesCount: 1, ViewsCount: 1, CreatedAt: time.Now().AddDate(-10, 0, 0)},
+			{ID: 3, Name: "Zero Values", Price: catalog.NewMoney(0, catalog.USD), SalesCount: 0, ViewsCount: 1, CreatedAt: time.Now()},
 		}
 
 		strategies := catalog.AllSortStrategies()
@@ -477,9 +477,9 @@ func TestService_EdgeCases(t *testing.T) {
 
 	t.Run("Products with Same Values", func(t *testing.T) {
 		identicalProducts := catalog.ProductCollection{
-			{ID: 1, Name: "Same Product", Price: 10.0, SalesCount: 5, ViewsCount: 50, CreatedAt: time.Now()},
-			{ID: 2, Name: "Same Product", Price: 10.0, SalesCount: 5, ViewsCount: 50, CreatedAt: time.Now()},
-			{ID: 3, Name: "Same Product", Price: 10.0, SalesCount: 5, ViewsCount: 50, CreatedAt: time.Now()},
+			{ID: 1, Name: "Same Product", Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 5, ViewsCount: 50, CreatedAt: time.Now()},
+			{ID: 2, Name: "Same Product", Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 5, ViewsCount: 50, CreatedAt: time.Now()},
+			{ID: 3, Name: "Same Product", Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 5, ViewsCount: 50, CreatedAt: time.Now()},
 		}
 
 		strategies := catalog.AllSortStrategies()
@@ -509,7 +509,7 @@ func generateLargeProductCollection(size int) catalog.ProductCollection {
 		products[i] = catalog.Product{
 			ID:         catalog.ProductID(i + 1),
 			Name:       fmt.Sprintf("Product %d", i+1),
-			Price:      catalog.Price(10 + float64(i%100)),
+			Price:      catalog.NewMoney(int64(10+i%100)*100, catalog.USD),
 			CreatedAt:  now.Add(-time.Duration(i%365) * 24 * time.Hour),
 			SalesCount: (i%500 + 1),
 			ViewsCount: (i%2000 + 100),
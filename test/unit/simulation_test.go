@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+// simulationBatch ranks a cheap product (10.00, 10 sales in 100 views) and an
+// expensive one (50.00, 20 sales in 100 views) in both orders
+func simulationBatch(currency catalog.Currency) *catalog.BatchSortResult {
+	cheap := catalog.Product{ID: 1, Name: "Cheap", Price: catalog.NewMoney(1000, catalog.USD),
+		SalesCount: 10, ViewsCount: 100, CreatedAt: time.Now()}
+	expensive := catalog.Product{ID: 2, Name: "Expensive", Price: catalog.NewMoney(5000, currency),
+		SalesCount: 20, ViewsCount: 100, CreatedAt: time.Now()}
+
+	return catalog.NewBatchSortResult(map[catalog.SortStrategy]*catalog.SortResult{
+		catalog.SortByPriceAsc:  catalog.NewSortResult(catalog.ProductCollection{cheap, expensive}, catalog.SortByPriceAsc, 0),
+		catalog.SortByPriceDesc: catalog.NewSortResult(catalog.ProductCollection{expensive, cheap}, catalog.SortByPriceDesc, 0),
+	}, 0)
+}
+
+func TestSimulateRankings(t *testing.T) {
+	options := catalog.SimulationOptions{K: 2, Runs: 100, Sessions: 1000, Seed: 7}
+
+	t.Run("Expected Values", func(t *testing.T) {
+		report, err := catalog.SimulateRankings(simulationBatch(catalog.USD), options)
+		require.NoError(t, err)
+		require.Len(t, report.Strategies, 2)
+		assert.Equal(t, catalog.SortByPriceAsc, report.Baseline)
+
+		// The catalog converts 30 of 200 views, so with 100 prior views the
+		// cheap product converts 25/200 and the expensive one 35/200. Slot 2
+		// is examined half as often as slot 1 and examined slots are clicked
+		// 20% of the time.
+		best := report.Strategies[0]
+		assert.Equal(t, catalog.SortByPriceDesc, best.Strategy)
+		assert.Equal(t, 1, best.Rank)
+		assert.Equal(t, catalog.USD, best.Currency)
+		assert.InDelta(t, 300, best.Clicks.Expected, 1e-9)
+		assert.InDelta(t, 1000*(0.2*0.175+0.1*0.125), best.Sales.Expected, 1e-9)
+		assert.InDelta(t, 1000*(0.2*0.175*50+0.1*0.125*10), best.Revenue.Expected, 1e-9)
+
+		require.Len(t, best.Slots, 2)
+		assert.Equal(t, catalog.ProductID(2), best.Slots[0].ProductID)
+		assert.InDelta(t, 0.5, best.Slots[1].Examination, 1e-9)
+		assert.InDelta(t, 0.125, best.Slots[1].Conversion, 1e-9)
+
+		baseline, ok := report.Strategy(catalog.SortByPriceAsc)
+		require.True(t, ok)
+		assert.InDelta(t, 1000*(0.2*0.125*10+0.1*0.175*50), baseline.Revenue.Expected, 1e-9)
+		assert.Zero(t, baseline.RevenueDifference.Mean)
+		assert.InDelta(t, best.Revenue.Expected-baseline.Revenue.Expected, best.RevenueDifference.Expected, 1e-9)
+	})
+
+	t.Run("Monte Carlo Intervals", func(t *testing.T) {
+		report, err := catalog.SimulateRankings(simulationBatch(catalog.USD), options)
+		require.NoError(t, err)
+
+		for _, simulation := range report.Strategies {
+			for _, estimate := range []catalog.SimulationEstimate{simulation.Clicks, simulation.Sales, simulation.Revenue} {
+				assert.LessOrEqual(t, estimate.Interval.Lower, estimate.Mean)
+				assert.GreaterOrEqual(t, estimate.Interval.Upper, estimate.Mean)
+				assert.InDelta(t, estimate.Expected, estimate.Mean, 0.1*estimate.Expected)
+			}
+		}
+
+		// Both strategies see the same shoppers, so the better one wins every run
+		assert.Greater(t, report.Strategies[0].RevenueDifference.Interval.Lower, 0.0)
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		first, err := catalog.SimulateRankings(simulationBatch(catalog.USD), options)
+		require.NoError(t, err)
+		second, err := catalog.SimulateRankings(simulationBatch(catalog.USD), options)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+
+		options.Seed = 8
+		third, err := catalog.SimulateRankings(simulationBatch(catalog.USD), options)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.Strategies[0].Revenue.Mean, third.Strategies[0].Revenue.Mean)
+	})
+
+	t.Run("Examination Probabilities", func(t *testing.T) {
+		custom := options
+		click := 0.5
+		custom.Examination = catalog.ExaminationModel{Probabilities: []float64{1}, ClickProbability: &click}
+		report, err := catalog.SimulateRankings(simulationBatch(catalog.USD), custom)
+		require.NoError(t, err)
+
+		best := report.Strategies[0]
+		assert.Zero(t, best.Slots[1].Examination)
+		assert.InDelta(t, 500, best.Clicks.Expected, 1e-9)
+	})
+
+	t.Run("Explicit Zeros", func(t *testing.T) {
+		zero := 0.0
+		custom := options
+		custom.Examination = catalog.ExaminationModel{Decay: &zero}
+		custom.ConversionPriorViews = &zero
+		report, err := catalog.SimulateRankings(simulationBatch(catalog.USD), custom)
+		require.NoError(t, err)
+
+		// Every slot is examined and conversion is the raw 20/100 and 10/100
+		descending, ok := report.Strategy(catalog.SortByPriceDesc)
+		require.True(t, ok)
+		assert.InDelta(t, 1, descending.Slots[1].Examination, 1e-9)
+		assert.InDelta(t, 0.2, descending.Slots[0].Conversion, 1e-9)
+		assert.InDelta(t, 0.1, descending.Slots[1].Conversion, 1e-9)
+
+		custom.Examination.ClickProbability = &zero
+		report, err = catalog.SimulateRankings(simulationBatch(catalog.USD), custom)
+		require.NoError(t, err)
+		assert.Zero(t, report.Strategies[0].Clicks.Mean)
+	})
+
+	t.Run("Invalid Options", func(t *testing.T) {
+		tooLikely, negative := 1.5, -1.0
+		for _, invalid := range []catalog.SimulationOptions{
+			{K: -1},
+			{Examination: catalog.ExaminationModel{ClickProbability: &tooLikely}},
+			{Examination: catalog.ExaminationModel{Decay: &negative}},
+			{ConversionPriorViews: &negative},
+			{Examination: catalog.ExaminationModel{Probabilities: []float64{0.5, -0.1}}},
+			{ConfidenceLevel: 1},
+			{Baseline: catalog.SortByRevenue},
+		} {
+			_, err := catalog.SimulateRankings(simulationBatch(catalog.USD), invalid)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Mixed Currencies", func(t *testing.T) {
+		_, err := catalog.SimulateRankings(simulationBatch(catalog.EUR), options)
+		assert.ErrorIs(t, err, catalog.ErrCurrencyMismatch)
+	})
+}
+
+func TestService_SimulateStrategies(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := generateLargeProductCollection(50)
+
+	strategies := catalog.SortStrategySet{catalog.SortByRevenue, catalog.SortByPriceAsc, catalog.SortBySalesConversionRatio}
+	report, err := service.SimulateStrategies(context.Background(), products, strategies,
+		catalog.SimulationOptions{K: 5, Runs: 20, Sessions: 100, Seed: 1})
+	require.NoError(t, err)
+	assert.Len(t, report.Strategies, 3)
+	assert.Equal(t, catalog.SortByRevenue, report.Baseline)
+
+	for i := 1; i < len(report.Strategies); i++ {
+		assert.GreaterOrEqual(t, report.Strategies[i-1].Revenue.Expected, report.Strategies[i].Revenue.Expected)
+	}
+}
@@ -11,8 +11,8 @@ import (
 
 func TestNewSortResult(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
-		{ID: 2, Name: "Product 2", Price: 20.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 2, Name: "Product 2", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	strategy := catalog.SortByPriceAsc
@@ -30,8 +30,8 @@ func TestNewSortResult(t *testing.T) {
 
 func TestSortResult_Validate(t *testing.T) {
 	validProducts := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
-		{ID: 2, Name: "Product 2", Price: 20.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 2, Name: "Product 2", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	tests := []struct {
@@ -125,11 +125,11 @@ func TestSortResult_Validate(t *testing.T) {
 
 func TestSortResult_GetTopProducts(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "First", Price: 10.0},
-		{ID: 2, Name: "Second", Price: 20.0},
-		{ID: 3, Name: "Third", Price: 30.0},
-		{ID: 4, Name: "Fourth", Price: 40.0},
-		{ID: 5, Name: "Fifth", Price: 50.0},
+		{ID: 1, Name: "First", Price: catalog.NewMoney(1000, catalog.USD)},
+		{ID: 2, Name: "Second", Price: catalog.NewMoney(2000, catalog.USD)},
+		{ID: 3, Name: "Third", Price: catalog.NewMoney(3000, catalog.USD)},
+		{ID: 4, Name: "Fourth", Price: catalog.NewMoney(4000, catalog.USD)},
+		{ID: 5, Name: "Fifth", Price: catalog.NewMoney(5000, catalog.USD)},
 	}
 
 	result := catalog.NewSortResult(products, catalog.SortByPriceAsc, time.Millisecond)
@@ -166,7 +166,7 @@ func TestSortResult_GetTopProducts(t *testing.T) {
 
 func TestSortResult_String(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	result := catalog.NewSortResult(products, catalog.SortByPriceAsc, 50*time.Millisecond)
@@ -180,8 +180,8 @@ func TestSortResult_String(t *testing.T) {
 
 func TestNewBatchSortResult(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
-		{ID: 2, Name: "Product 2", Price: 20.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 2, Name: "Product 2", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	results := map[catalog.SortStrategy]*catalog.SortResult{
@@ -203,7 +203,7 @@ func TestNewBatchSortResult(t *testing.T) {
 
 func TestBatchSortResult_GetResult(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	result1 := catalog.NewSortResult(products, catalog.SortByPriceAsc, 30*time.Millisecond)
@@ -229,7 +229,7 @@ func TestBatchSortResult_GetResult(t *testing.T) {
 
 func TestBatchSortResult_Validate(t *testing.T) {
 	validProducts := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	validResult := catalog.NewSortResult(validProducts, catalog.SortByPriceAsc, 30*time.Millisecond)
@@ -319,7 +319,7 @@ func TestBatchSortResult_Validate(t *testing.T) {
 
 func TestBatchSortResult_String(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	results := map[catalog.SortStrategy]*catalog.SortResult{
@@ -350,7 +350,7 @@ func TestSortResult_EdgeCases(t *testing.T) {
 
 	t.Run("Zero execution time", func(t *testing.T) {
 		products := catalog.ProductCollection{
-			{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+			{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 		}
 
 		result := catalog.NewSortResult(products, catalog.SortByPriceAsc, 0)
@@ -375,7 +375,7 @@ func TestBatchSortResult_EdgeCases(t *testing.T) {
 
 	t.Run("Single strategy batch", func(t *testing.T) {
 		products := catalog.ProductCollection{
-			{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+			{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 		}
 
 		results := map[catalog.SortStrategy]*catalog.SortResult{
@@ -391,8 +391,8 @@ func TestBatchSortResult_EdgeCases(t *testing.T) {
 // Benchmark tests
 func BenchmarkSortResult_Validate(b *testing.B) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
-		{ID: 2, Name: "Product 2", Price: 20.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 2, Name: "Product 2", Price: catalog.NewMoney(2000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	result := catalog.NewSortResult(products, catalog.SortByPriceAsc, 50*time.Millisecond)
@@ -409,7 +409,7 @@ func BenchmarkSortResult_GetTopProducts(b *testing.B) {
 		products[i] = catalog.Product{
 			ID:        catalog.ProductID(i + 1),
 			Name:      "Product",
-			Price:     catalog.Price(float64(i + 1)),
+			Price:     catalog.NewMoney(int64(i+1)*100, catalog.USD),
 			CreatedAt: time.Now(),
 		}
 	}
@@ -424,7 +424,7 @@ func BenchmarkSortResult_GetTopProducts(b *testing.B) {
 
 func BenchmarkBatchSortResult_Validate(b *testing.B) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Product 1", Price: 10.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Product 1", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	results := map[catalog.SortStrategy]*catalog.SortResult{
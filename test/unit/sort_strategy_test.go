@@ -21,6 +21,10 @@ func TestSortStrategy_String(t *testing.T) {
 		{catalog.SortByPopularity, "popularity"},
 		{catalog.SortByRevenue, "revenue"},
 		{catalog.SortByName, "name"},
+		{catalog.SortByMargin, "margin"},
+		{catalog.SortByProfit, "profit"},
+		{catalog.SortByProfitPerView, "profit_per_view"},
+		{catalog.SortByRating, "top_rated"},
 	}
 
 	for _, tt := range tests {
@@ -40,6 +44,10 @@ func TestSortStrategy_IsValid(t *testing.T) {
 		catalog.SortByPopularity,
 		catalog.SortByRevenue,
 		catalog.SortByName,
+		catalog.SortByMargin,
+		catalog.SortByProfit,
+		catalog.SortByProfitPerView,
+		catalog.SortByRating,
 	}
 
 	for _, strategy := range validStrategies {
@@ -74,6 +82,10 @@ func TestSortStrategy_Description(t *testing.T) {
 		{catalog.SortByPopularity, "Popularity (Most Viewed First)"},
 		{catalog.SortByRevenue, "Revenue Generated (Highest First)"},
 		{catalog.SortByName, "Name (Alphabetical)"},
+		{catalog.SortByMargin, "Gross Margin (Highest First)"},
+		{catalog.SortByProfit, "Total Profit (Highest First)"},
+		{catalog.SortByProfitPerView, "Profit per View (Highest First)"},
+		{catalog.SortByRating, "Top Rated (Bayesian Average)"},
 		{catalog.SortStrategy("unknown"), "Unknown Strategy (unknown)"},
 	}
 
@@ -97,6 +109,10 @@ func TestSortStrategy_Priority(t *testing.T) {
 		{catalog.SortByCreatedAtDesc, 6},
 		{catalog.SortByCreatedAtAsc, 5},
 		{catalog.SortByName, 4},
+		{catalog.SortByProfit, 9},
+		{catalog.SortByMargin, 8},
+		{catalog.SortByProfitPerView, 8},
+		{catalog.SortByRating, 8},
 		{catalog.SortStrategy("unknown"), 1},
 	}
 
@@ -120,6 +136,10 @@ func TestAllSortStrategies(t *testing.T) {
 		catalog.SortByPopularity,
 		catalog.SortByRevenue,
 		catalog.SortByName,
+		catalog.SortByMargin,
+		catalog.SortByProfit,
+		catalog.SortByProfitPerView,
+		catalog.SortByRating,
 	}
 
 	assert.Len(t, strategies, len(expectedStrategies))
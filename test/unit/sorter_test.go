@@ -15,11 +15,11 @@ import (
 
 func TestPriceSorter_Comprehensive(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Expensive", Price: 100.0, CreatedAt: time.Now()},
-		{ID: 2, Name: "Cheap", Price: 10.0, CreatedAt: time.Now()},
-		{ID: 3, Name: "Medium", Price: 50.0, CreatedAt: time.Now()},
-		{ID: 4, Name: "Same Price 1", Price: 25.0, CreatedAt: time.Now()},
-		{ID: 5, Name: "Same Price 2", Price: 25.0, CreatedAt: time.Now()},
+		{ID: 1, Name: "Expensive", Price: catalog.NewMoney(10000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 2, Name: "Cheap", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 3, Name: "Medium", Price: catalog.NewMoney(5000, catalog.USD), CreatedAt: time.Now()},
+		{ID: 4, Name: "Same Price 1", Price: catalog.NewMoney(2500, catalog.USD), CreatedAt: time.Now()},
+		{ID: 5, Name: "Same Price 2", Price: catalog.NewMoney(2500, catalog.USD), CreatedAt: time.Now()},
 	}
 
 	ctx := context.Background()
@@ -32,7 +32,9 @@ func TestPriceSorter_Comprehensive(t *testing.T) {
 		require.Len(t, sorted, 5)
 
 		// Verify ascending order
-		expectedPrices := []catalog.Price{10.0, 25.0, 25.0, 50.0, 100.0}
+		expectedPrices := []catalog.Price{
+			catalog.NewMoney(1000, catalog.USD), catalog.NewMoney(2500, catalog.USD), catalog.NewMoney(2500, catalog.USD), catalog.NewMoney(5000, catalog.USD), catalog.NewMoney(10000, catalog.USD),
+		}
 		for i, expected := range expectedPrices {
 			assert.Equal(t, expected, sorted[i].Price, "Position %d should have price %v", i, expected)
 		}
@@ -50,7 +52,9 @@ func TestPriceSorter_Comprehensive(t *testing.T) {
 		require.Len(t, sorted, 5)
 
 		// Verify descending order
-		expectedPrices := []catalog.Price{100.0, 50.0, 25.0, 25.0, 10.0}
+		expectedPrices := []catalog.Price{
+			catalog.NewMoney(10000, catalog.USD), catalog.NewMoney(5000, catalog.USD), catalog.NewMoney(2500, catalog.USD), catalog.NewMoney(2500, catalog.USD), catalog.NewMoney(1000, catalog.USD),
+		}
 		for i, expected := range expectedPrices {
 			assert.Equal(t, expected, sorted[i].Price, "Position %d should have price %v", i, expected)
 		}
@@ -70,7 +74,7 @@ func TestPriceSorter_Comprehensive(t *testing.T) {
 
 	t.Run("Single Item", func(t *testing.T) {
 		singleProduct := catalog.ProductCollection{
-			{ID: 1, Name: "Only One", Price: 42.0, CreatedAt: time.Now()},
+			{ID: 1, Name: "Only One", Price: catalog.NewMoney(4200, catalog.USD), CreatedAt: time.Now()},
 		}
 
 		sorter := sorting.NewPriceSorter(true)
@@ -78,7 +82,7 @@ func TestPriceSorter_Comprehensive(t *testing.T) {
 
 		require.NoError(t, err)
 		require.Len(t, sorted, 1)
-		assert.Equal(t, catalog.Price(42.0), sorted[0].Price)
+		assert.Equal(t, catalog.NewMoney(4200, catalog.USD), sorted[0].Price)
 	})
 
 	t.Run("Immutability Check", func(t *testing.T) {
@@ -308,11 +312,11 @@ func TestPopularitySorter_Comprehensive(t *testing.T) {
 
 func TestRevenueSorter_Comprehensive(t *testing.T) {
 	products := catalog.ProductCollection{
-		{ID: 1, Name: "Low Revenue", Price: 10.0, SalesCount: 5, CreatedAt: time.Now()},     // 50.0
-		{ID: 2, Name: "High Revenue", Price: 100.0, SalesCount: 10, CreatedAt: time.Now()},  // 1000.0
-		{ID: 3, Name: "Medium Revenue", Price: 25.0, SalesCount: 8, CreatedAt: time.Now()},  // 200.0
-		{ID: 4, Name: "Same Revenue A", Price: 20.0, SalesCount: 5, CreatedAt: time.Now()},  // 100.0
-		{ID: 5, Name: "Same Revenue B", Price: 10.0, SalesCount: 10, CreatedAt: time.Now()}, // 100.0
+		{ID: 1, Name: "Low Revenue", Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 5, CreatedAt: time.Now()},     // 50.0
+		{ID: 2, Name: "High Revenue", Price: catalog.NewMoney(10000, catalog.USD), SalesCount: 10, CreatedAt: time.Now()},  // 1000.0
+		{ID: 3, Name: "Medium Revenue", Price: catalog.NewMoney(2500, catalog.USD), SalesCount: 8, CreatedAt: time.Now()},  // 200.0
+		{ID: 4, Name: "Same Revenue A", Price: catalog.NewMoney(2000, catalog.USD), SalesCount: 5, CreatedAt: time.Now()},  // 100.0
+		{ID: 5, Name: "Same Revenue B", Price: catalog.NewMoney(1000, catalog.USD), SalesCount: 10, CreatedAt: time.Now()}, // 100.0
 	}
 
 	ctx := context.Background()
@@ -325,7 +329,10 @@ func TestRevenueSorter_Comprehensive(t *testing.T) {
 		require.Len(t, sorted, 5)
 
 		// Verify descending order by revenue
-		expectedRevenues := []float64{1000.0, 200.0, 100.0, 100.0, 50.0}
+		expectedRevenues := []catalog.Money{
+			catalog.NewMoney(100000, catalog.USD), catalog.NewMoney(20000, catalog.USD), catalog.NewMoney(10000, catalog.USD),
+			catalog.NewMoney(10000, catalog.USD), catalog.NewMoney(5000, catalog.USD),
+		}
 		for i, expected := range expectedRevenues {
 			actual := sorted[i].RevenueGenerated()
 			assert.Equal(t, expected, actual, "Position %d should have revenue %v", i, expected)
@@ -342,7 +349,7 @@ func TestRevenueSorter_Comprehensive(t *testing.T) {
 		// Find products with same revenue (100.0)
 		var sameRevenueProducts []catalog.Product
 		for _, product := range sorted {
-			if product.RevenueGenerated() == 100.0 {
+			if product.RevenueGenerated() == catalog.NewMoney(10000, catalog.USD) {
 				sameRevenueProducts = append(sameRevenueProducts, product)
 			}
 		}
@@ -511,7 +518,7 @@ func TestSorter_EdgeCases(t *testing.T) {
 
 	t.Run("Nil Context", func(t *testing.T) {
 		products := catalog.ProductCollection{
-			{ID: 1, Name: "Product", Price: 10.0, CreatedAt: time.Now()},
+			{ID: 1, Name: "Product", Price: catalog.NewMoney(1000, catalog.USD), CreatedAt: time.Now()},
 		}
 
 		sorter := sorting.NewPriceSorter(true)
@@ -523,8 +530,8 @@ func TestSorter_EdgeCases(t *testing.T) {
 
 	t.Run("Extreme Values", func(t *testing.T) {
 		products := catalog.ProductCollection{
-			{ID: 1, Name: "Max Price", Price: 999999.99, SalesCount: 1000000, ViewsCount: 1000000, CreatedAt: time.Now()},
-			{ID: 2, Name: "Min Price", Price: 0.01, SalesCount: 1, ViewsCount: 1, CreatedAt: time.Now()},
+			{ID: 1, Name: "Max Price", Price: catalog.NewMoney(99999999, catalog.USD), SalesCount: 1000000, ViewsCount: 1000000, CreatedAt: time.Now()},
+			{ID: 2, Name: "Min Price", Price: catalog.NewMoney(1, catalog.USD), SalesCount: 1, ViewsCount: 1, CreatedAt: time.Now()},
 		}
 
 		sorters := []catalog.Sorter{
@@ -551,7 +558,7 @@ func generateLargeProductSet(size int) catalog.ProductCollection {
 		products[i] = catalog.Product{
 			ID:         catalog.ProductID(i + 1),
 			Name:       fmt.Sprintf("Product %d", i+1),
-			Price:      catalog.Price(10 + float64(i%100)),
+			Price:      catalog.NewMoney(int64(10+i%100)*100, catalog.USD),
 			CreatedAt:  now.Add(-time.Duration(i%365) * 24 * time.Hour),
 			SalesCount: (i%500 + 1),
 			ViewsCount: (i%2000 + 100),
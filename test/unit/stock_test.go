@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/repository"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+// stockTestProducts are ordered by descending sales, so SortByPopularity keeps this order
+func stockTestProducts() catalog.ProductCollection {
+	return catalog.ProductCollection{
+		testdata.Product(1, testdata.WithSales(600), testdata.WithStock(catalog.OutOfStock, 0)),
+		testdata.Product(2, testdata.WithSales(500), testdata.WithStock(catalog.InStock, 40)),
+		testdata.Product(3, testdata.WithSales(400), testdata.WithStock(catalog.AvailabilityUntracked, 0)),
+		testdata.Product(4, testdata.WithSales(300), testdata.WithStock(catalog.InStock, 2)),
+		testdata.Product(5, testdata.WithSales(200), testdata.WithStock(catalog.Backorder, 0)),
+		testdata.Product(6, testdata.WithSales(100), testdata.WithStock(catalog.InStock, 90)),
+	}
+}
+
+func TestStockPolicy_Apply(t *testing.T) {
+	products := stockTestProducts()
+
+	tests := []struct {
+		name     string
+		policy   catalog.StockPolicy
+		expected []catalog.ProductID
+	}{
+		{"Bury", catalog.StockPolicy{Mode: catalog.DemoteBury}, []catalog.ProductID{2, 3, 4, 6, 1, 5}},
+		{"Bury Low Stock", catalog.StockPolicy{Mode: catalog.DemoteBury, LowStockThreshold: 5}, []catalog.ProductID{2, 3, 6, 1, 4, 5}},
+		{"Drop Two", catalog.StockPolicy{Mode: catalog.DemoteDrop, Positions: 2}, []catalog.ProductID{2, 3, 1, 4, 6, 5}},
+		{"Drop Past End", catalog.StockPolicy{Mode: catalog.DemoteDrop, Positions: 100}, []catalog.ProductID{2, 3, 4, 6, 1, 5}},
+		{"Exclude", catalog.StockPolicy{Mode: catalog.DemoteExclude}, []catalog.ProductID{2, 3, 4, 6}},
+		{"Exclude Low Stock", catalog.StockPolicy{Mode: catalog.DemoteExclude, LowStockThreshold: 2}, []catalog.ProductID{2, 3, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.policy.Validate())
+			assert.Equal(t, tt.expected, productIDs(tt.policy.Apply(products)))
+		})
+	}
+
+	t.Run("Input Is Not Modified", func(t *testing.T) {
+		catalog.StockPolicy{Mode: catalog.DemoteBury}.Apply(products)
+		assert.Equal(t, []catalog.ProductID{1, 2, 3, 4, 5, 6}, productIDs(products))
+	})
+
+	t.Run("Invalid Policies", func(t *testing.T) {
+		assert.Error(t, catalog.StockPolicy{Mode: "hide"}.Validate())
+		assert.Error(t, catalog.StockPolicy{Mode: catalog.DemoteDrop}.Validate())
+		assert.Error(t, catalog.StockPolicy{Mode: catalog.DemoteBury, LowStockThreshold: -1}.Validate())
+	})
+}
+
+func TestService_SortProductsWithStockPolicy(t *testing.T) {
+	ctx := context.Background()
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := stockTestProducts()
+
+	for _, strategy := range []catalog.SortStrategy{catalog.SortByPopularity, catalog.SortByRevenue, catalog.SortByName} {
+		t.Run(string(strategy), func(t *testing.T) {
+			result, err := service.SortProductsWithOptions(ctx, products, strategy, catalog.SortOptions{
+				Stock: &catalog.StockPolicy{Mode: catalog.DemoteBury},
+			})
+			require.NoError(t, err)
+			require.Len(t, result.Products, len(products))
+
+			// No in-stock product follows a demoted one
+			seenDemoted := false
+			for _, product := range result.Products {
+				if !product.IsInStock() {
+					seenDemoted = true
+					continue
+				}
+				assert.False(t, seenDemoted, "Product %d is ranked below an out-of-stock product", product.ID)
+			}
+		})
+	}
+
+	t.Run("Exclude Updates Count", func(t *testing.T) {
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPopularity, catalog.SortOptions{
+			Stock: &catalog.StockPolicy{Mode: catalog.DemoteExclude},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.ProductCount)
+		assert.NoError(t, result.Validate())
+	})
+
+	t.Run("Invalid Policy", func(t *testing.T) {
+		_, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPopularity, catalog.SortOptions{
+			Stock: &catalog.StockPolicy{Mode: catalog.DemoteDrop},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestProduct_InventoryValidation(t *testing.T) {
+	product := stockTestProducts()[1]
+	require.NoError(t, product.Validate())
+
+	product.StockQuantity = -1
+	assert.Error(t, product.Validate())
+
+	product.StockQuantity = 1
+	product.Availability = "discontinued"
+	assert.Error(t, product.Validate())
+}
+
+func TestMemoryRepository_InStockFilter(t *testing.T) {
+	repo, err := repository.NewMemoryRepository(sorting.NewSorterFactory())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, product := range stockTestProducts() {
+		product := product
+		require.NoError(t, repo.SaveProduct(ctx, &product))
+	}
+
+	filter := catalog.ProductFilter{InStockOnly: true}
+	assert.False(t, filter.IsEmpty())
+
+	products, err := repo.GetProducts(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, []catalog.ProductID{2, 3, 4, 6}, productIDs(products))
+
+	count, err := repo.GetProductCount(ctx, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
@@ -0,0 +1,192 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/httpapi"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+)
+
+func TestService_StreamSort_Comprehensive(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := generateLargeProductCollection(500)
+	ctx := context.Background()
+
+	for _, strategy := range catalog.AllSortStrategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			expected, err := service.SortProducts(ctx, products, strategy)
+			require.NoError(t, err)
+
+			t.Run("Full Output", func(t *testing.T) {
+				var emitted catalog.ProductCollection
+				result, err := service.StreamSort(ctx, catalog.NewCollectionIterator(products), strategy,
+					catalog.StreamOptions{}, collectInto(&emitted))
+				require.NoError(t, err)
+
+				assert.Equal(t, len(products), result.ProductCount)
+				assert.Equal(t, len(products), result.EmittedCount)
+				assert.Equal(t, productIDs(expected.Products), productIDs(emitted))
+			})
+
+			t.Run("Top K", func(t *testing.T) {
+				var emitted catalog.ProductCollection
+				result, err := service.StreamSort(ctx, catalog.NewCollectionIterator(products), strategy,
+					catalog.StreamOptions{TopK: 25}, collectInto(&emitted))
+				require.NoError(t, err)
+
+				assert.Equal(t, len(products), result.ProductCount)
+				assert.Equal(t, 25, result.EmittedCount)
+				assert.Equal(t, productIDs(expected.Products[:25]), productIDs(emitted))
+			})
+		})
+	}
+
+	t.Run("Channel Source", func(t *testing.T) {
+		source := make(chan catalog.Product)
+		go func() {
+			defer close(source)
+			for _, product := range products {
+				source <- product
+			}
+		}()
+
+		var emitted catalog.ProductCollection
+		result, err := service.StreamSort(ctx, catalog.NewChannelProductIterator(ctx, source),
+			catalog.SortByPriceAsc, catalog.StreamOptions{TopK: 3}, collectInto(&emitted))
+		require.NoError(t, err)
+
+		assert.Equal(t, len(products), result.ProductCount)
+		require.Len(t, emitted, 3)
+		assert.Equal(t, catalog.NewMoney(1000, catalog.USD), emitted[0].Price)
+	})
+
+	t.Run("NDJSON Round Trip", func(t *testing.T) {
+		var input, output bytes.Buffer
+		for _, product := range products[:10] {
+			require.NoError(t, json.NewEncoder(&input).Encode(product))
+		}
+
+		_, err := service.StreamSort(ctx, catalog.NewJSONProductIterator(&input), catalog.SortByName,
+			catalog.StreamOptions{}, catalog.NewNDJSONEmitter(&output))
+		require.NoError(t, err)
+
+		assert.Len(t, decodeJSONLProducts(t, output.String()), 10)
+	})
+
+	t.Run("Error Cases", func(t *testing.T) {
+		t.Run("Invalid Product", func(t *testing.T) {
+			invalid := append(products[:2].Copy(), catalog.Product{ID: 0, Name: ""})
+			_, err := service.StreamSort(ctx, catalog.NewCollectionIterator(invalid), catalog.SortByName,
+				catalog.StreamOptions{}, func(catalog.Product) error { return nil })
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "index 2")
+		})
+
+		t.Run("Malformed JSON", func(t *testing.T) {
+			_, err := service.StreamSort(ctx, catalog.NewJSONProductIterator(strings.NewReader("{not json}\n")),
+				catalog.SortByName, catalog.StreamOptions{}, func(catalog.Product) error { return nil })
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 1")
+		})
+
+		t.Run("Invalid Strategy", func(t *testing.T) {
+			_, err := service.StreamSort(ctx, catalog.NewCollectionIterator(products), "bogus",
+				catalog.StreamOptions{}, func(catalog.Product) error { return nil })
+			assert.Error(t, err)
+		})
+
+		t.Run("Negative Top K", func(t *testing.T) {
+			_, err := service.StreamSort(ctx, catalog.NewCollectionIterator(products), catalog.SortByName,
+				catalog.StreamOptions{TopK: -1}, func(catalog.Product) error { return nil })
+			assert.Error(t, err)
+		})
+
+		t.Run("Nil Emitter", func(t *testing.T) {
+			_, err := service.StreamSort(ctx, catalog.NewCollectionIterator(products), catalog.SortByName,
+				catalog.StreamOptions{}, nil)
+			assert.Error(t, err)
+		})
+
+		t.Run("Cancelled Context", func(t *testing.T) {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err := service.StreamSort(cancelled, catalog.NewCollectionIterator(products), catalog.SortByName,
+				catalog.StreamOptions{}, func(catalog.Product) error { return nil })
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	})
+}
+
+func TestHTTPHandler_StreamSort(t *testing.T) {
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	handler := httpapi.NewHandler(service, zap.NewNop())
+	products := generateLargeProductCollection(50)
+
+	var body bytes.Buffer
+	for _, product := range products {
+		require.NoError(t, json.NewEncoder(&body).Encode(product))
+	}
+
+	t.Run("Streams Top K", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/sort/stream?strategy=price_desc&top=5",
+			bytes.NewReader(body.Bytes()))
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+
+		sorted := decodeJSONLProducts(t, recorder.Body.String())
+		require.Len(t, sorted, 5)
+		assert.Equal(t, catalog.NewMoney(5900, catalog.USD), sorted[0].Price)
+	})
+
+	t.Run("Invalid Strategy", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/sort/stream?strategy=bogus",
+			bytes.NewReader(body.Bytes()))
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "invalid sort strategy")
+	})
+
+	t.Run("Method Not Allowed", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/sort/stream?strategy=name", nil)
+		recorder := httptest.NewRecorder()
+
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
+
+// collectInto returns an emitter that appends products to the given collection
+func collectInto(products *catalog.ProductCollection) catalog.ProductEmitter {
+	return func(product catalog.Product) error {
+		*products = append(*products, product)
+		return nil
+	}
+}
+
+// productIDs extracts product IDs in order
+func productIDs(products catalog.ProductCollection) []catalog.ProductID {
+	ids := make([]catalog.ProductID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	return ids
+}
@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"product-catalog-sorting/internal/domain/catalog"
+	"product-catalog-sorting/internal/infrastructure/sorting"
+	"product-catalog-sorting/test/testdata"
+)
+
+// variantTestProducts has a T-shirt (10) with three sizes, a mug (20) whose
+// parent record is absent, and a standalone poster (30)
+func variantTestProducts() catalog.ProductCollection {
+	products := catalog.ProductCollection{
+		testdata.Product(10, testdata.WithName("T-Shirt"), testdata.WithCategory("apparel"),
+			testdata.WithPrice(0), testdata.WithSales(0), testdata.WithViews(0)),
+		testdata.Product(11, testdata.WithParent(10), testdata.WithPrice(1500), testdata.WithSales(40), testdata.WithViews(400)),
+		testdata.Product(12, testdata.WithParent(10), testdata.WithPrice(1200), testdata.WithSales(90), testdata.WithViews(500),
+			testdata.WithStock(catalog.OutOfStock, 0)),
+		testdata.Product(13, testdata.WithParent(10), testdata.WithPrice(1800), testdata.WithSales(20), testdata.WithViews(300)),
+		testdata.Product(21, testdata.WithParent(20), testdata.WithPrice(900), testdata.WithSales(50)),
+		testdata.Product(22, testdata.WithParent(20), testdata.WithPrice(1100), testdata.WithSales(30), testdata.WithViews(800)),
+		testdata.Product(30, testdata.WithPrice(2500), testdata.WithSales(100), testdata.WithViews(900)),
+	}
+
+	// Stagger creation so the earliest variant is the lowest ID
+	created := time.Now().AddDate(0, -2, 0)
+	for i := range products {
+		products[i].CreatedAt = created.Add(time.Duration(products[i].ID) * time.Hour)
+	}
+	return products
+}
+
+func TestGroupVariants_Aggregate(t *testing.T) {
+	products := variantTestProducts()
+	groups, err := catalog.GroupVariants(products)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+
+	shirt := groups[0].Aggregate()
+	assert.Equal(t, catalog.ProductID(10), shirt.ID)
+	assert.Equal(t, "T-Shirt", shirt.Name)
+	assert.Equal(t, "apparel", shirt.Category)
+	assert.Equal(t, catalog.NewMoney(1200, catalog.USD), shirt.Price)
+	assert.Equal(t, 150, shirt.SalesCount)
+	assert.Equal(t, 1200, shirt.ViewsCount)
+	assert.Equal(t, catalog.InStock, shirt.Availability)
+	assert.Equal(t, products[1].CreatedAt, shirt.CreatedAt)
+	assert.NoError(t, shirt.Validate())
+
+	mug := groups[1].Aggregate()
+	assert.Equal(t, catalog.ProductID(20), mug.ID)
+	assert.False(t, mug.IsVariant())
+	assert.Equal(t, catalog.NewMoney(900, catalog.USD), mug.Price)
+	assert.Equal(t, 80, mug.SalesCount)
+
+	poster := groups[2]
+	assert.Empty(t, poster.Variants)
+	assert.Equal(t, products[6], poster.Aggregate())
+
+	t.Run("Mixed Currencies", func(t *testing.T) {
+		products := variantTestProducts()
+		products[5].Price = catalog.NewMoney(1100, catalog.EUR)
+		_, err := catalog.GroupVariants(products)
+		assert.ErrorIs(t, err, catalog.ErrCurrencyMismatch)
+	})
+
+	t.Run("Nested Variants", func(t *testing.T) {
+		products := variantTestProducts()
+		products[2].ParentID = 11
+		_, err := catalog.GroupVariants(products)
+		assert.ErrorIs(t, err, catalog.ErrNestedVariant)
+		assert.Contains(t, err.Error(), "product 12 has parent 11")
+	})
+
+	t.Run("Inactive Variants", func(t *testing.T) {
+		products := variantTestProducts()
+		products[4].Status = catalog.StatusDiscontinued
+		products[5].Status = catalog.StatusArchived
+		products[1].Status = catalog.StatusDraft
+
+		groups, err := catalog.GroupVariants(products)
+		require.NoError(t, err)
+		active := groups.FilterActive()
+		require.Len(t, active, 2)
+		assert.Equal(t, []catalog.ProductID{12, 13}, productIDs(active[0].Variants))
+		assert.Equal(t, catalog.ProductID(30), active[1].Parent.ID)
+	})
+}
+
+func TestProduct_ParentValidation(t *testing.T) {
+	product := variantTestProducts()[1]
+	require.NoError(t, product.Validate())
+
+	product.ParentID = product.ID
+	assert.Error(t, product.Validate())
+
+	product.ParentID = -1
+	assert.Error(t, product.Validate())
+}
+
+func TestService_SortVariants(t *testing.T) {
+	ctx := context.Background()
+	service := catalog.NewService(sorting.NewSorterFactory(), zap.NewNop())
+	products := variantTestProducts()
+
+	tests := []struct {
+		name     string
+		strategy catalog.SortStrategy
+		mode     catalog.VariantMode
+		expected []catalog.ProductID
+	}{
+		{"Flat", catalog.SortByPopularity, catalog.VariantsFlat, []catalog.ProductID{21, 30, 22, 12, 11, 13, 10}},
+		{"Parents By Views", catalog.SortByPopularity, catalog.VariantsParents, []catalog.ProductID{20, 10, 30}},
+		{"Parents By From Price", catalog.SortByPriceAsc, catalog.VariantsParents, []catalog.ProductID{20, 10, 30}},
+		{"Best Variant By Views", catalog.SortByPopularity, catalog.VariantsBest, []catalog.ProductID{21, 12, 30}},
+		{"Best Variant By Price", catalog.SortByPriceDesc, catalog.VariantsBest, []catalog.ProductID{30, 13, 22}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.SortProductsWithOptions(ctx, products, tt.strategy, catalog.SortOptions{Variants: tt.mode})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, productIDs(result.Products))
+		})
+	}
+
+	t.Run("Stock Policy Uses Aggregated Availability", func(t *testing.T) {
+		result, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPopularity, catalog.SortOptions{
+			Variants: catalog.VariantsBest,
+			Stock:    &catalog.StockPolicy{Mode: catalog.DemoteBury},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []catalog.ProductID{21, 30, 12}, productIDs(result.Products))
+	})
+
+	t.Run("Invalid Mode", func(t *testing.T) {
+		_, err := service.SortProductsWithOptions(ctx, products, catalog.SortByPopularity, catalog.SortOptions{Variants: "children"})
+		assert.Error(t, err)
+	})
+}